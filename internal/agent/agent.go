@@ -7,11 +7,46 @@ import (
 	"github.com/user/talk/internal/provider"
 )
 
+// Agent kinds
+const (
+	KindLLM   = "llm"   // Answers through its provider (default)
+	KindHuman = "human" // A person taking a panel seat; has no provider
+)
+
+// Visibility modes
+const (
+	VisibilityFull      = ""          // The whole transcript (default)
+	VisibilitySummaries = "summaries" // Only the debate's summaries
+	VisibilityBlind     = "blind"     // Only the topic
+)
+
+// Visibility limits which parts of the transcript an agent sees
+type Visibility struct {
+	Mode      string   `json:"mode,omitempty" yaml:"mode,omitempty"`
+	LastN     int      `json:"last_n,omitempty" yaml:"last_n,omitempty"`       // Only the last N messages, 0 for all
+	From      []string `json:"from,omitempty" yaml:"from,omitempty"`           // Only these agents' messages, plus its own and interjections
+	Anonymous bool     `json:"anonymous,omitempty" yaml:"anonymous,omitempty"` // Hide who wrote each message (Delphi rounds)
+}
+
+// Validate checks the mode is known and LastN is not negative
+func (v Visibility) Validate() error {
+	switch v.Mode {
+	case VisibilityFull, VisibilitySummaries, VisibilityBlind:
+	default:
+		return fmt.Errorf("unknown visibility mode %q", v.Mode)
+	}
+	if v.LastN < 0 {
+		return fmt.Errorf("visibility last_n must not be negative")
+	}
+	return nil
+}
+
 // Agent represents an AI agent with a specific role and provider
 type Agent struct {
 	ID               string            `json:"id" yaml:"id"`
 	Name             string            `json:"name" yaml:"name"`
 	Role             string            `json:"role" yaml:"role"`
+	Kind             string            `json:"kind,omitempty" yaml:"kind,omitempty"`
 	SystemPrompt     string            `json:"system_prompt" yaml:"system_prompt"`
 	ProviderType     string            `json:"provider_type" yaml:"provider_type"`
 	Model            string            `json:"model" yaml:"model"`
@@ -22,6 +57,7 @@ type Agent struct {
 	TopK             int               `json:"top_k" yaml:"top_k"`
 	FrequencyPenalty float64           `json:"frequency_penalty" yaml:"frequency_penalty"`
 	PresencePenalty  float64           `json:"presence_penalty" yaml:"presence_penalty"`
+	Visibility       Visibility        `json:"visibility" yaml:"visibility"`
 	Provider         provider.Provider `json:"-" yaml:"-"`
 }
 
@@ -30,6 +66,7 @@ type AgentConfig struct {
 	ID               string          `yaml:"id"`
 	Name             string          `yaml:"name"`
 	Role             string          `yaml:"role"`
+	Kind             string          `yaml:"kind"`
 	SystemPrompt     string          `yaml:"system_prompt"`
 	ProviderType     string          `yaml:"provider_type"`
 	Model            string          `yaml:"model"`
@@ -40,15 +77,51 @@ type AgentConfig struct {
 	TopK             int             `yaml:"top_k"`
 	FrequencyPenalty float64         `yaml:"frequency_penalty"`
 	PresencePenalty  float64         `yaml:"presence_penalty"`
+	Visibility       Visibility      `yaml:"visibility"`
 	ProviderConfig   provider.Config `yaml:"provider_config"`
 }
 
+// IsHuman reports whether a person, not a model, speaks for this agent
+func (a *Agent) IsHuman() bool {
+	return a.Kind == KindHuman
+}
+
 // Chat sends a message to the agent and returns a streaming response
 func (a *Agent) Chat(ctx context.Context, messages []provider.Message, opts provider.Options) (<-chan provider.StreamChunk, error) {
+	if a.IsHuman() {
+		return nil, fmt.Errorf("agent %s is a human participant", a.ID)
+	}
 	if a.Provider == nil {
 		return nil, fmt.Errorf("provider not initialized for agent %s", a.ID)
 	}
 
+	fullMessages, opts := a.resolve(messages, opts)
+	return a.Provider.Chat(ctx, fullMessages, opts)
+}
+
+// PreviewChat returns what Chat would send to the provider for messages,
+// without calling it: the messages with the system prompt, the resolved
+// options, and the provider's request when it can build one.
+func (a *Agent) PreviewChat(messages []provider.Message, opts provider.Options) ([]provider.Message, provider.Options, *provider.RequestPreview, error) {
+	if a.IsHuman() {
+		return nil, opts, nil, fmt.Errorf("agent %s is a human participant", a.ID)
+	}
+	if a.Provider == nil {
+		return nil, opts, nil, fmt.Errorf("provider not initialized for agent %s", a.ID)
+	}
+
+	fullMessages, opts := a.resolve(messages, opts)
+	p, ok := a.Provider.(provider.Previewer)
+	if !ok {
+		return fullMessages, opts, nil, nil
+	}
+	preview := p.Preview(fullMessages, opts)
+	return fullMessages, opts, &preview, nil
+}
+
+// resolve prepends the system prompt and fills unset options from the
+// agent's settings
+func (a *Agent) resolve(messages []provider.Message, opts provider.Options) ([]provider.Message, provider.Options) {
 	// Prepend system prompt
 	fullMessages := make([]provider.Message, 0, len(messages)+1)
 	if a.SystemPrompt != "" {
@@ -83,12 +156,29 @@ func (a *Agent) Chat(ctx context.Context, messages []provider.Message, opts prov
 	if opts.PresencePenalty == 0 && a.PresencePenalty != 0 {
 		opts.PresencePenalty = a.PresencePenalty
 	}
-
-	return a.Provider.Chat(ctx, fullMessages, opts)
+	return fullMessages, opts
 }
 
 // NewAgent creates a new agent from config
 func NewAgent(cfg AgentConfig) (*Agent, error) {
+	if err := cfg.Visibility.Validate(); err != nil {
+		return nil, fmt.Errorf("agent %s: %w", cfg.ID, err)
+	}
+
+	switch cfg.Kind {
+	case "", KindLLM:
+	case KindHuman:
+		return &Agent{
+			ID:    cfg.ID,
+			Name:  cfg.Name,
+			Role:  cfg.Role,
+			Kind:  KindHuman,
+			Color: cfg.Color,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown kind %q for agent %s", cfg.Kind, cfg.ID)
+	}
+
 	prov, err := provider.CreateProvider(cfg.ProviderConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create provider for agent %s: %w", cfg.ID, err)
@@ -108,10 +198,72 @@ func NewAgent(cfg AgentConfig) (*Agent, error) {
 		TopK:             cfg.TopK,
 		FrequencyPenalty: cfg.FrequencyPenalty,
 		PresencePenalty:  cfg.PresencePenalty,
+		Visibility:       cfg.Visibility,
 		Provider:         prov,
 	}, nil
 }
 
+// DefaultAgentsFor returns the default agents with prompts in a language
+// ("vi" or "en"); unknown languages get the Vietnamese set
+func DefaultAgentsFor(lang string) []AgentConfig {
+	if lang != "en" {
+		return DefaultAgents()
+	}
+	english := map[string][2]string{
+		"analyst": {"Logical, data-driven analysis", `You are Analyst - an expert in logical and data analysis.
+Your role:
+- Analyze the problem logically and systematically
+- Make arguments grounded in data and evidence
+- Weigh the different aspects of the problem
+- Answer briefly and concisely, focused on logic
+
+When taking part in the discussion:
+1. Analyze the previous opinions
+2. Give your analytical perspective
+3. Back it with logic and data where possible`},
+		"creative": {"Creative, brings new ideas", `You are Creative - an inventive and innovative thinker.
+Your role:
+- Bring new, original ideas
+- Think outside the usual frame
+- Look for creative solutions
+- Connect different ideas
+
+When taking part in the discussion:
+1. Offer a fresh perspective
+2. Propose creative ideas
+3. Widen the possibilities of the problem`},
+		"critic": {"Critiques, finds weaknesses", `You are Critic - a sharp reviewer.
+Your role:
+- Find weaknesses and gaps in the arguments
+- Ask questions that challenge assumptions
+- Give constructive criticism
+- Help strengthen and refine ideas
+
+When taking part in the discussion:
+1. Evaluate the opinions critically
+2. Point out what has not been considered
+3. Suggest how to fix the problems`},
+		"synthesizer": {"Synthesizes, finds common ground", `You are Synthesizer - a synthesizer and mediator.
+Your role:
+- Bring the different opinions together
+- Find common ground between the views
+- Build consensus
+- Propose integrated solutions
+
+When taking part in the discussion:
+1. Summarize the opinions given so far
+2. Find the strengths of each view
+3. Propose a combined way forward`},
+	}
+	agents := DefaultAgents()
+	for i := range agents {
+		if t, ok := english[agents[i].ID]; ok {
+			agents[i].Role, agents[i].SystemPrompt = t[0], t[1]
+		}
+	}
+	return agents
+}
+
 // DefaultAgents returns the default set of 4 agents
 func DefaultAgents() []AgentConfig {
 	return []AgentConfig{
@@ -199,6 +351,7 @@ type AgentInfo struct {
 	ID    string `json:"id"`
 	Name  string `json:"name"`
 	Role  string `json:"role"`
+	Kind  string `json:"kind,omitempty"`
 	Color string `json:"color"`
 }
 
@@ -208,6 +361,7 @@ func (a *Agent) Info() AgentInfo {
 		ID:    a.ID,
 		Name:  a.Name,
 		Role:  a.Role,
+		Kind:  a.Kind,
 		Color: a.Color,
 	}
 }
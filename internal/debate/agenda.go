@@ -0,0 +1,410 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ErrAgendaComplete is returned by turns once every agenda item has been discussed
+var ErrAgendaComplete = errors.New("every agenda item has been discussed")
+
+// Agenda item states
+const (
+	AgendaPending = "pending"
+	AgendaOpen    = "open"
+	AgendaDone    = "done"
+)
+
+// AgendaItem is one sub-topic of a meeting-style debate. The item closes when
+// either budget runs out; an item without a budget stays open until skipped.
+type AgendaItem struct {
+	ID        string `json:"id"`
+	Topic     string `json:"topic"`
+	Turns     int    `json:"turns,omitempty"`     // Turn budget, 0 = none
+	Seconds   int    `json:"seconds,omitempty"`   // Time budget, 0 = none
+	Summarize bool   `json:"summarize,omitempty"` // Summarize the item when it closes
+
+	State        string     `json:"state"`
+	TurnsTaken   int        `json:"turns_taken"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	EndedAt      *time.Time `json:"ended_at,omitempty"`
+	FirstMessage string     `json:"first_message,omitempty"` // Topic change message that opened the item
+	SummaryID    string     `json:"summary_id,omitempty"`
+}
+
+// AgendaItemUpdate changes an agenda item; nil fields are left as they are
+type AgendaItemUpdate struct {
+	Topic     *string `json:"topic"`
+	Turns     *int    `json:"turns"`
+	Seconds   *int    `json:"seconds"`
+	Summarize *bool   `json:"summarize"`
+}
+
+// AgendaStatus describes progress through the agenda
+type AgendaStatus struct {
+	Items       []AgendaItem `json:"items"`
+	Current     int          `json:"current"`                // Index of the open item, -1 if none
+	CurrentItem *AgendaItem  `json:"current_item,omitempty"` // The open item
+	TurnsLeft   int          `json:"turns_left,omitempty"`   // Left in the open item's turn budget
+	SecondsLeft int          `json:"seconds_left,omitempty"` // Left in the open item's time budget
+	Complete    bool         `json:"complete"`
+}
+
+// validate checks an item's topic and budgets
+func (item AgendaItem) validate() error {
+	if strings.TrimSpace(item.Topic) == "" {
+		return fmt.Errorf("agenda item topic is required")
+	}
+	if item.Turns < 0 || item.Seconds < 0 {
+		return fmt.Errorf("turns and seconds must not be negative")
+	}
+	return nil
+}
+
+// exhausted reports whether the item has used up one of its budgets
+func (item AgendaItem) exhausted(now time.Time) bool {
+	if item.Turns > 0 && item.TurnsTaken >= item.Turns {
+		return true
+	}
+	return item.Seconds > 0 && item.StartedAt != nil &&
+		now.Sub(*item.StartedAt) >= time.Duration(item.Seconds)*time.Second
+}
+
+// SetAgenda replaces the agenda items that have not been opened yet; items
+// already discussed, and the open one, are kept. Items are opened in order,
+// each becoming the debate's topic, as turns are played.
+func (m *Manager) SetAgenda(items []AgendaItem) error {
+	for _, item := range items {
+		if err := item.validate(); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	agenda := make([]AgendaItem, 0, len(items))
+	for _, item := range m.agenda {
+		if item.State != AgendaPending {
+			agenda = append(agenda, item)
+		}
+	}
+	for _, item := range items {
+		m.agendaCounter++
+		agenda = append(agenda, AgendaItem{
+			ID:        fmt.Sprintf("item_%d", m.agendaCounter),
+			Topic:     strings.TrimSpace(item.Topic),
+			Turns:     item.Turns,
+			Seconds:   item.Seconds,
+			Summarize: item.Summarize,
+			State:     AgendaPending,
+		})
+	}
+	m.agenda = agenda
+	if len(m.agenda) == 0 {
+		m.agenda = nil
+	}
+	m.mu.Unlock()
+
+	m.persist()
+	return nil
+}
+
+// ClearAgenda drops the agenda; the current topic stays
+func (m *Manager) ClearAgenda() {
+	m.mu.Lock()
+	m.agenda = nil
+	m.mu.Unlock()
+	m.persist()
+}
+
+// UpdateAgendaItem changes an item that has not been closed. The open item's
+// budgets can be changed, e.g. to give it more time, but not its topic.
+func (m *Manager) UpdateAgendaItem(id string, update AgendaItemUpdate) error {
+	m.mu.Lock()
+	i := m.agendaIndexLocked(id)
+	if i < 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("agenda item not found: %s", id)
+	}
+	item := m.agenda[i]
+	if item.State == AgendaDone {
+		m.mu.Unlock()
+		return fmt.Errorf("agenda item %s is already closed", id)
+	}
+	if update.Topic != nil {
+		if item.State == AgendaOpen {
+			m.mu.Unlock()
+			return fmt.Errorf("the open agenda item's topic cannot be changed")
+		}
+		item.Topic = strings.TrimSpace(*update.Topic)
+	}
+	if update.Turns != nil {
+		item.Turns = *update.Turns
+	}
+	if update.Seconds != nil {
+		item.Seconds = *update.Seconds
+	}
+	if update.Summarize != nil {
+		item.Summarize = *update.Summarize
+	}
+	if err := item.validate(); err != nil {
+		m.mu.Unlock()
+		return err
+	}
+	m.agenda[i] = item
+	m.mu.Unlock()
+
+	m.persist()
+	return nil
+}
+
+// RemoveAgendaItem removes an item that has not been opened yet
+func (m *Manager) RemoveAgendaItem(id string) error {
+	m.mu.Lock()
+	i := m.agendaIndexLocked(id)
+	if i < 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("agenda item not found: %s", id)
+	}
+	if m.agenda[i].State != AgendaPending {
+		m.mu.Unlock()
+		return fmt.Errorf("agenda item %s has already been opened", id)
+	}
+	m.agenda = append(m.agenda[:i], m.agenda[i+1:]...)
+	m.mu.Unlock()
+
+	m.persist()
+	return nil
+}
+
+// NextAgendaItem closes the open item now, summarizing it if asked to, and
+// opens the next one. It holds the turn lock, so no turn runs meanwhile.
+func (m *Manager) NextAgendaItem(streamCh chan<- StreamMessage) error {
+	m.mu.Lock()
+	if !m.isRunning {
+		m.mu.Unlock()
+		return ErrNotRunning
+	}
+	if m.isTurnInProgress {
+		m.mu.Unlock()
+		return ErrTurnInProgress
+	}
+	if len(m.agenda) == 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("no agenda set")
+	}
+	m.isTurnInProgress = true
+	ctx := m.ctx
+	m.mu.Unlock()
+
+	m.advanceAgenda(ctx, true, streamCh)
+
+	m.mu.Lock()
+	m.isTurnInProgress = false
+	m.mu.Unlock()
+	return nil
+}
+
+// GetAgenda returns progress through the agenda, nil if none is set
+func (m *Manager) GetAgenda() *AgendaStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.agendaStatusLocked()
+}
+
+// agendaStatusLocked builds the agenda status; callers must hold m.mu
+func (m *Manager) agendaStatusLocked() *AgendaStatus {
+	if len(m.agenda) == 0 {
+		return nil
+	}
+	status := &AgendaStatus{
+		Items:    append([]AgendaItem(nil), m.agenda...),
+		Current:  m.openAgendaLocked(),
+		Complete: true,
+	}
+	for _, item := range m.agenda {
+		status.Complete = status.Complete && item.State == AgendaDone
+	}
+	if status.Current >= 0 {
+		item := status.Items[status.Current]
+		status.CurrentItem = &item
+		if item.Turns > 0 {
+			status.TurnsLeft = item.Turns - item.TurnsTaken
+		}
+		if item.Seconds > 0 && item.StartedAt != nil {
+			left := time.Duration(item.Seconds)*time.Second - time.Since(*item.StartedAt)
+			if left > 0 {
+				status.SecondsLeft = int(left.Round(time.Second).Seconds())
+			}
+		}
+	}
+	return status
+}
+
+// agendaIndexLocked finds an agenda item by ID; callers must hold m.mu
+func (m *Manager) agendaIndexLocked(id string) int {
+	for i, item := range m.agenda {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// openAgendaLocked returns the open item's index, -1 if none; callers must hold m.mu
+func (m *Manager) openAgendaLocked() int {
+	for i, item := range m.agenda {
+		if item.State == AgendaOpen {
+			return i
+		}
+	}
+	return -1
+}
+
+// countAgendaTurnsLocked counts turns toward the open item's budget; callers
+// must hold m.mu
+func (m *Manager) countAgendaTurnsLocked(n int) {
+	if i := m.openAgendaLocked(); i >= 0 {
+		m.agenda[i].TurnsTaken += n
+	}
+}
+
+// restartAgendaLocked puts every item back to pending for a new debate;
+// callers must hold m.mu
+func (m *Manager) restartAgendaLocked() {
+	for i := range m.agenda {
+		m.agenda[i] = AgendaItem{
+			ID:        m.agenda[i].ID,
+			Topic:     m.agenda[i].Topic,
+			Turns:     m.agenda[i].Turns,
+			Seconds:   m.agenda[i].Seconds,
+			Summarize: m.agenda[i].Summarize,
+			State:     AgendaPending,
+		}
+	}
+}
+
+// remainingAgendaTurnsLocked counts the turns left in the agenda's turn
+// budgets, reporting false if an unfinished item has none; callers must hold m.mu
+func (m *Manager) remainingAgendaTurnsLocked() (int, bool) {
+	total := 0
+	for _, item := range m.agenda {
+		if item.State == AgendaDone {
+			continue
+		}
+		if item.Turns == 0 {
+			return 0, false
+		}
+		total += item.Turns - item.TurnsTaken
+	}
+	return total, true
+}
+
+// prepareAgendaTurn is run by turns before picking a speaker: it closes the
+// open item if its time ran out while idle and opens the next one
+func (m *Manager) prepareAgendaTurn(ctx context.Context, streamCh chan<- StreamMessage) error {
+	if m.advanceAgenda(ctx, false, streamCh) {
+		return ErrAgendaComplete
+	}
+	return nil
+}
+
+// advanceAgenda closes the open item when force is set or its budget ran
+// out, then opens the next pending item, making its topic the debate's. It
+// reports whether the whole agenda has been discussed.
+func (m *Manager) advanceAgenda(ctx context.Context, force bool, streamCh chan<- StreamMessage) bool {
+	m.mu.Lock()
+	if len(m.agenda) == 0 {
+		m.mu.Unlock()
+		return false
+	}
+	var closed *AgendaItem
+	if i := m.openAgendaLocked(); i >= 0 && (force || m.agenda[i].exhausted(time.Now())) {
+		now := time.Now()
+		m.agenda[i].State = AgendaDone
+		m.agenda[i].EndedAt = &now
+		item := m.agenda[i]
+		closed = &item
+	}
+	m.mu.Unlock()
+
+	if closed != nil && closed.Summarize {
+		summary, err := m.summarize(ctx, SummaryAgenda, closed, streamCh)
+		if err != nil {
+			log.Printf("Agenda summary for %s failed: %v", closed.ID, err)
+		} else {
+			m.mu.Lock()
+			if i := m.agendaIndexLocked(closed.ID); i >= 0 {
+				m.agenda[i].SummaryID = summary.ID
+			}
+			m.mu.Unlock()
+		}
+	}
+
+	m.mu.Lock()
+	var opened *AgendaItem
+	if m.openAgendaLocked() < 0 {
+		for i := range m.agenda {
+			if m.agenda[i].State != AgendaPending {
+				continue
+			}
+			now := time.Now()
+			item := &m.agenda[i]
+			item.State = AgendaOpen
+			item.StartedAt = &now
+			item.FirstMessage = m.changeTopicLocked(item.Topic)
+			opened = item
+			break
+		}
+	}
+	status := m.agendaStatusLocked()
+	var topic, itemID string
+	if opened != nil {
+		topic, itemID = opened.Topic, opened.ID
+	}
+	m.mu.Unlock()
+
+	if closed == nil && opened == nil {
+		return status != nil && status.Complete
+	}
+	m.persist()
+	if opened != nil {
+		m.emit(map[string]interface{}{
+			"type":        "topic_changed",
+			"topic":       topic,
+			"agenda_item": itemID,
+		})
+	}
+	m.emit(map[string]interface{}{
+		"type":   "agenda_changed",
+		"agenda": status,
+	})
+	return status.Complete
+}
+
+// agendaCounterFor returns the highest item number in an agenda, so items
+// added to a restored debate don't reuse IDs
+func agendaCounterFor(items []AgendaItem) int {
+	highest := 0
+	for _, item := range items {
+		var n int
+		if _, err := fmt.Sscanf(item.ID, "item_%d", &n); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest
+}
+
+// messagesFrom returns the transcript from the message with ID id on, the
+// whole transcript if id is empty or not found
+func messagesFrom(messages []Message, id string) []Message {
+	for i, msg := range messages {
+		if msg.ID == id {
+			return messages[i:]
+		}
+	}
+	return messages
+}
@@ -0,0 +1,62 @@
+package debate
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAgendaItemsCloseOnTurnBudget(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Họp định kỳ")
+	if err := m.SetAgenda([]AgendaItem{{Topic: "Ngân sách", Turns: 2}, {Topic: " Nhân sự ", Turns: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	nextTurns(t, m, 1)
+	status := m.GetAgenda()
+	if status.CurrentItem == nil || status.CurrentItem.ID != "item_1" || status.TurnsLeft != 1 || m.GetTopic() != "Ngân sách" {
+		t.Fatalf("status = %+v, topic %q; want item_1 open with 1 turn left", status, m.GetTopic())
+	}
+
+	// Using up the budget closes item_1 and opens item_2 with a topic change message
+	nextTurns(t, m, 2)
+	status = m.GetAgenda()
+	if status.Items[0].State != AgendaDone || status.CurrentItem == nil || status.CurrentItem.ID != "item_2" || m.GetTopic() != "Nhân sự" {
+		t.Fatalf("status = %+v, topic %q; want item_2 open", status, m.GetTopic())
+	}
+	msgs := m.GetMessages()
+	if len(msgs) != 4 || msgs[2].AgentID != "system" || status.CurrentItem.FirstMessage != msgs[2].ID {
+		t.Errorf("messages = %+v, want the topic change before item_2's turn", msgs)
+	}
+
+	nextTurns(t, m, 1)
+	if err := m.NextTurn(make(chan StreamMessage, 10)); !errors.Is(err, ErrAgendaComplete) {
+		t.Errorf("turn after the last item = %v, want ErrAgendaComplete", err)
+	}
+	if status := m.GetAgenda(); !status.Complete || status.Current != -1 {
+		t.Errorf("status = %+v, want complete", status)
+	}
+}
+
+func TestSetAgendaKeepsOpenItems(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Họp định kỳ")
+	if err := m.SetAgenda([]AgendaItem{{Topic: "Ngân sách"}, {Topic: "Nhân sự"}}); err != nil {
+		t.Fatal(err)
+	}
+	nextTurns(t, m, 1)
+
+	if err := m.SetAgenda([]AgendaItem{{Topic: "Tuyển dụng", Seconds: 60}}); err != nil {
+		t.Fatal(err)
+	}
+	items := m.GetAgenda().Items
+	if len(items) != 2 || items[0].ID != "item_1" || items[0].State != AgendaOpen || items[1].ID != "item_3" || items[1].Topic != "Tuyển dụng" {
+		t.Errorf("items = %+v, want the open item kept and the pending one replaced", items)
+	}
+
+	for _, bad := range []AgendaItem{{Topic: " "}, {Topic: "x", Turns: -1}} {
+		if err := m.SetAgenda([]AgendaItem{bad}); err == nil {
+			t.Errorf("accepted agenda item %+v", bad)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package debate
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MainBranch is the ID of the branch every debate starts on
+const MainBranch = "main"
+
+// Branch is an alternative continuation of a debate, forked after a message.
+// The active branch's state lives in the Manager; Messages and the fields
+// after it hold the state of inactive branches.
+type Branch struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Parent     string       `json:"parent,omitempty"`      // Branch it was forked from
+	ForkedFrom string       `json:"forked_from,omitempty"` // Last message shared with the parent
+	CreatedAt  time.Time    `json:"created_at"`
+	Messages   []Message    `json:"messages,omitempty"`
+	Topic      string       `json:"topic,omitempty"`
+	Summaries  []Summary    `json:"summaries,omitempty"`
+	Verdict    *Verdict     `json:"verdict,omitempty"`
+	Ended      *Termination `json:"ended,omitempty"`
+	PhaseIndex int          `json:"phase_index,omitempty"`
+	PhaseTurn  int          `json:"phase_turn,omitempty"`
+	Agenda     []AgendaItem `json:"agenda,omitempty"`
+}
+
+// BranchInfo describes a branch for listing
+type BranchInfo struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Parent       string    `json:"parent,omitempty"`
+	ForkedFrom   string    `json:"forked_from,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	MessageCount int       `json:"message_count"`
+	Active       bool      `json:"active"`
+}
+
+// appendMessageLocked appends a message to the active branch, linking it to
+// the message before it; callers must hold m.mu
+func (m *Manager) appendMessageLocked(msg Message) {
+	if len(m.messages) > 0 {
+		msg.ParentID = m.messages[len(m.messages)-1].ID
+	}
+	m.messages = append(m.messages, msg)
+}
+
+// ensureBranchesLocked sets up the main branch on first use; callers must hold m.mu
+func (m *Manager) ensureBranchesLocked() {
+	if m.branches != nil {
+		return
+	}
+	m.branch = MainBranch
+	m.branches = map[string]*Branch{
+		MainBranch: {ID: MainBranch, Name: renderPrompt(m.promptSet, "main_branch", PromptData{}), CreatedAt: m.createdAt},
+	}
+}
+
+// branchMessagesLocked returns a branch's transcript; callers must hold m.mu
+func (m *Manager) branchMessagesLocked(id string) ([]Message, bool) {
+	if id == "" || id == m.activeBranchLocked() {
+		return m.messages, true
+	}
+	b, ok := m.branches[id]
+	if !ok {
+		return nil, false
+	}
+	return b.Messages, true
+}
+
+// activeBranchLocked returns the active branch ID; callers must hold m.mu
+func (m *Manager) activeBranchLocked() string {
+	if m.branch == "" {
+		return MainBranch
+	}
+	return m.branch
+}
+
+// findForkPointLocked finds the branch holding messageID and the message's
+// index in it, preferring the active branch; callers must hold m.mu
+func (m *Manager) findForkPointLocked(messageID string) (string, int, bool) {
+	ids := []string{m.activeBranchLocked()}
+	for id := range m.branches {
+		if id != m.activeBranchLocked() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids[1:])
+
+	for _, id := range ids {
+		messages, _ := m.branchMessagesLocked(id)
+		for i, msg := range messages {
+			if msg.ID == messageID {
+				return id, i, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// Fork creates a branch that shares the transcript up to and including
+// messageID and continues independently. With switchTo the new branch becomes
+// active, so the next turn continues it.
+func (m *Manager) Fork(messageID, name string, switchTo bool) (*BranchInfo, error) {
+	m.mu.Lock()
+	if m.isTurnInProgress {
+		m.mu.Unlock()
+		return nil, ErrTurnInProgress
+	}
+	m.ensureBranchesLocked()
+
+	source, idx, ok := m.findForkPointLocked(messageID)
+	if !ok {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("message not found: %s", messageID)
+	}
+
+	m.branchCounter++
+	id := fmt.Sprintf("branch_%d", m.branchCounter)
+	if name == "" {
+		name = renderPrompt(m.promptSet, "branch_name", PromptData{Count: m.branchCounter})
+	}
+
+	// The new branch continues from the source branch's topic, format phase
+	// and agenda, keeping the summaries of the shared prefix
+	state := m.branchStateLocked(source)
+	prefix := make([]Message, idx+1)
+	copy(prefix, state.Messages[:idx+1])
+	shared := make(map[string]bool, len(prefix))
+	for _, msg := range prefix {
+		shared[msg.ID] = true
+	}
+	branch := &Branch{
+		ID:         id,
+		Name:       name,
+		Parent:     source,
+		ForkedFrom: messageID,
+		CreatedAt:  time.Now(),
+		Messages:   prefix,
+		Topic:      state.Topic,
+		PhaseIndex: state.PhaseIndex,
+		PhaseTurn:  state.PhaseTurn,
+		Agenda:     append([]AgendaItem(nil), state.Agenda...),
+	}
+	for _, s := range state.Summaries {
+		if shared[s.UpTo] {
+			branch.Summaries = append(branch.Summaries, s)
+		}
+	}
+
+	// Record the child on the fork point in the source branch only, after
+	// copying the prefix so the new branch doesn't list itself
+	state.Messages[idx].Children = append(append([]string(nil), state.Messages[idx].Children...), id)
+	m.branches[id] = branch
+	if switchTo {
+		m.switchBranchLocked(id)
+	}
+	info := m.branchInfoLocked(branch)
+	m.mu.Unlock()
+
+	m.persist()
+	m.logBranchState()
+	return &info, nil
+}
+
+// SwitchBranch makes another branch active
+func (m *Manager) SwitchBranch(id string) error {
+	m.mu.Lock()
+	if m.isTurnInProgress {
+		m.mu.Unlock()
+		return ErrTurnInProgress
+	}
+	m.ensureBranchesLocked()
+	if _, ok := m.branches[id]; !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("branch not found: %s", id)
+	}
+	m.switchBranchLocked(id)
+	m.mu.Unlock()
+
+	m.persist()
+	m.logBranchState()
+	return nil
+}
+
+// logBranchState logs the whole state after a fork or switch, so replay
+// continues the branch that is now active
+func (m *Manager) logBranchState() {
+	m.mu.RLock()
+	id, snap := m.debateID, m.snapshotLocked()
+	m.mu.RUnlock()
+	m.logEvent(id, map[string]interface{}{
+		"type":     "branch_state",
+		"snapshot": snap,
+	})
+}
+
+// switchBranchLocked stores the active branch's state in its Branch and loads
+// branch id's; callers must hold m.mu
+func (m *Manager) switchBranchLocked(id string) {
+	current := m.activeBranchLocked()
+	if id == current {
+		return
+	}
+	*m.branches[current] = m.branchStateLocked(current)
+
+	next := m.branches[id]
+	m.messages = next.Messages
+	if m.messages == nil {
+		m.messages = make([]Message, 0)
+	}
+	if next.Topic != "" {
+		m.topic = next.Topic
+	}
+	m.summaries = next.Summaries
+	m.verdict = next.Verdict
+	m.ended = next.Ended
+	m.phaseIndex, m.phaseTurn = next.PhaseIndex, next.PhaseTurn
+	m.agenda = next.Agenda
+	m.branch = id
+	*next = Branch{ID: next.ID, Name: next.Name, Parent: next.Parent, ForkedFrom: next.ForkedFrom, CreatedAt: next.CreatedAt}
+}
+
+// branchStateLocked returns a branch with its state filled in, taking the
+// active branch's state from the manager; callers must hold m.mu
+func (m *Manager) branchStateLocked(id string) Branch {
+	b := *m.branches[id]
+	if id != m.activeBranchLocked() {
+		return b
+	}
+	b.Messages = m.messages
+	b.Topic = m.topic
+	b.Summaries = m.summaries
+	b.Verdict = m.verdict
+	b.Ended = m.ended
+	b.PhaseIndex, b.PhaseTurn = m.phaseIndex, m.phaseTurn
+	b.Agenda = m.agenda
+	return b
+}
+
+// GetBranch returns the active branch ID
+func (m *Manager) GetBranch() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.activeBranchLocked()
+}
+
+// ListBranches returns all branches, oldest first
+func (m *Manager) ListBranches() []BranchInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.branches == nil {
+		return []BranchInfo{{
+			ID:           MainBranch,
+			Name:         renderPrompt(m.promptSet, "main_branch", PromptData{}),
+			CreatedAt:    m.createdAt,
+			MessageCount: len(m.messages),
+			Active:       true,
+		}}
+	}
+
+	result := make([]BranchInfo, 0, len(m.branches))
+	for _, b := range m.branches {
+		result = append(result, m.branchInfoLocked(b))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result
+}
+
+// GetBranchMessages returns a copy of a branch's transcript (empty ID = active branch)
+func (m *Manager) GetBranchMessages(id string) ([]Message, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	messages, ok := m.branchMessagesLocked(id)
+	if !ok {
+		return nil, fmt.Errorf("branch not found: %s", id)
+	}
+	result := make([]Message, len(messages))
+	copy(result, messages)
+	return result, nil
+}
+
+// ForkSnapshot returns a snapshot of a new debate holding the active branch
+// up to and including messageID, for continuing in another session
+func (m *Manager) ForkSnapshot(messageID string) (Snapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i, msg := range m.messages {
+		if msg.ID != messageID {
+			continue
+		}
+		snap := m.snapshotLocked()
+		snap.ID = newDebateID()
+		snap.CreatedAt = time.Now()
+		snap.Messages = append([]Message(nil), m.messages[:i+1]...)
+		snap.Branch, snap.Branches = "", nil
+		snap.Summaries, snap.Verdict, snap.Ended = nil, nil, nil
+		return snap, nil
+	}
+	return Snapshot{}, fmt.Errorf("message not found: %s", messageID)
+}
+
+// branchInfoLocked describes a branch; callers must hold m.mu
+func (m *Manager) branchInfoLocked(b *Branch) BranchInfo {
+	messages, _ := m.branchMessagesLocked(b.ID)
+	return BranchInfo{
+		ID:           b.ID,
+		Name:         b.Name,
+		Parent:       b.Parent,
+		ForkedFrom:   b.ForkedFrom,
+		CreatedAt:    b.CreatedAt,
+		MessageCount: len(messages),
+		Active:       b.ID == m.activeBranchLocked(),
+	}
+}
@@ -0,0 +1,203 @@
+package debate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/talk/internal/agent"
+)
+
+func TestForkContinuesFromForkPoint(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Chủ đề")
+	nextTurns(t, m, 3)
+
+	branch, err := m.Fork("msg_1", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !branch.Active || branch.Parent != MainBranch || branch.MessageCount != 1 {
+		t.Fatalf("branch = %+v, want the active fork of main after msg_1", branch)
+	}
+	nextTurns(t, m, 1)
+
+	msgs := m.GetMessages()
+	if got := messageIDs(msgs); !reflect.DeepEqual(got, []string{"msg_1", "msg_4"}) {
+		t.Fatalf("fork transcript = %v, want [msg_1 msg_4]", got)
+	}
+	if len(msgs[0].Children) != 0 {
+		t.Errorf("the fork's own copy of msg_1 lists children %v", msgs[0].Children)
+	}
+	if msgs[1].ParentID != "msg_1" {
+		t.Errorf("msg_4 parent = %q, want msg_1", msgs[1].ParentID)
+	}
+
+	main, err := m.GetBranchMessages(MainBranch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := messageIDs(main); !reflect.DeepEqual(got, []string{"msg_1", "msg_2", "msg_3"}) {
+		t.Fatalf("main transcript = %v", got)
+	}
+	if !reflect.DeepEqual(main[0].Children, []string{branch.ID}) {
+		t.Errorf("main msg_1 children = %v, want [%s]", main[0].Children, branch.ID)
+	}
+
+	if err := m.SwitchBranch(MainBranch); err != nil {
+		t.Fatal(err)
+	}
+	if got := messageIDs(m.GetMessages()); !reflect.DeepEqual(got, []string{"msg_1", "msg_2", "msg_3"}) {
+		t.Errorf("after switching back, transcript = %v", got)
+	}
+}
+
+func TestBranchesKeepTheirOwnState(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Chủ đề")
+	nextTurns(t, m, 3)
+
+	m.mu.Lock()
+	m.summaries = []Summary{
+		{ID: "sum_1", Kind: SummaryRolling, Content: "tóm tắt đầu", UpTo: "msg_1"},
+		{ID: "sum_2", Kind: SummaryRolling, Content: "tóm tắt sau", UpTo: "msg_3"},
+	}
+	m.verdict = &Verdict{Judge: "Giám khảo", Winner: "a1", WinnerName: "Alpha", Rationale: "lập luận chặt"}
+	m.ended = &Termination{Reason: "max_turns"}
+	m.mu.Unlock()
+
+	branch, err := m.Fork("msg_1", "Thử nghiệm", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.GetSummaries(); len(got) != 1 || got[0].ID != "sum_1" {
+		t.Errorf("fork summaries = %+v, want only the one covering the shared prefix", got)
+	}
+	if m.GetVerdict() != nil || m.GetEnded() != nil {
+		t.Errorf("fork inherited verdict %+v / ended %+v", m.GetVerdict(), m.GetEnded())
+	}
+
+	md, err := m.ExportBranchMarkdown(MainBranch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(md, "tóm tắt sau") || !strings.Contains(md, "lập luận chặt") {
+		t.Errorf("main export lost its summaries or verdict:\n%s", md)
+	}
+	md, err = m.ExportBranchMarkdown(branch.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(md, "tóm tắt sau") || strings.Contains(md, "lập luận chặt") {
+		t.Errorf("fork export shows main's summaries or verdict:\n%s", md)
+	}
+
+	if err := m.SwitchBranch(MainBranch); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.GetSummaries(); len(got) != 2 {
+		t.Errorf("main summaries after switching back = %+v", got)
+	}
+	if v := m.GetVerdict(); v == nil || v.Winner != "a1" {
+		t.Errorf("main verdict after switching back = %+v", v)
+	}
+	if e := m.GetEnded(); e == nil || e.Reason != "max_turns" {
+		t.Errorf("main ended after switching back = %+v", e)
+	}
+}
+
+func TestRestoreKeepsBranches(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Chủ đề")
+	nextTurns(t, m, 2)
+	m.mu.Lock()
+	m.verdict = &Verdict{Winner: "a2"}
+	m.mu.Unlock()
+	branch, err := m.Fork("msg_1", "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nextTurns(t, m, 1)
+	snap := m.Snapshot()
+
+	restored, _ := newTestManager(t)
+	if err := restored.Restore(snap); err != nil {
+		t.Fatal(err)
+	}
+	if got := restored.GetBranch(); got != branch.ID {
+		t.Errorf("active branch = %q, want %q", got, branch.ID)
+	}
+	if got := messageIDs(restored.GetMessages()); !reflect.DeepEqual(got, []string{"msg_1", "msg_3"}) {
+		t.Errorf("restored transcript = %v", got)
+	}
+	if err := restored.SwitchBranch(MainBranch); err != nil {
+		t.Fatal(err)
+	}
+	if got := messageIDs(restored.GetMessages()); !reflect.DeepEqual(got, []string{"msg_1", "msg_2"}) {
+		t.Errorf("restored main transcript = %v", got)
+	}
+	if v := restored.GetVerdict(); v == nil || v.Winner != "a2" {
+		t.Errorf("restored main verdict = %+v", v)
+	}
+	if next, err := restored.Fork("msg_2", "", false); err != nil || next.ID != "branch_2" {
+		t.Errorf("fork after restore = %+v, %v; want branch_2", next, err)
+	}
+}
+
+func TestSessionsForkKeepsPanelAndMode(t *testing.T) {
+	p := &scriptProvider{}
+	sessions := NewSessions([]*agent.Agent{testAgent("a1", "Alpha", p), testAgent("a2", "Beta", p)})
+	sess, err := sessions.Create("Gốc", nil, ModeRoundRobin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guest := testAgent("g1", "Khách", p)
+	if _, err := sessions.SetPanel(sess.ID, []*agent.Agent{guest}); err != nil {
+		t.Fatal(err)
+	}
+	opts := SelectorOptions{Weights: map[string]float64{"g1": 2}}
+	if err := sess.Manager.SetMode(ModeWeightedRandom, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	forked, err := sessions.Fork(sess.ID, "Nhánh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := forked.Info()
+	if !info.Temporary || len(info.Agents) != 1 || info.Agents[0].ID != "g1" {
+		t.Errorf("forked agents = %+v (temporary %v), want the temporary panel", info.Agents, info.Temporary)
+	}
+	if info.Name != "Nhánh" {
+		t.Errorf("forked name = %q", info.Name)
+	}
+	if forked.Manager.GetMode() != ModeWeightedRandom || !reflect.DeepEqual(forked.Manager.GetModeOptions(), opts) {
+		t.Errorf("forked mode = %s %+v", forked.Manager.GetMode(), forked.Manager.GetModeOptions())
+	}
+}
+
+func TestSessionsForkNamesInPromptSet(t *testing.T) {
+	sessions := NewSessions([]*agent.Agent{testAgent("a1", "Alpha", &scriptProvider{})})
+	sess, err := sessions.Create("Thuế", nil, ModeRoundRobin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forked, err := sessions.Fork(sess.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := forked.Info().Name; got != "Thuế (nhánh)" {
+		t.Errorf("vi fork name = %q", got)
+	}
+
+	if err := sess.Manager.SetPromptSet("en"); err != nil {
+		t.Fatal(err)
+	}
+	forked, err = sessions.Fork(sess.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := forked.Info().Name; got != "Thuế (fork)" {
+		t.Errorf("en fork name = %q", got)
+	}
+}
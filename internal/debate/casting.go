@@ -0,0 +1,200 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/user/talk/internal/agent"
+)
+
+const (
+	// DefaultCastSize is the panel size when none is requested
+	DefaultCastSize = 4
+	// MaxCastSize bounds how many agents one cast may propose
+	MaxCastSize = 8
+)
+
+// castColors are assigned when a proposal has no usable color
+var castColors = []string{"#3B82F6", "#EF4444", "#10B981", "#F59E0B", "#8B5CF6", "#EC4899", "#14B8A6", "#F97316"}
+
+var (
+	castColorRegex = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+	castSlugRegex  = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// CastProposal is one agent proposed for a panel
+type CastProposal struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	Role              string `json:"role"`
+	SystemPrompt      string `json:"system_prompt"`
+	Color             string `json:"color"`
+	Provider          string `json:"provider"`
+	SuggestedProvider string `json:"suggested_provider,omitempty"` // Set when the model's choice had no key and Provider replaced it
+}
+
+// castResponse is the JSON shape the caster is asked to return
+type castResponse struct {
+	Agents []CastProposal `json:"agents"`
+}
+
+// CastPanel asks caster to propose count agents for topic. available lists the
+// providers that have a usable key (preferred first); suggestions outside it are
+// replaced. taken holds agent IDs the proposals must not reuse. The request is
+// written with the prompt set setID.
+func CastPanel(ctx context.Context, caster *agent.Agent, setID, topic string, count int, available, taken []string) ([]CastProposal, error) {
+	if strings.TrimSpace(topic) == "" {
+		return nil, fmt.Errorf("topic is required")
+	}
+	if len(available) == 0 {
+		return nil, fmt.Errorf("no provider has an API key configured")
+	}
+	if count <= 0 {
+		count = DefaultCastSize
+	}
+	if count > MaxCastSize {
+		return nil, fmt.Errorf("count must be at most %d", MaxCastSize)
+	}
+
+	system := renderPrompt(setID, "cast_system", PromptData{})
+	resp, err := askAgent(ctx, caster, system, castPrompt(setID, topic, count, available))
+	if err != nil {
+		return nil, err
+	}
+	var parsed castResponse
+	if err := extractJSON(resp, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid cast response: %w", err)
+	}
+
+	proposals := normalizeCast(parsed.Agents, available, taken)
+	if len(proposals) == 0 {
+		return nil, fmt.Errorf("caster proposed no usable agents")
+	}
+	if len(proposals) > count {
+		proposals = proposals[:count]
+	}
+	return proposals, nil
+}
+
+// ValidateCast checks a (possibly user-edited) panel before it is used:
+// names and prompts present, IDs unique slugs, colors hex and providers available.
+func ValidateCast(proposals []CastProposal, available []string) error {
+	if len(proposals) == 0 {
+		return fmt.Errorf("panel is empty")
+	}
+	if len(proposals) > MaxCastSize {
+		return fmt.Errorf("panel must have at most %d agents", MaxCastSize)
+	}
+	seen := make(map[string]bool, len(proposals))
+	for _, p := range proposals {
+		if p.ID == "" || p.ID != castSlug(p.ID) {
+			return fmt.Errorf("invalid agent id: %q", p.ID)
+		}
+		if seen[p.ID] {
+			return fmt.Errorf("duplicate agent id: %s", p.ID)
+		}
+		seen[p.ID] = true
+		if strings.TrimSpace(p.Name) == "" || strings.TrimSpace(p.SystemPrompt) == "" {
+			return fmt.Errorf("agent %s needs a name and system prompt", p.ID)
+		}
+		if !castColorRegex.MatchString(p.Color) {
+			return fmt.Errorf("agent %s has an invalid color: %q", p.ID, p.Color)
+		}
+		if !containsString(available, p.Provider) {
+			return fmt.Errorf("agent %s: provider %q has no API key configured", p.ID, p.Provider)
+		}
+	}
+	return nil
+}
+
+// normalizeCast repairs model output: drops incomplete entries, derives unique
+// IDs, fills colors and swaps unavailable providers for the first available one
+func normalizeCast(proposals []CastProposal, available, taken []string) []CastProposal {
+	used := make(map[string]bool, len(taken)+len(proposals))
+	for _, id := range taken {
+		used[id] = true
+	}
+
+	result := make([]CastProposal, 0, len(proposals))
+	for _, p := range proposals {
+		p.Name = strings.TrimSpace(p.Name)
+		p.Role = strings.TrimSpace(p.Role)
+		p.SystemPrompt = strings.TrimSpace(p.SystemPrompt)
+		if p.Name == "" || p.SystemPrompt == "" {
+			continue
+		}
+
+		base := castSlug(p.ID)
+		if base == "" {
+			base = castSlug(p.Name)
+		}
+		if base == "" {
+			base = "agent"
+		}
+		p.ID = base
+		for n := 2; used[p.ID]; n++ {
+			p.ID = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[p.ID] = true
+
+		if !castColorRegex.MatchString(p.Color) {
+			p.Color = castColors[len(result)%len(castColors)]
+		}
+
+		p.Provider = strings.ToLower(strings.TrimSpace(p.Provider))
+		p.SuggestedProvider = ""
+		if !containsString(available, p.Provider) {
+			p.SuggestedProvider = p.Provider
+			p.Provider = available[0]
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// vietnameseFold maps accented Vietnamese letters to their ASCII base for IDs
+var vietnameseFold = func() map[rune]rune {
+	groups := map[rune]string{
+		'a': "àáạảãâầấậẩẫăằắặẳẵ",
+		'e': "èéẹẻẽêềếệểễ",
+		'i': "ìíịỉĩ",
+		'o': "òóọỏõôồốộổỗơờớợởỡ",
+		'u': "ùúụủũưừứựửữ",
+		'y': "ỳýỵỷỹ",
+		'd': "đ",
+	}
+	fold := make(map[rune]rune)
+	for base, accented := range groups {
+		for _, r := range accented {
+			fold[r] = base
+		}
+	}
+	return fold
+}()
+
+// castSlug lowercases s, folds Vietnamese accents and keeps only ASCII letters
+// and digits joined by underscores
+func castSlug(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if base, ok := vietnameseFold[r]; ok {
+			return base
+		}
+		return r
+	}, strings.ToLower(s))
+	return strings.Trim(castSlugRegex.ReplaceAllString(s, "_"), "_")
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func castPrompt(setID, topic string, count int, available []string) string {
+	return renderPrompt(setID, "cast", PromptData{Topic: topic, Count: count, Providers: strings.Join(available, ", ")})
+}
@@ -0,0 +1,56 @@
+package debate
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCastPanelRepairsProposals(t *testing.T) {
+	caster := testAgent("c", "Caster", replyProvider{reply: `{"agents": [
+  {"name": "Nhà Kinh Tế", "system_prompt": "Phân tích chi phí.", "color": "đỏ", "provider": " OpenAI "},
+  {"id": "nha_kinh_te", "name": "Nhà kinh tế 2", "system_prompt": "Phản biện.", "color": "#112233", "provider": "grok"},
+  {"name": "Thiếu prompt", "system_prompt": " "},
+  {"id": "critic", "name": "Critic", "system_prompt": "Chỉ ra lỗ hổng.", "provider": "openai"}
+]}`})
+
+	proposals, err := CastPanel(context.Background(), caster, "vi", "Thuế carbon", 2, []string{"openai", "claude"}, []string{"critic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(proposals) != 2 {
+		t.Fatalf("proposals = %+v, want the first 2 usable ones", proposals)
+	}
+	first, second := proposals[0], proposals[1]
+	if first.ID != "nha_kinh_te" || first.Color != castColors[0] || first.Provider != "openai" || first.SuggestedProvider != "" {
+		t.Errorf("first = %+v", first)
+	}
+	if second.ID != "nha_kinh_te_2" || second.Color != "#112233" || second.Provider != "openai" || second.SuggestedProvider != "grok" {
+		t.Errorf("second = %+v", second)
+	}
+	if err := ValidateCast(proposals, []string{"openai"}); err != nil {
+		t.Errorf("repaired panel fails validation: %v", err)
+	}
+
+	if _, err := CastPanel(context.Background(), caster, "vi", "Thuế carbon", MaxCastSize+1, []string{"openai"}, nil); err == nil {
+		t.Error("accepted a cast larger than MaxCastSize")
+	}
+	if _, err := CastPanel(context.Background(), caster, "vi", "Thuế carbon", 2, nil, nil); err == nil {
+		t.Error("cast without any provider available")
+	}
+}
+
+func TestValidateCast(t *testing.T) {
+	ok := CastProposal{ID: "critic", Name: "Critic", SystemPrompt: "p", Color: "#112233", Provider: "openai"}
+	for _, bad := range [][]CastProposal{
+		nil,
+		{ok, ok},
+		{func() CastProposal { p := ok; p.ID = "Nhà Phê Bình"; return p }()},
+		{func() CastProposal { p := ok; p.Color = "red"; return p }()},
+		{func() CastProposal { p := ok; p.Provider = "grok"; return p }()},
+		{func() CastProposal { p := ok; p.SystemPrompt = " "; return p }()},
+	} {
+		if err := ValidateCast(bad, []string{"openai"}); err == nil {
+			t.Errorf("ValidateCast(%+v) accepted an invalid panel", bad)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package debate
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Actions reported by message_updated events and kept in edit history
+const (
+	EditActionEdited      = "edited"
+	EditActionRegenerated = "regenerated"
+	EditActionDeleted     = "deleted"
+)
+
+// MessageEdit is a previous version of a message, kept when it is edited or regenerated
+type MessageEdit struct {
+	Action     string    `json:"action"` // EditActionEdited or EditActionRegenerated
+	AgentID    string    `json:"agent_id"`
+	AgentName  string    `json:"agent_name"`
+	Content    string    `json:"content"`
+	Timestamp  time.Time `json:"timestamp"` // When the previous version was written
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// EditMessage replaces a message's content with a human correction. The
+// previous version is kept in the message's edit history.
+func (m *Manager) EditMessage(id, content string) (*Message, error) {
+	m.mu.Lock()
+	if m.isTurnInProgress {
+		m.mu.Unlock()
+		return nil, ErrTurnInProgress
+	}
+	idx := m.messageIndexLocked(id)
+	if idx < 0 {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("message not found: %s", id)
+	}
+	msg := &m.messages[idx]
+	msg.Edits = withEdit(*msg, EditActionEdited)
+	msg.Content = content
+	updated := *msg
+	m.mu.Unlock()
+
+	m.persist()
+	m.emitMessageUpdated(EditActionEdited, &updated, id)
+	return &updated, nil
+}
+
+// DeleteMessage removes a message from the active branch, so later turns no
+// longer see it. The following message is relinked to the deleted one's parent.
+func (m *Manager) DeleteMessage(id string) error {
+	m.mu.Lock()
+	if m.isTurnInProgress {
+		m.mu.Unlock()
+		return ErrTurnInProgress
+	}
+	idx := m.messageIndexLocked(id)
+	if idx < 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("message not found: %s", id)
+	}
+	if idx+1 < len(m.messages) {
+		m.messages[idx+1].ParentID = m.messages[idx].ParentID
+	}
+	m.messages = append(m.messages[:idx:idx], m.messages[idx+1:]...)
+	m.mu.Unlock()
+
+	m.persist()
+	m.emitMessageUpdated(EditActionDeleted, nil, id)
+	return nil
+}
+
+// RegenerateMessage has an agent rewrite a message from the context that
+// preceded it, streaming the new version under the same message ID. An empty
+// agentID keeps the original author. The previous version is kept in the
+// message's edit history.
+func (m *Manager) RegenerateMessage(id, agentID string, streamCh chan<- StreamMessage) (*Message, error) {
+	m.mu.Lock()
+	if !m.isRunning {
+		m.mu.Unlock()
+		return nil, ErrNotRunning
+	}
+	if m.isTurnInProgress {
+		m.mu.Unlock()
+		return nil, ErrTurnInProgress
+	}
+	idx := m.messageIndexLocked(id)
+	if idx < 0 {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("message not found: %s", id)
+	}
+	if agentID == "" {
+		agentID = m.messages[idx].AgentID
+	}
+	speaker := m.findAgentLocked(agentID)
+	if speaker == nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("agent not found: %s", agentID)
+	}
+	m.isTurnInProgress = true
+	ctx := m.ctx
+	tc := m.newTurnLocked(speaker, id, m.messages[:idx])
+	// A regenerated private message stays private, shown to its new author too
+	if audience := m.messages[idx].Audience; len(audience) > 0 && !containsString(audience, speaker.ID) {
+		m.whisperLocked(tc, append(append([]string(nil), audience...), speaker.ID))
+	} else {
+		m.whisperLocked(tc, audience)
+	}
+	pipeline := m.pipelineLocked()
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.isTurnInProgress = false
+		m.mu.Unlock()
+	}()
+
+	if err := pipeline.beforeTurn(ctx, tc); err != nil {
+		return nil, fmt.Errorf("turn vetoed: %w", err)
+	}
+	var err error
+	if tc.Content, tc.Citations, err = streamReply(ctx, tc, pipeline, false, streamCh); err != nil {
+		return nil, err
+	}
+	if err := pipeline.afterTurn(ctx, tc); err != nil {
+		streamCh <- StreamMessage{Type: "end", AgentID: speaker.ID, MessageID: id, Audience: tc.Audience}
+		// The previous version is still stored: send it back to clients
+		m.mu.RLock()
+		var previous *Message
+		if idx := m.messageIndexLocked(id); idx >= 0 {
+			msg := m.messages[idx]
+			previous = &msg
+		}
+		m.mu.RUnlock()
+		m.emit(map[string]interface{}{
+			"type":       "turn_rejected",
+			"agent_id":   speaker.ID,
+			"agent_name": speaker.Name,
+			"message_id": id,
+			"reason":     err.Error(),
+			"message":    previous,
+		})
+		return nil, fmt.Errorf("regenerated message rejected: %w", err)
+	}
+
+	m.mu.Lock()
+	content, _ := m.endMarkerLocked(tc.Content)
+	// A reset or restore may have replaced the transcript while streaming
+	idx = m.messageIndexLocked(id)
+	if idx < 0 {
+		m.mu.Unlock()
+		streamCh <- StreamMessage{Type: "end", AgentID: speaker.ID, MessageID: id, Audience: tc.Audience}
+		return nil, fmt.Errorf("message not found: %s", id)
+	}
+	msg := &m.messages[idx]
+	msg.Edits = withEdit(*msg, EditActionRegenerated)
+	msg.AgentID = speaker.ID
+	msg.AgentName = speaker.Name
+	msg.Color = speaker.Color
+	msg.Content = content
+	msg.Citations = tc.Citations
+	msg.Annotations = tc.Annotations
+	msg.Meta = tc.Meta
+	msg.Audience = tc.Audience
+	msg.Timestamp = time.Now()
+	updated := *msg
+	m.mu.Unlock()
+
+	log.Printf("Regenerated %s with %s", id, speaker.Name)
+	m.persist()
+	streamCh <- endMessage(tc, content, false)
+	m.emitMessageUpdated(EditActionRegenerated, &updated, id)
+	return &updated, nil
+}
+
+// messageIndexLocked returns the index of a message in the active branch, -1
+// if absent; callers must hold m.mu
+func (m *Manager) messageIndexLocked(id string) int {
+	for i, msg := range m.messages {
+		if msg.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// emitMessageUpdated tells clients a stored message changed (msg is nil when deleted)
+func (m *Manager) emitMessageUpdated(action string, msg *Message, id string) {
+	m.emit(map[string]interface{}{
+		"type":       "message_updated",
+		"action":     action,
+		"message_id": id,
+		"message":    msg,
+	})
+}
+
+// withEdit returns msg's edit history with its current version appended. The
+// history is copied since forked branches share it.
+func withEdit(msg Message, action string) []MessageEdit {
+	return append(append([]MessageEdit(nil), msg.Edits...), MessageEdit{
+		Action:     action,
+		AgentID:    msg.AgentID,
+		AgentName:  msg.AgentName,
+		Content:    msg.Content,
+		Timestamp:  msg.Timestamp,
+		ReplacedAt: time.Now(),
+	})
+}
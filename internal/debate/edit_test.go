@@ -0,0 +1,67 @@
+package debate
+
+import "testing"
+
+func TestEditKeepsPreviousVersion(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Thuế carbon")
+	nextTurns(t, m, 2)
+
+	edited, err := m.EditMessage("msg_2", "nội dung đã sửa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Content != "nội dung đã sửa" || len(edited.Edits) != 1 {
+		t.Fatalf("edited = %+v, want the new content and one previous version", edited)
+	}
+	if prev := edited.Edits[0]; prev.Action != EditActionEdited || prev.Content != "câu trả lời số 2" || prev.AgentID != "a2" {
+		t.Errorf("previous version = %+v", prev)
+	}
+	if _, err := m.EditMessage("msg_9", "x"); err == nil {
+		t.Error("edited a message that doesn't exist")
+	}
+}
+
+func TestDeleteRelinksNextMessage(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Thuế carbon")
+	nextTurns(t, m, 3)
+
+	if err := m.DeleteMessage("msg_2"); err != nil {
+		t.Fatal(err)
+	}
+	msgs := m.GetMessages()
+	if len(msgs) != 2 || msgs[0].ID != "msg_1" || msgs[1].ID != "msg_3" {
+		t.Fatalf("messages = %+v, want msg_1 and msg_3", msgs)
+	}
+	if msgs[1].ParentID != "msg_1" {
+		t.Errorf("msg_3 parent = %q, want msg_1", msgs[1].ParentID)
+	}
+}
+
+func TestRegenerateKeepsIDAndSwitchesAuthor(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Thuế carbon")
+	nextTurns(t, m, 2)
+
+	var regenerated *Message
+	play(t, m, func(ch chan<- StreamMessage) error {
+		var err error
+		regenerated, err = m.RegenerateMessage("msg_1", "a3", ch)
+		return err
+	})
+	if regenerated.ID != "msg_1" || regenerated.AgentID != "a3" || regenerated.Content != "câu trả lời số 3" {
+		t.Errorf("regenerated = %+v, want msg_1 rewritten by a3", regenerated)
+	}
+	if len(regenerated.Edits) != 1 || regenerated.Edits[0].Action != EditActionRegenerated || regenerated.Edits[0].AgentID != "a1" {
+		t.Errorf("edits = %+v, want a1's version kept", regenerated.Edits)
+	}
+	if msgs := m.GetMessages(); len(msgs) != 2 || msgs[0].Content != regenerated.Content {
+		t.Errorf("messages = %+v, want msg_1 replaced in place", msgs)
+	}
+
+	m.Stop()
+	if _, err := m.RegenerateMessage("msg_1", "", make(chan StreamMessage, 10)); err != ErrNotRunning {
+		t.Errorf("regenerate on a stopped debate = %v, want ErrNotRunning", err)
+	}
+}
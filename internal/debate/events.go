@@ -0,0 +1,400 @@
+package debate
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is one entry of a debate's event log: anything broadcast to the
+// debate's clients, in order
+type Event struct {
+	Seq  int64           `json:"seq"`
+	Time time.Time       `json:"time"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"` // The event as clients received it
+}
+
+// EventRecorder persists debate event logs. A Recorder that implements it
+// also gets every event of the debates it saves.
+type EventRecorder interface {
+	AppendEvent(debateID string, ev Event) error
+	LoadEvents(debateID string) ([]Event, error)
+	CloseEvents(debateID string) error // Releases the log AppendEvent keeps open
+}
+
+// eventRecorderLocked returns the recorder's event log, nil if it keeps
+// none; callers must hold m.mu
+func (m *Manager) eventRecorderLocked() EventRecorder {
+	er, _ := m.recorder.(EventRecorder)
+	return er
+}
+
+// RecordEvent appends an event sent to this debate's clients to the current
+// debate's log. Events before Start and playback events are not logged.
+func (m *Manager) RecordEvent(data []byte) {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil || strings.HasPrefix(head.Type, "playback") {
+		return
+	}
+
+	m.mu.RLock()
+	id, er := m.debateID, m.eventRecorderLocked()
+	m.mu.RUnlock()
+	if id == "" || er == nil {
+		return
+	}
+	m.appendEvent(er, id, head.Type, data)
+}
+
+// logEvent appends an event the manager itself records (not broadcast)
+func (m *Manager) logEvent(id string, event map[string]interface{}) {
+	m.mu.RLock()
+	er := m.eventRecorderLocked()
+	m.mu.RUnlock()
+	if id == "" || er == nil {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	m.appendEvent(er, id, event["type"].(string), data)
+}
+
+// appendEvent numbers an event and writes it. eventMu keeps sequence numbers
+// in file order when several goroutines broadcast at once.
+func (m *Manager) appendEvent(er EventRecorder, id, eventType string, data []byte) {
+	m.eventMu.Lock()
+	defer m.eventMu.Unlock()
+	if m.eventDebate != id {
+		if m.eventDebate != "" {
+			er.CloseEvents(m.eventDebate)
+		}
+		m.eventDebate, m.eventSeq = id, lastEventSeq(er, id)
+	}
+	m.eventSeq++
+	ev := Event{Seq: m.eventSeq, Time: time.Now(), Type: eventType, Data: data}
+	if err := er.AppendEvent(id, ev); err != nil {
+		log.Printf("Warning: Failed to log event for debate %s: %v", id, err)
+	}
+}
+
+// closeEventLog releases the log of the debate being recorded, after a reset
+func (m *Manager) closeEventLog() {
+	m.mu.RLock()
+	er := m.eventRecorderLocked()
+	m.mu.RUnlock()
+
+	m.eventMu.Lock()
+	defer m.eventMu.Unlock()
+	if er != nil && m.eventDebate != "" {
+		er.CloseEvents(m.eventDebate)
+	}
+	m.eventDebate, m.eventSeq = "", 0
+}
+
+// lastEventSeq returns the sequence number of a debate's last logged event,
+// so a resumed debate continues its log
+func lastEventSeq(er EventRecorder, id string) int64 {
+	events, err := er.LoadEvents(id)
+	if err != nil || len(events) == 0 {
+		return 0
+	}
+	return events[len(events)-1].Seq
+}
+
+// logCreated starts a new debate's log with its initial settings
+func (m *Manager) logCreated() {
+	m.mu.RLock()
+	event := map[string]interface{}{
+		"type":         "debate_created",
+		"debate_id":    m.debateID,
+		"topic":        m.topic,
+		"mode":         m.mode,
+		"mode_options": m.selectorOpts,
+		"prompt_set":   m.promptSet,
+		"created_at":   m.createdAt,
+	}
+	id := m.debateID
+	m.mu.RUnlock()
+	m.logEvent(id, event)
+}
+
+// replayEvent holds the fields of logged events that replay reads
+type replayEvent struct {
+	Type        string            `json:"type"`
+	DebateID    string            `json:"debate_id"`
+	Topic       string            `json:"topic"`
+	Mode        Mode              `json:"mode"`
+	ModeOptions SelectorOptions   `json:"mode_options"`
+	PromptSet   string            `json:"prompt_set"`
+	Set         string            `json:"set"`
+	CreatedAt   time.Time         `json:"created_at"`
+	AgentID     string            `json:"agent_id"`
+	AgentName   string            `json:"agent_name"`
+	MessageID   string            `json:"message_id"`
+	Color       string            `json:"color"`
+	Content     string            `json:"content"`
+	Citations   json.RawMessage   `json:"citations"`
+	Annotations map[string]string `json:"annotations"`
+	Meta        *MessageMeta      `json:"meta"`
+	Action      string            `json:"action"`
+	Message     *Message          `json:"message"`
+	Messages    []Message         `json:"messages"`
+	Audience    []string          `json:"audience"`
+	Parallel    bool              `json:"parallel"`
+	Agents      json.RawMessage   `json:"agents"`
+	Snapshot    *Snapshot         `json:"snapshot"`
+	Agenda      *AgendaStatus     `json:"agenda"`
+}
+
+// ReplayEvents rebuilds a debate's state from its event log: settings, topic
+// changes, the agenda, branches and the transcript, including edits,
+// interjections, join and leave notes and streamed turns. Summaries and
+// verdicts come only from the snapshots logged on restore, fork and switch.
+func ReplayEvents(events []Event) (Snapshot, error) {
+	if len(events) == 0 {
+		return Snapshot{}, fmt.Errorf("event log is empty")
+	}
+
+	snap := Snapshot{Mode: ModeRoundRobin, CreatedAt: events[0].Time}
+	pending := make(map[string]*Message) // Turns being streamed, by message ID
+	// Panel position of the last parallel round's replies, by message ID
+	var parallelOrder map[string]int
+	// Logs written before end events carried the committed content
+	legacy := !endsCarryContent(events)
+	for _, ev := range events {
+		var e replayEvent
+		if err := json.Unmarshal(ev.Data, &e); err != nil {
+			return Snapshot{}, fmt.Errorf("event %d: %w", ev.Seq, err)
+		}
+
+		switch ev.Type {
+		case "debate_created":
+			snap.ID, snap.Topic, snap.PromptSet = e.DebateID, e.Topic, e.PromptSet
+			snap.Mode, snap.ModeOptions = e.Mode, e.ModeOptions
+			if !e.CreatedAt.IsZero() {
+				snap.CreatedAt = e.CreatedAt
+			}
+		case "debate_restored", "branch_state":
+			if e.Snapshot != nil {
+				snap = *e.Snapshot
+				pending = make(map[string]*Message)
+			}
+		case "debate_started":
+			snap.Topic, snap.Messages = e.Topic, nil
+		case "debate_reset":
+			// The session moved on; the saved debate keeps its transcript
+			pending = make(map[string]*Message)
+		case "mode_changed":
+			snap.Mode = e.Mode
+		case "prompt_set_changed":
+			snap.PromptSet = e.Set
+		case "agenda_changed":
+			snap.Agenda = nil
+			if e.Agenda != nil {
+				snap.Agenda = e.Agenda.Items
+			}
+		case "topic_changed":
+			if len(snap.Messages) > 0 {
+				snap.MsgCounter++
+				snap.Messages = append(snap.Messages, Message{
+					ID:        fmt.Sprintf("msg_%d", snap.MsgCounter),
+					ParentID:  snap.Messages[len(snap.Messages)-1].ID,
+					AgentID:   "system",
+					AgentName: renderPrompt(snap.PromptSet, "system_name", PromptData{}),
+					Content:   renderPrompt(snap.PromptSet, "topic_change", PromptData{Topic: e.Topic}),
+					Timestamp: ev.Time,
+					Color:     "#888888",
+				})
+			}
+			snap.Topic = e.Topic
+		case "start":
+			pending[e.MessageID] = &Message{
+				ID:        e.MessageID,
+				AgentID:   e.AgentID,
+				AgentName: e.AgentName,
+				Color:     e.Color,
+				Audience:  e.Audience,
+			}
+		case "chunk":
+			if msg := pending[e.MessageID]; msg != nil {
+				msg.Content += e.Content
+			}
+		case "retry":
+			if msg := pending[e.MessageID]; msg != nil {
+				msg.Content = ""
+			}
+		case "error":
+			delete(pending, e.MessageID)
+		case "turn_rejected":
+			// A rejected rewrite carries the version that was kept
+			delete(pending, e.MessageID)
+			if e.Message != nil {
+				snap.Messages = replaceMessage(snap.Messages, *e.Message)
+			} else {
+				snap.Messages = removeMessage(snap.Messages, e.MessageID)
+			}
+		case "parallel_started":
+			var agents []struct {
+				MessageID string `json:"message_id"`
+			}
+			json.Unmarshal(e.Agents, &agents)
+			parallelOrder = make(map[string]int, len(agents))
+			for i, a := range agents {
+				parallelOrder[a.MessageID] = i
+			}
+		case "end":
+			msg := pending[e.MessageID]
+			delete(pending, e.MessageID)
+			if msg == nil {
+				continue
+			}
+			// A turn that ended without committed content was stopped or dropped
+			if legacy {
+				msg.Content, _ = stripEndMarker(snap.PromptSet, cleanMessageContent(msg.Content))
+			} else {
+				msg.Content = e.Content
+			}
+			if msg.Content == "" {
+				continue
+			}
+			msg.Timestamp = ev.Time
+			if len(e.Citations) > 0 {
+				json.Unmarshal(e.Citations, &msg.Citations)
+			}
+			msg.Annotations, msg.Meta = e.Annotations, e.Meta
+			if _, ok := parallelOrder[msg.ID]; ok && e.Parallel {
+				snap.Messages = commitParallel(snap.Messages, *msg, parallelOrder)
+				continue
+			}
+			snap.Messages = commitReplayed(snap.Messages, *msg)
+		case "interjection":
+			if e.Message != nil {
+				snap.Messages = append(snap.Messages, *e.Message)
+			}
+		case "agents_updated":
+			// Join and leave notes; the panel itself comes from the session
+			snap.Messages = append(snap.Messages, e.Messages...)
+		case "message_updated":
+			if e.Action == EditActionDeleted {
+				snap.Messages = removeMessage(snap.Messages, e.MessageID)
+			} else if e.Message != nil {
+				snap.Messages = replaceMessage(snap.Messages, *e.Message)
+			}
+		}
+		if n := messageNumber(e.MessageID); n > snap.MsgCounter {
+			snap.MsgCounter = n
+		}
+		if e.Message != nil {
+			if n := messageNumber(e.Message.ID); n > snap.MsgCounter {
+				snap.MsgCounter = n
+			}
+		}
+		for _, msg := range e.Messages {
+			if n := messageNumber(msg.ID); n > snap.MsgCounter {
+				snap.MsgCounter = n
+			}
+		}
+		snap.UpdatedAt = ev.Time
+	}
+	if snap.ID == "" {
+		return Snapshot{}, fmt.Errorf("event log has no debate_created event")
+	}
+	return snap, nil
+}
+
+// commitReplayed adds a replayed turn after the last message, or puts a
+// regenerated one back in its place
+func commitReplayed(messages []Message, msg Message) []Message {
+	if n := len(messages); n > 0 {
+		msg.ParentID = messages[n-1].ID
+	}
+	return replaceMessage(messages, msg)
+}
+
+// commitParallel adds a replayed parallel reply among the round's replies
+// already at the end of the transcript, in panel order: replies finish in
+// any order but are committed in the order the round started them
+func commitParallel(messages []Message, msg Message, order map[string]int) []Message {
+	pos := len(messages)
+	for pos > 0 {
+		i, ok := order[messages[pos-1].ID]
+		if !ok || i < order[msg.ID] {
+			break
+		}
+		pos--
+	}
+	msg.ParentID = ""
+	if pos > 0 {
+		msg.ParentID = messages[pos-1].ID
+	}
+	if pos < len(messages) {
+		messages[pos].ParentID = msg.ID
+	}
+	messages = append(messages, Message{})
+	copy(messages[pos+1:], messages[pos:])
+	messages[pos] = msg
+	return messages
+}
+
+// endsCarryContent reports whether a log's end events carry the committed
+// content, rather than leaving replay to rebuild it from the chunks
+func endsCarryContent(events []Event) bool {
+	for _, ev := range events {
+		if ev.Type != "end" {
+			continue
+		}
+		var e struct {
+			Content string `json:"content"`
+		}
+		if json.Unmarshal(ev.Data, &e) == nil && e.Content != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// replaceMessage replaces the message with msg's ID (keeping its place,
+// parent and, when msg has none, its edit history), or appends msg. A
+// regeneration's end event may be logged after the message_updated event
+// that carries its history.
+func replaceMessage(messages []Message, msg Message) []Message {
+	for i := range messages {
+		if messages[i].ID == msg.ID {
+			msg.ParentID = messages[i].ParentID
+			if msg.Edits == nil {
+				msg.Edits = messages[i].Edits
+			}
+			messages[i] = msg
+			return messages
+		}
+	}
+	return append(messages, msg)
+}
+
+// removeMessage drops the message with the given ID, if present, and links
+// the next message to its parent as DeleteMessage does
+func removeMessage(messages []Message, id string) []Message {
+	for i := range messages {
+		if messages[i].ID == id {
+			if i+1 < len(messages) {
+				messages[i+1].ParentID = messages[i].ParentID
+			}
+			return append(messages[:i:i], messages[i+1:]...)
+		}
+	}
+	return messages
+}
+
+// messageNumber returns N for a "msg_N" ID, 0 otherwise
+func messageNumber(id string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(id, "msg_"))
+	return n
+}
@@ -0,0 +1,383 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/user/talk/internal/agent"
+	"github.com/user/talk/internal/provider"
+)
+
+// scriptProvider streams numbered replies word by word
+type scriptProvider struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (p *scriptProvider) Name() string { return "script" }
+
+func (p *scriptProvider) Chat(_ context.Context, _ []provider.Message, _ provider.Options) (<-chan provider.StreamChunk, error) {
+	p.mu.Lock()
+	p.n++
+	reply := fmt.Sprintf("câu trả lời số %d", p.n)
+	p.mu.Unlock()
+
+	words := strings.SplitAfter(reply, " ")
+	ch := make(chan provider.StreamChunk, len(words)+1)
+	for _, w := range words {
+		ch <- provider.StreamChunk{Content: w}
+	}
+	ch <- provider.StreamChunk{Done: true, FinishReason: "stop"}
+	close(ch)
+	return ch, nil
+}
+
+// memRecorder keeps snapshots and event logs in memory
+type memRecorder struct {
+	mu     sync.Mutex
+	events map[string][]Event
+}
+
+func (r *memRecorder) SaveDebate(Snapshot) error { return nil }
+
+func (r *memRecorder) AppendEvent(id string, ev Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[id] = append(r.events[id], ev)
+	return nil
+}
+
+func (r *memRecorder) LoadEvents(id string) ([]Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event(nil), r.events[id]...), nil
+}
+
+func (r *memRecorder) CloseEvents(string) error { return nil }
+
+func testAgent(id, name string, p provider.Provider) *agent.Agent {
+	return &agent.Agent{ID: id, Name: name, Role: "r", Color: "#123456", Provider: p}
+}
+
+// newTestManager returns a manager whose events, manager-originated or
+// streamed through record, land in an in-memory log as the hub would log them
+func newTestManager(t *testing.T, hooks ...Hook) (*Manager, *memRecorder) {
+	t.Helper()
+	p := &scriptProvider{}
+	m := NewManager([]*agent.Agent{testAgent("a1", "Alpha", p), testAgent("a2", "Beta", p), testAgent("a3", "Gamma", p)})
+	rec := &memRecorder{events: make(map[string][]Event)}
+	m.SetRecorder(rec)
+	m.SetEventFunc(func(ev interface{}) { record(m, ev) })
+	m.SetPipeline(NewPipeline(append([]Hook{cleanContentHook{}}, hooks...)...))
+	return m, rec
+}
+
+func record(m *Manager, ev interface{}) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		panic(err)
+	}
+	m.RecordEvent(data)
+}
+
+// play runs a streaming call, recording its stream like the server does
+func play(t *testing.T, m *Manager, fn func(chan<- StreamMessage) error) {
+	t.Helper()
+	ch := make(chan StreamMessage, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn(ch)
+		close(ch)
+	}()
+	for msg := range ch {
+		record(m, msg)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func start(t *testing.T, m *Manager, topic string) {
+	t.Helper()
+	if err := m.Start(topic); err != nil {
+		t.Fatal(err)
+	}
+	record(m, map[string]interface{}{"type": "debate_started", "topic": topic})
+}
+
+func nextTurns(t *testing.T, m *Manager, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		play(t, m, m.NextTurn)
+	}
+}
+
+// retryOnceHook rejects every first attempt and asks for a rewrite
+type retryOnceHook struct{}
+
+func (retryOnceHook) Name() string { return "retry_once" }
+
+func (retryOnceHook) AfterTurn(_ context.Context, tc *TurnContext) error {
+	if tc.Attempt == 0 {
+		return &Rejection{Reason: "too short", Retry: true}
+	}
+	return nil
+}
+
+// vetoHook vetoes every turn of one agent
+type vetoHook struct{ agentID string }
+
+func (vetoHook) Name() string { return "veto" }
+
+func (h vetoHook) BeforeTurn(_ context.Context, tc *TurnContext) error {
+	if tc.Agent.ID == h.agentID {
+		return errors.New("vetoed")
+	}
+	return nil
+}
+
+// approveHook rewrites every reply after it is streamed
+type approveHook struct{}
+
+func (approveHook) Name() string { return "approve" }
+
+func (approveHook) AfterTurn(_ context.Context, tc *TurnContext) error {
+	tc.Content += " (đã duyệt)"
+	return nil
+}
+
+// replayedMessage is what replay must reproduce of a message
+type replayedMessage struct {
+	ID, AgentID, AgentName, Content, ParentID, To string
+	Audience                                      []string
+	Edits                                         int
+}
+
+func replayedMessages(messages []Message) []replayedMessage {
+	result := make([]replayedMessage, len(messages))
+	for i, msg := range messages {
+		result[i] = replayedMessage{
+			ID:        msg.ID,
+			AgentID:   msg.AgentID,
+			AgentName: msg.AgentName,
+			Content:   msg.Content,
+			ParentID:  msg.ParentID,
+			To:        msg.To,
+			Audience:  msg.Audience,
+			Edits:     len(msg.Edits),
+		}
+	}
+	return result
+}
+
+func TestReplayEventsMatchesLiveSnapshot(t *testing.T) {
+	tests := []struct {
+		name  string
+		hooks []Hook
+		run   func(t *testing.T, m *Manager)
+	}{
+		{
+			name: "turns",
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 4)
+			},
+		},
+		{
+			name: "interjection answered next",
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 1)
+				if _, err := m.Interject("Còn chi phí thì sao?", "a3", nil); err != nil {
+					t.Fatal(err)
+				}
+				nextTurns(t, m, 2)
+			},
+		},
+		{
+			name: "edit and delete",
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 3)
+				if _, err := m.EditMessage("msg_2", "nội dung đã sửa"); err != nil {
+					t.Fatal(err)
+				}
+				if err := m.DeleteMessage("msg_1"); err != nil {
+					t.Fatal(err)
+				}
+				nextTurns(t, m, 1)
+			},
+		},
+		{
+			name: "regenerate",
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 2)
+				play(t, m, func(ch chan<- StreamMessage) error {
+					_, err := m.RegenerateMessage("msg_1", "a3", ch)
+					return err
+				})
+			},
+		},
+		{
+			name:  "retried replies",
+			hooks: []Hook{retryOnceHook{}},
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 2)
+			},
+		},
+		{
+			name:  "vetoed turn",
+			hooks: []Hook{vetoHook{agentID: "a2"}},
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 3)
+			},
+		},
+		{
+			name: "private turn and hint",
+			run: func(t *testing.T, m *Manager) {
+				play(t, m, func(ch chan<- StreamMessage) error {
+					return m.TurnByAgent("a1", []string{"a2"}, ch)
+				})
+				if _, err := m.Interject("Gợi ý riêng", "", []string{"a3"}); err != nil {
+					t.Fatal(err)
+				}
+				nextTurns(t, m, 1)
+			},
+		},
+		{
+			name: "join and leave",
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 1)
+				agents := m.agents
+				m.UpdateAgents([]*agent.Agent{agents[0], agents[2], testAgent("a4", "Delta", &scriptProvider{})})
+				nextTurns(t, m, 2)
+			},
+		},
+		{
+			name: "fork and switch",
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 3)
+				if _, err := m.Fork("msg_1", "", true); err != nil {
+					t.Fatal(err)
+				}
+				nextTurns(t, m, 1)
+				if err := m.SwitchBranch(MainBranch); err != nil {
+					t.Fatal(err)
+				}
+				nextTurns(t, m, 1)
+			},
+		},
+		{
+			name: "parallel round",
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 1)
+				play(t, m, func(ch chan<- StreamMessage) error {
+					return m.ParallelRound(true, ch)
+				})
+				nextTurns(t, m, 1)
+			},
+		},
+		{
+			name:  "post-turn rewrite",
+			hooks: []Hook{approveHook{}},
+			run: func(t *testing.T, m *Manager) {
+				nextTurns(t, m, 2)
+				play(t, m, func(ch chan<- StreamMessage) error {
+					_, err := m.RegenerateMessage("msg_1", "", ch)
+					return err
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, rec := newTestManager(t, tt.hooks...)
+			start(t, m, "Thuế carbon")
+			tt.run(t, m)
+
+			live := m.Snapshot()
+			events, _ := rec.LoadEvents(live.ID)
+			replayed, err := ReplayEvents(events)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if replayed.ID != live.ID || replayed.Topic != live.Topic {
+				t.Errorf("replayed debate %s %q, want %s %q", replayed.ID, replayed.Topic, live.ID, live.Topic)
+			}
+			if replayed.Branch != live.Branch || len(replayed.Branches) != len(live.Branches) {
+				t.Errorf("replayed branch %q of %d, want %q of %d", replayed.Branch, len(replayed.Branches), live.Branch, len(live.Branches))
+			}
+			if replayed.MsgCounter != live.MsgCounter {
+				t.Errorf("replayed MsgCounter = %d, want %d", replayed.MsgCounter, live.MsgCounter)
+			}
+			got, want := replayedMessages(replayed.Messages), replayedMessages(live.Messages)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("replayed messages differ\n got: %+v\nwant: %+v", got, want)
+			}
+		})
+	}
+}
+
+// logged builds an event log from events given as JSON
+func logged(t *testing.T, events ...string) []Event {
+	t.Helper()
+	result := make([]Event, len(events))
+	for i, data := range events {
+		var head struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(data), &head); err != nil {
+			t.Fatal(err)
+		}
+		result[i] = Event{Seq: int64(i + 1), Type: head.Type, Data: json.RawMessage(data)}
+	}
+	return result
+}
+
+func TestReplayParallelRepliesInPanelOrder(t *testing.T) {
+	events := logged(t,
+		`{"type":"debate_created","debate_id":"d1","topic":"t"}`,
+		`{"type":"parallel_started","round":"answer","agents":[{"agent_id":"a1","message_id":"msg_1"},{"agent_id":"a2","message_id":"msg_2"},{"agent_id":"a3","message_id":"msg_3"}]}`,
+		`{"type":"start","agent_id":"a1","message_id":"msg_1","parallel":true}`,
+		`{"type":"start","agent_id":"a2","message_id":"msg_2","parallel":true}`,
+		`{"type":"start","agent_id":"a3","message_id":"msg_3","parallel":true}`,
+		`{"type":"end","agent_id":"a3","message_id":"msg_3","content":"ba","parallel":true}`,
+		`{"type":"parallel_finished","round":"answer","committed":3}`,
+		`{"type":"end","agent_id":"a1","message_id":"msg_1","content":"một","parallel":true}`,
+		`{"type":"end","agent_id":"a2","message_id":"msg_2","content":"hai","parallel":true}`,
+	)
+	snap, err := ReplayEvents(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := messageIDs(snap.Messages); !reflect.DeepEqual(got, []string{"msg_1", "msg_2", "msg_3"}) {
+		t.Fatalf("replayed order = %v, want panel order", got)
+	}
+	for i, parent := range []string{"", "msg_1", "msg_2"} {
+		if snap.Messages[i].ParentID != parent {
+			t.Errorf("%s parent = %q, want %q", snap.Messages[i].ID, snap.Messages[i].ParentID, parent)
+		}
+	}
+}
+
+func TestReplayLegacyEndsRebuildFromChunks(t *testing.T) {
+	events := logged(t,
+		`{"type":"debate_created","debate_id":"d1","topic":"t"}`,
+		`{"type":"start","agent_id":"a1","agent_name":"Alpha","message_id":"msg_1"}`,
+		`{"type":"chunk","agent_id":"a1","message_id":"msg_1","content":"xin "}`,
+		`{"type":"chunk","agent_id":"a1","message_id":"msg_1","content":"chào [KẾT THÚC]"}`,
+		`{"type":"end","agent_id":"a1","message_id":"msg_1"}`,
+	)
+	snap, err := ReplayEvents(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Messages) != 1 || snap.Messages[0].Content != "xin chào" {
+		t.Errorf("replayed messages = %+v, want the cleaned chunks", snap.Messages)
+	}
+}
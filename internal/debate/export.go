@@ -0,0 +1,145 @@
+package debate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExportBranchMarkdown renders one branch's transcript with its own summaries
+// and verdict (empty ID = active branch)
+func (m *Manager) ExportBranchMarkdown(id string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if id == "" || id == m.activeBranchLocked() {
+		return renderMarkdown(m.promptSet, m.topic, m.messages, m.summaries, m.verdict, m.agentNamesLocked(m.messages)), nil
+	}
+	b, ok := m.branches[id]
+	if !ok {
+		return "", fmt.Errorf("branch not found: %s", id)
+	}
+	topic := b.Topic
+	if topic == "" {
+		topic = m.topic
+	}
+	return renderMarkdown(m.promptSet, topic, b.Messages, b.Summaries, b.Verdict, m.agentNamesLocked(b.Messages)), nil
+}
+
+// agentNamesLocked maps agent IDs to names for the panel and everyone who
+// spoke in messages; callers must hold m.mu
+func (m *Manager) agentNamesLocked(messages []Message) map[string]string {
+	names := make(map[string]string, len(m.agents))
+	for _, msg := range messages {
+		names[msg.AgentID] = msg.AgentName
+	}
+	for _, a := range m.agents {
+		names[a.ID] = a.Name
+	}
+	return names
+}
+
+// renderMarkdown builds the Markdown export for a topic and its messages,
+// with labels from the prompt set setID. The "## Name *(time)*" headers match
+// what the web UI can import back. names resolves the agent IDs of private
+// messages' audiences.
+func renderMarkdown(setID, topic string, messages []Message, summaries []Summary, verdict *Verdict, names map[string]string) string {
+	var sb strings.Builder
+
+	sb.WriteString("# AI Multi-Agent Debate\n\n")
+	if topic != "" {
+		fmt.Fprintf(&sb, "%s\n\n", renderPrompt(setID, "export_topic", PromptData{Topic: topic}))
+	}
+	date := PromptData{Content: time.Now().Format("2006-01-02 15:04:05")}
+	fmt.Fprintf(&sb, "%s\n\n---\n\n", renderPrompt(setID, "export_date", date))
+
+	for _, msg := range messages {
+		fmt.Fprintf(&sb, "## %s *(%s)*\n\n", msg.AgentName, msg.Timestamp.Format("15:04:05"))
+		if len(msg.Audience) > 0 {
+			private := PromptData{Audience: audienceLabel(msg.Audience, names)}
+			fmt.Fprintf(&sb, "%s\n\n", renderPrompt(setID, "export_private", private))
+		}
+		sb.WriteString(msg.Content)
+		sb.WriteString("\n\n")
+		if msg.Meta != nil {
+			fmt.Fprintf(&sb, "*%s*\n\n", msg.Meta)
+		}
+
+		if len(msg.Citations) > 0 {
+			fmt.Fprintf(&sb, "%s\n\n", renderPrompt(setID, "export_sources", PromptData{}))
+			for _, c := range msg.Citations {
+				title := c.Title
+				if title == "" {
+					title = c.URL
+				}
+				fmt.Fprintf(&sb, "%d. [%s](%s)\n", c.Index, title, c.URL)
+			}
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("---\n\n")
+	}
+
+	if len(summaries) > 0 {
+		renderSummaries(&sb, setID, summaries)
+	}
+	if verdict != nil {
+		renderVerdict(&sb, setID, verdict)
+	}
+
+	return sb.String()
+}
+
+// audienceLabel lists a private message's audience by name
+func audienceLabel(audience []string, names map[string]string) string {
+	labels := make([]string, len(audience))
+	for i, id := range audience {
+		labels[i] = id
+		if name := names[id]; name != "" {
+			labels[i] = name
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+// renderSummaries appends the summaries, final ones last. Headers avoid the
+// "## Name *(time)*" form so importing the file skips them.
+func renderSummaries(sb *strings.Builder, setID string, summaries []Summary) {
+	fmt.Fprintf(sb, "%s\n\n", renderPrompt(setID, "export_summaries", PromptData{}))
+	for _, kind := range []string{SummaryAgenda, SummaryRolling, SummaryFinal} {
+		label := renderPrompt(setID, "summary_kind", PromptData{Kind: kind})
+		for _, s := range summaries {
+			if s.Kind != kind {
+				continue
+			}
+			fmt.Fprintf(sb, "### %s - %s (%s)\n\n%s\n\n", label, s.AgentName, s.Timestamp.Format("15:04:05"), s.Content)
+		}
+	}
+	sb.WriteString("---\n\n")
+}
+
+// renderVerdict appends the judge's scores table and decision
+func renderVerdict(sb *strings.Builder, setID string, v *Verdict) {
+	fmt.Fprintf(sb, "%s\n\n", renderPrompt(setID, "export_verdict", PromptData{Speaker: v.Judge}))
+
+	fmt.Fprintf(sb, "| %s |", renderPrompt(setID, "export_participant", PromptData{}))
+	for _, c := range v.Rubric {
+		fmt.Fprintf(sb, " %s |", c.Name)
+	}
+	fmt.Fprintf(sb, " %s |\n|---|", renderPrompt(setID, "export_total", PromptData{}))
+	for range v.Rubric {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("---|\n")
+	for _, score := range v.Scores {
+		fmt.Fprintf(sb, "| %s |", score.AgentName)
+		for _, c := range v.Rubric {
+			fmt.Fprintf(sb, " %.1f |", score.Scores[c.ID])
+		}
+		fmt.Fprintf(sb, " **%.1f** |\n", score.Total)
+	}
+
+	fmt.Fprintf(sb, "\n%s\n\n", renderPrompt(setID, "export_winner", PromptData{Speaker: v.WinnerName}))
+	if v.Rationale != "" {
+		fmt.Fprintf(sb, "%s\n\n", v.Rationale)
+	}
+}
@@ -0,0 +1,333 @@
+package debate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// ErrFormatComplete is returned by NextTurn once every phase of the format has been played
+var ErrFormatComplete = errors.New("all phases of the debate format are complete")
+
+// Side is the position an agent argues in a structured format
+type Side string
+
+const (
+	SidePro     Side = "pro"
+	SideCon     Side = "con"
+	SideNeutral Side = "neutral"
+	// SideAll is only used in phase orders: every agent, in panel order
+	SideAll Side = "all"
+)
+
+// valid reports whether an agent can be assigned the side
+func (s Side) valid() bool {
+	return s == SidePro || s == SideCon || s == SideNeutral
+}
+
+// Phase is one stage of a structured debate
+type Phase struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Order        []Side `json:"order"` // Speaking order; each side expands to its agents in panel order
+	Instructions string `json:"instructions"`
+	WordLimit    int    `json:"word_limit,omitempty"` // 0 = no limit
+}
+
+// Format is a structured debate made of ordered phases
+type Format struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	UsesSides   bool    `json:"uses_sides"` // Agents argue pro/con instead of as a panel
+	Phases      []Phase `json:"phases"`
+}
+
+// builtinFormats hold the structure of each format; descriptions and phase
+// names and instructions come from the prompt set (see localized)
+var builtinFormats = map[string]Format{
+	"oxford": {
+		ID:        "oxford",
+		Name:      "Oxford",
+		UsesSides: true,
+		Phases: []Phase{
+			{
+				ID:        "opening",
+				Order:     []Side{SidePro, SideCon},
+				WordLimit: 300,
+			},
+			{
+				ID:        "rebuttal",
+				Order:     []Side{SideCon, SidePro},
+				WordLimit: 250,
+			},
+			{
+				ID:        "closing",
+				Order:     []Side{SideCon, SidePro},
+				WordLimit: 200,
+			},
+		},
+	},
+	"lincoln_douglas": {
+		ID:        "lincoln_douglas",
+		Name:      "Lincoln-Douglas",
+		UsesSides: true,
+		Phases: []Phase{
+			{
+				ID:        "opening",
+				Order:     []Side{SidePro, SideCon},
+				WordLimit: 300,
+			},
+			{
+				ID:        "cross_exam",
+				Order:     []Side{SideCon, SidePro, SidePro, SideCon},
+				WordLimit: 120,
+			},
+			{
+				ID:        "rebuttal",
+				Order:     []Side{SidePro, SideCon, SidePro},
+				WordLimit: 200,
+			},
+			{
+				ID:        "closing",
+				Order:     []Side{SideCon, SidePro},
+				WordLimit: 150,
+			},
+		},
+	},
+	"panel": {
+		ID:   "panel",
+		Name: "Panel",
+		Phases: []Phase{
+			{
+				ID:        "opening",
+				Order:     []Side{SideAll},
+				WordLimit: 200,
+			},
+			{
+				ID:        "rebuttal",
+				Order:     []Side{SideAll},
+				WordLimit: 200,
+			},
+			{
+				ID:        "cross_exam",
+				Order:     []Side{SideAll},
+				WordLimit: 150,
+			},
+			{
+				ID:        "closing",
+				Order:     []Side{SideAll},
+				WordLimit: 120,
+			},
+		},
+	},
+}
+
+// Formats returns the built-in debate formats with texts from a prompt set,
+// sorted by ID
+func Formats(setID string) []Format {
+	formats := make([]Format, 0, len(builtinFormats))
+	for _, f := range builtinFormats {
+		formats = append(formats, f.localized(setID))
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i].ID < formats[j].ID })
+	return formats
+}
+
+// GetFormat returns a built-in format by ID, without its texts
+func GetFormat(id string) (Format, bool) {
+	f, ok := builtinFormats[id]
+	return f, ok
+}
+
+// FormatStatus describes progress through the active format
+type FormatStatus struct {
+	Format      string          `json:"format"`
+	Name        string          `json:"name"`
+	Phase       string          `json:"phase,omitempty"`
+	PhaseName   string          `json:"phase_name,omitempty"`
+	PhaseIndex  int             `json:"phase_index"`
+	TotalPhases int             `json:"total_phases"`
+	Turn        int             `json:"turn"`        // Turns already taken in the current phase
+	PhaseTurns  int             `json:"phase_turns"` // Turns in the current phase
+	WordLimit   int             `json:"word_limit,omitempty"`
+	NextSpeaker string          `json:"next_speaker,omitempty"`
+	Sides       map[string]Side `json:"sides,omitempty"`
+	Complete    bool            `json:"complete"`
+}
+
+// SetFormat switches to a structured format (empty ID clears it) and restarts
+// its phases. Agents missing from sides alternate pro/con in panel order.
+func (m *Manager) SetFormat(id string, sides map[string]Side) error {
+	var format *Format
+	if id != "" {
+		f, ok := GetFormat(id)
+		if !ok {
+			return fmt.Errorf("unknown format: %s", id)
+		}
+		format = &f
+	}
+	for agentID, side := range sides {
+		if !side.valid() {
+			return fmt.Errorf("invalid side %q for agent %s", side, agentID)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.isTurnInProgress {
+		return ErrTurnInProgress
+	}
+	for agentID := range sides {
+		if m.findAgentLocked(agentID) == nil {
+			return fmt.Errorf("agent not found: %s", agentID)
+		}
+	}
+
+	m.format = format
+	m.sides = nil
+	m.phaseIndex = 0
+	m.phaseTurn = 0
+	if format != nil && format.UsesSides {
+		m.sides = assignSides(m.agents, sides)
+	}
+	return nil
+}
+
+// GetFormatStatus returns progress through the active format, nil if none
+func (m *Manager) GetFormatStatus() *FormatStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.formatStatusLocked()
+}
+
+// formatStatusLocked builds the format status; callers must hold m.mu
+func (m *Manager) formatStatusLocked() *FormatStatus {
+	if m.format == nil {
+		return nil
+	}
+	status := &FormatStatus{
+		Format:      m.format.ID,
+		Name:        m.format.Name,
+		PhaseIndex:  m.phaseIndex,
+		TotalPhases: len(m.format.Phases),
+		Turn:        m.phaseTurn,
+		Sides:       m.sides,
+		Complete:    m.phaseIndex >= len(m.format.Phases),
+	}
+	if !status.Complete {
+		phase := m.format.localized(m.promptSet).Phases[m.phaseIndex]
+		speakers := m.phaseSpeakersLocked(phase)
+		status.Phase = phase.ID
+		status.PhaseName = phase.Name
+		status.PhaseTurns = len(speakers)
+		status.WordLimit = phase.WordLimit
+		if m.phaseTurn < len(speakers) {
+			status.NextSpeaker = speakers[m.phaseTurn].ID
+		}
+	}
+	return status
+}
+
+// localized returns the format with its description and phase names and
+// instructions rendered from a prompt set: format_<format>,
+// phase_<format>_<phase> and phase_<format>_<phase>_instructions
+func (f Format) localized(setID string) Format {
+	f.Description = renderPrompt(setID, "format_"+f.ID, PromptData{})
+	phases := make([]Phase, len(f.Phases))
+	for i, phase := range f.Phases {
+		name := "phase_" + f.ID + "_" + phase.ID
+		phase.Name = renderPrompt(setID, name, PromptData{})
+		phase.Instructions = renderPrompt(setID, name+"_instructions", PromptData{})
+		phases[i] = phase
+	}
+	f.Phases = phases
+	return f
+}
+
+// assignSides fills in sides for agents without an explicit one, alternating pro/con
+func assignSides(agents []*agent.Agent, explicit map[string]Side) map[string]Side {
+	sides := make(map[string]Side, len(agents))
+	next := SidePro
+	for _, a := range agents {
+		if side, ok := explicit[a.ID]; ok {
+			sides[a.ID] = side
+			continue
+		}
+		sides[a.ID] = next
+		if next == SidePro {
+			next = SideCon
+		} else {
+			next = SidePro
+		}
+	}
+	return sides
+}
+
+// phaseSpeakersLocked expands a phase's order into agents; callers must hold m.mu
+func (m *Manager) phaseSpeakersLocked(phase Phase) []*agent.Agent {
+	var speakers []*agent.Agent
+	for _, side := range phase.Order {
+		for _, a := range m.agents {
+			if side == SideAll || m.sides[a.ID] == side {
+				speakers = append(speakers, a)
+			}
+		}
+	}
+	return speakers
+}
+
+// formatSpeakerLocked returns the next scheduled speaker, skipping phases
+// nobody can speak in. Returns nil once the format is complete.
+func (m *Manager) formatSpeakerLocked() *agent.Agent {
+	for m.phaseIndex < len(m.format.Phases) {
+		speakers := m.phaseSpeakersLocked(m.format.Phases[m.phaseIndex])
+		if m.phaseTurn < len(speakers) {
+			return speakers[m.phaseTurn]
+		}
+		m.phaseIndex++
+		m.phaseTurn = 0
+	}
+	return nil
+}
+
+// advanceFormatLocked records a completed scheduled turn and reports whether
+// the phase changed; callers must hold m.mu
+func (m *Manager) advanceFormatLocked() bool {
+	if m.format == nil || m.phaseIndex >= len(m.format.Phases) {
+		return false
+	}
+	m.phaseTurn++
+	if m.phaseTurn < len(m.phaseSpeakersLocked(m.format.Phases[m.phaseIndex])) {
+		return false
+	}
+	m.phaseIndex++
+	m.phaseTurn = 0
+	// Skip phases that have no speakers with the current sides
+	m.formatSpeakerLocked()
+	return true
+}
+
+// remainingFormatTurnsLocked counts scheduled turns left; callers must hold m.mu
+func (m *Manager) remainingFormatTurnsLocked() int {
+	if m.format == nil {
+		return 0
+	}
+	total := 0
+	for i := m.phaseIndex; i < len(m.format.Phases); i++ {
+		total += len(m.phaseSpeakersLocked(m.format.Phases[i]))
+	}
+	return total - m.phaseTurn
+}
+
+// findAgentLocked finds a panel agent by ID; callers must hold m.mu
+func (m *Manager) findAgentLocked(id string) *agent.Agent {
+	for _, a := range m.agents {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package debate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOxfordFollowsPhaseOrder(t *testing.T) {
+	m, _ := newTestManager(t)
+	if err := m.SetFormat("oxford", map[string]Side{"a3": SideCon}); err != nil {
+		t.Fatal(err)
+	}
+	start(t, m, "Thuế carbon")
+
+	// a1 pro and a2 con by alternation, a3 con explicitly: 3 turns per phase
+	if status := m.GetFormatStatus(); status.Phase != "opening" || status.PhaseTurns != 3 || status.NextSpeaker != "a1" {
+		t.Fatalf("status = %+v, want the opening phase led by a1", status)
+	}
+	nextTurns(t, m, 9)
+
+	var speakers []string
+	for _, msg := range m.GetMessages() {
+		speakers = append(speakers, msg.AgentID)
+	}
+	want := []string{"a1", "a2", "a3", "a2", "a3", "a1", "a2", "a3", "a1"}
+	if !reflect.DeepEqual(speakers, want) {
+		t.Errorf("speakers = %v, want %v", speakers, want)
+	}
+	if status := m.GetFormatStatus(); !status.Complete {
+		t.Errorf("status = %+v, want complete", status)
+	}
+	if err := m.NextTurn(make(chan StreamMessage, 10)); !errors.Is(err, ErrFormatComplete) {
+		t.Errorf("turn after the last phase = %v, want ErrFormatComplete", err)
+	}
+}
+
+func TestSetFormatRejectsBadInput(t *testing.T) {
+	m, _ := newTestManager(t)
+	if err := m.SetFormat("debate_club", nil); err == nil {
+		t.Error("accepted an unknown format")
+	}
+	if err := m.SetFormat("oxford", map[string]Side{"a1": SideAll}); err == nil {
+		t.Error("accepted an invalid side")
+	}
+	if err := m.SetFormat("oxford", map[string]Side{"a9": SidePro}); err == nil {
+		t.Error("accepted a side for an agent not on the panel")
+	}
+
+	if err := m.SetFormat("panel", nil); err != nil {
+		t.Fatal(err)
+	}
+	if status := m.GetFormatStatus(); status.Sides != nil || status.PhaseTurns != 3 {
+		t.Errorf("panel status = %+v, want everyone speaking without sides", status)
+	}
+	if err := m.SetFormat("", nil); err != nil || m.GetFormatStatus() != nil {
+		t.Errorf("clearing the format left %+v (%v)", m.GetFormatStatus(), err)
+	}
+}
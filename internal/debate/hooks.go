@@ -0,0 +1,206 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HookOptions are a hook's settings from config.yaml
+type HookOptions map[string]interface{}
+
+// Int returns an integer option, def if unset
+func (o HookOptions) Int(key string, def int) (int, error) {
+	switch v := o[key].(type) {
+	case nil:
+		return def, nil
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("option %s must be a number", key)
+	}
+}
+
+// String returns a string option, def if unset
+func (o HookOptions) String(key, def string) (string, error) {
+	switch v := o[key].(type) {
+	case nil:
+		return def, nil
+	case string:
+		return v, nil
+	default:
+		return "", fmt.Errorf("option %s must be a string", key)
+	}
+}
+
+// Strings returns a list of strings option
+func (o HookOptions) Strings(key string) ([]string, error) {
+	switch v := o[key].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		result := make([]string, len(v))
+		for i, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("option %s must be a list of strings", key)
+			}
+			result[i] = s
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("option %s must be a list of strings", key)
+	}
+}
+
+func init() {
+	RegisterHook("clean_content", func(HookOptions) (Hook, error) {
+		return cleanContentHook{}, nil
+	})
+	RegisterHook("context_window", newContextWindowHook)
+	RegisterHook("no_repeat", func(HookOptions) (Hook, error) {
+		return noRepeatHook{}, nil
+	})
+	RegisterHook("length", newLengthHook)
+	RegisterHook("redact", newRedactHook)
+}
+
+// cleanContentHook removes thinking blocks and signatures that leaked into a reply
+type cleanContentHook struct{}
+
+func (cleanContentHook) Name() string { return "clean_content" }
+
+func (cleanContentHook) AfterTurn(_ context.Context, tc *TurnContext) error {
+	tc.Content = cleanMessageContent(tc.Content)
+	return nil
+}
+
+// contextWindowHook keeps the topic message and only the latest messages of
+// the transcript, for models with small context windows
+type contextWindowHook struct {
+	messages int
+}
+
+func newContextWindowHook(opts HookOptions) (Hook, error) {
+	n, err := opts.Int("messages", 20)
+	if err != nil {
+		return nil, err
+	}
+	if n < 1 {
+		return nil, errors.New("messages must be at least 1")
+	}
+	return contextWindowHook{messages: n}, nil
+}
+
+func (contextWindowHook) Name() string { return "context_window" }
+
+func (h contextWindowHook) BeforeTurn(_ context.Context, tc *TurnContext) error {
+	if len(tc.Messages) <= h.messages+1 {
+		return nil
+	}
+	tc.Messages = append(tc.Messages[:1:1], tc.Messages[len(tc.Messages)-h.messages:]...)
+	return nil
+}
+
+// noRepeatHook vetoes a turn when the agent also wrote the previous message,
+// unless it is answering an interjection addressed to it
+type noRepeatHook struct{}
+
+func (noRepeatHook) Name() string { return "no_repeat" }
+
+func (noRepeatHook) BeforeTurn(_ context.Context, tc *TurnContext) error {
+	if tc.Addressed {
+		return nil
+	}
+	spoken := agentMessages(tc.History)
+	if n := len(spoken); n > 0 && spoken[n-1].AgentID == tc.Agent.ID {
+		return fmt.Errorf("%s spoke last", tc.Agent.Name)
+	}
+	return nil
+}
+
+// lengthHook rejects replies outside a word range, asking the agent to
+// rewrite them up to retries times, and annotates the word count
+type lengthHook struct {
+	minWords, maxWords, retries int
+}
+
+func newLengthHook(opts HookOptions) (Hook, error) {
+	var h lengthHook
+	var err error
+	if h.minWords, err = opts.Int("min_words", 0); err != nil {
+		return nil, err
+	}
+	if h.maxWords, err = opts.Int("max_words", 0); err != nil {
+		return nil, err
+	}
+	if h.retries, err = opts.Int("retries", 1); err != nil {
+		return nil, err
+	}
+	if h.maxWords > 0 && h.minWords > h.maxWords {
+		return nil, errors.New("min_words is greater than max_words")
+	}
+	return h, nil
+}
+
+func (lengthHook) Name() string { return "length" }
+
+func (h lengthHook) AfterTurn(_ context.Context, tc *TurnContext) error {
+	words := len(strings.Fields(tc.Content))
+	tc.Annotate("words", strconv.Itoa(words))
+	var reason string
+	switch {
+	case words < h.minWords:
+		reason = fmt.Sprintf("too short: %d words, at least %d required", words, h.minWords)
+	case h.maxWords > 0 && words > h.maxWords:
+		reason = fmt.Sprintf("too long: %d words, at most %d allowed", words, h.maxWords)
+	default:
+		return nil
+	}
+	return &Rejection{Reason: reason, Retry: tc.Attempt < h.retries}
+}
+
+// redactHook masks listed words in replies as they stream, and again on the
+// full reply to catch words split across chunks
+type redactHook struct {
+	replacer *strings.Replacer
+}
+
+func newRedactHook(opts HookOptions) (Hook, error) {
+	words, err := opts.Strings("words")
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, errors.New("words is required")
+	}
+	mask, err := opts.String("replacement", "***")
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]string, 0, 2*len(words))
+	for _, w := range words {
+		if w == "" {
+			return nil, errors.New("words must not be empty")
+		}
+		pairs = append(pairs, w, mask)
+	}
+	return redactHook{replacer: strings.NewReplacer(pairs...)}, nil
+}
+
+func (redactHook) Name() string { return "redact" }
+
+func (h redactHook) OnChunk(_ *TurnContext, chunk string) string {
+	return h.replacer.Replace(chunk)
+}
+
+func (h redactHook) AfterTurn(_ context.Context, tc *TurnContext) error {
+	tc.Content = h.replacer.Replace(tc.Content)
+	return nil
+}
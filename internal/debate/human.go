@@ -0,0 +1,209 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// DefaultHumanTimeout is how long a human seat's turn waits before it is skipped
+const DefaultHumanTimeout = 5 * time.Minute
+
+// ErrNoHumanTurn is returned when submitting for a human who isn't being waited on
+var ErrNoHumanTurn = errors.New("no human turn is waiting")
+
+// Outcomes of a human turn
+const (
+	HumanAnswered = "answered"
+	HumanSkipped  = "skipped"
+	HumanTimedOut = "timeout"
+)
+
+// HumanTurn describes a human seat the debate is waiting on
+type HumanTurn struct {
+	AgentID   string    `json:"agent_id"`
+	AgentName string    `json:"agent_name"`
+	MessageID string    `json:"message_id"`
+	Deadline  time.Time `json:"deadline"`
+}
+
+// humanReply is what the person sent: text, or a skip
+type humanReply struct {
+	content string
+	skip    bool
+}
+
+// pendingHuman is the human turn in progress
+type pendingHuman struct {
+	HumanTurn
+	reply chan humanReply
+}
+
+// SetHumanTimeout sets how long human turns wait (0 = DefaultHumanTimeout)
+func (m *Manager) SetHumanTimeout(d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("human timeout must not be negative")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.humanTimeout = d
+	return nil
+}
+
+// GetHumanTimeout returns how long human turns wait
+func (m *Manager) GetHumanTimeout() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.humanTimeout == 0 {
+		return DefaultHumanTimeout
+	}
+	return m.humanTimeout
+}
+
+// GetHumanTurn returns the human turn being waited on, nil if none
+func (m *Manager) GetHumanTurn() *HumanTurn {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.pendingHuman == nil {
+		return nil
+	}
+	turn := m.pendingHuman.HumanTurn
+	return &turn
+}
+
+// SubmitHuman answers the waiting human turn. An empty agentID matches
+// whichever human is being waited on.
+func (m *Manager) SubmitHuman(agentID, content string) error {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return fmt.Errorf("content is required")
+	}
+	return m.replyHuman(agentID, humanReply{content: content})
+}
+
+// SkipHuman passes the waiting human turn without a message
+func (m *Manager) SkipHuman(agentID string) error {
+	return m.replyHuman(agentID, humanReply{skip: true})
+}
+
+func (m *Manager) replyHuman(agentID string, reply humanReply) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p := m.pendingHuman
+	if p == nil || (agentID != "" && p.AgentID != agentID) {
+		return ErrNoHumanTurn
+	}
+	m.pendingHuman = nil
+	p.reply <- reply // Buffered, and only sent once since pendingHuman is cleared
+	return nil
+}
+
+// waitHuman blocks until the person answers, skips, or the turn times out.
+// It returns the answer (empty unless answered) and the outcome; a stopped
+// debate returns ctx's error.
+func (m *Manager) waitHuman(ctx context.Context, a *agent.Agent, msgID string) (string, string, error) {
+	timeout := m.GetHumanTimeout()
+	p := &pendingHuman{
+		HumanTurn: HumanTurn{
+			AgentID:   a.ID,
+			AgentName: a.Name,
+			MessageID: msgID,
+			Deadline:  time.Now().Add(timeout),
+		},
+		reply: make(chan humanReply, 1),
+	}
+
+	m.mu.Lock()
+	m.pendingHuman = p
+	m.mu.Unlock()
+
+	m.emit(map[string]interface{}{
+		"type": "human_turn",
+		"turn": p.HumanTurn,
+	})
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var content, outcome string
+	select {
+	case reply := <-p.reply:
+		if reply.skip {
+			outcome = HumanSkipped
+		} else {
+			content, outcome = reply.content, HumanAnswered
+		}
+	case <-timer.C:
+		outcome = HumanTimedOut
+	case <-ctx.Done():
+	}
+
+	m.mu.Lock()
+	if m.pendingHuman == p {
+		m.pendingHuman = nil
+	}
+	m.mu.Unlock()
+
+	if outcome == "" {
+		outcome = HumanSkipped
+	}
+	m.emit(map[string]interface{}{
+		"type":       "human_turn_ended",
+		"agent_id":   a.ID,
+		"message_id": msgID,
+		"outcome":    outcome,
+	})
+	if ctx.Err() != nil && content == "" {
+		return "", outcome, ctx.Err()
+	}
+	return content, outcome, nil
+}
+
+// humanTurn runs a human seat's turn: the debate waits for the person's text,
+// which then goes through the stream and post-turn hooks like a model reply
+// (a rejected answer is dropped, not retried). A skipped or timed out turn
+// still counts toward the format schedule when advance is set.
+func (m *Manager) humanTurn(ctx context.Context, tc *TurnContext, pipeline *Pipeline, advance bool, streamCh chan<- StreamMessage) error {
+	a := tc.Agent
+	content, outcome, err := m.waitHuman(ctx, a, tc.MessageID)
+	if err != nil {
+		// Stopped while waiting: end quietly like a cancelled model turn
+		m.mu.Lock()
+		m.isTurnInProgress = false
+		m.mu.Unlock()
+		streamCh <- StreamMessage{Type: "end", AgentID: a.ID, MessageID: tc.MessageID, Audience: tc.Audience}
+		return nil
+	}
+
+	if outcome != HumanAnswered {
+		m.skipTurn(a, tc.MessageID, advance, streamCh)
+		return nil
+	}
+
+	streamCh <- StreamMessage{
+		Type:      "start",
+		AgentID:   a.ID,
+		AgentName: a.Name,
+		MessageID: tc.MessageID,
+		Color:     a.Color,
+		Audience:  tc.Audience,
+	}
+	tc.Content = pipeline.onChunk(tc, content)
+	streamCh <- StreamMessage{
+		Type:      "chunk",
+		AgentID:   a.ID,
+		Content:   tc.Content,
+		MessageID: tc.MessageID,
+		Audience:  tc.Audience,
+	}
+	if err := pipeline.afterTurn(ctx, tc); err != nil {
+		m.dropTurn(tc, "turn_rejected", err, advance, streamCh)
+		return nil
+	}
+	m.commitTurn(ctx, tc, advance, streamCh)
+	return nil
+}
@@ -0,0 +1,74 @@
+package debate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// humanTurnWith plays one turn, answering the human seat with reply once the
+// debate waits on it
+func humanTurnWith(t *testing.T, m *Manager, reply func() error) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		play(t, m, m.NextTurn)
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for m.GetHumanTurn() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("the debate never waited on the human seat")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := reply(); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+}
+
+func TestHumanSeatAnswersAndSkips(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.agents[0].Kind = agent.KindHuman
+	start(t, m, "Thuế carbon")
+
+	humanTurnWith(t, m, func() error {
+		if err := m.SubmitHuman("a2", "không phải lượt tôi"); err != ErrNoHumanTurn {
+			t.Errorf("submitting for a2 = %v, want ErrNoHumanTurn", err)
+		}
+		return m.SubmitHuman("", "  Tôi ủng hộ thuế carbon.  ")
+	})
+	nextTurns(t, m, 2)
+	humanTurnWith(t, m, func() error { return m.SkipHuman("a1") })
+
+	msgs := m.GetMessages()
+	if len(msgs) != 3 || msgs[0].AgentID != "a1" || msgs[0].Content != "Tôi ủng hộ thuế carbon." {
+		t.Fatalf("messages = %+v, want the human answer and no message for the skip", msgs)
+	}
+	if m.GetHumanTurn() != nil {
+		t.Error("a human turn is still waiting")
+	}
+	if err := m.SubmitHuman("a1", "muộn rồi"); err != ErrNoHumanTurn {
+		t.Errorf("submitting with no turn waiting = %v, want ErrNoHumanTurn", err)
+	}
+}
+
+func TestHumanSeatTimesOut(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.agents[0].Kind = agent.KindHuman
+	if err := m.SetHumanTimeout(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetHumanTimeout(-time.Second); err == nil {
+		t.Error("accepted a negative timeout")
+	}
+	start(t, m, "Thuế carbon")
+
+	nextTurns(t, m, 2)
+	msgs := m.GetMessages()
+	if len(msgs) != 1 || msgs[0].AgentID != "a2" {
+		t.Errorf("messages = %+v, want only a2's reply after the timed out seat", msgs)
+	}
+}
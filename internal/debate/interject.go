@@ -0,0 +1,88 @@
+package debate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// Interjections are stored as messages from the human moderator, named by
+// the prompt set's interjection_name
+const (
+	InterjectionAgentID = "moderator"
+	interjectionColor   = "#F39C12"
+)
+
+// Interject adds a human moderator message (a question, new constraint or
+// fact) that every agent sees from the next turn on. When addressed to an
+// agent, that agent answers next. A non-empty audience makes it a private
+// hint only those agents (and the addressee) see.
+func (m *Manager) Interject(content, to string, audience []string) (*Message, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("content is required")
+	}
+
+	m.mu.Lock()
+	if !m.isRunning {
+		m.mu.Unlock()
+		return nil, ErrNotRunning
+	}
+	if to != "" && m.findAgentLocked(to) == nil {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("agent not found: %s", to)
+	}
+	var include []string
+	if to != "" {
+		include = append(include, to)
+	}
+	audience, err := m.audienceLocked(audience, include...)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	m.msgCounter++
+	msg := Message{
+		ID:        fmt.Sprintf("msg_%d", m.msgCounter),
+		AgentID:   InterjectionAgentID,
+		AgentName: renderPrompt(m.promptSet, "interjection_name", PromptData{}),
+		Content:   content,
+		Timestamp: time.Now(),
+		Color:     interjectionColor,
+		To:        to,
+		Audience:  audience,
+	}
+	m.appendMessageLocked(msg)
+	m.addressee = to
+	msg = m.messages[len(m.messages)-1]
+	m.mu.Unlock()
+
+	m.persist()
+	m.emit(map[string]interface{}{
+		"type":    "interjection",
+		"message": msg,
+	})
+	return &msg, nil
+}
+
+// takeAddresseeLocked returns the agent the last interjection asked directly
+// and clears it, nil if none; callers must hold m.mu
+func (m *Manager) takeAddresseeLocked() *agent.Agent {
+	if m.addressee == "" {
+		return nil
+	}
+	a := m.findAgentLocked(m.addressee)
+	m.addressee = ""
+	return a
+}
+
+// agentNameLocked returns a panel agent's name, the ID itself if the agent
+// left and "" for an empty ID; callers must hold m.mu
+func (m *Manager) agentNameLocked(id string) string {
+	if a := m.findAgentLocked(id); a != nil {
+		return a.Name
+	}
+	return id
+}
@@ -0,0 +1,50 @@
+package debate
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterjectionGivesAddresseeTheFloor(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Thuế carbon")
+	nextTurns(t, m, 1)
+
+	msg, err := m.Interject("  Còn chi phí thì sao?  ", "a3", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.AgentID != InterjectionAgentID || msg.Content != "Còn chi phí thì sao?" || msg.To != "a3" {
+		t.Errorf("interjection = %+v", msg)
+	}
+	nextTurns(t, m, 1)
+
+	msgs := m.GetMessages()
+	if len(msgs) != 3 || msgs[2].AgentID != "a3" {
+		t.Fatalf("messages = %+v, want a3 answering the interjection", msgs)
+	}
+	m.mu.Lock()
+	built := m.buildContextLocked(m.agents[1], m.messages)
+	m.mu.Unlock()
+	var seen bool
+	for _, b := range built {
+		seen = seen || strings.Contains(b.Content, "Còn chi phí thì sao?")
+	}
+	if !seen {
+		t.Error("the interjection is missing from the next speaker's context")
+	}
+}
+
+func TestInterjectRejectsBadInput(t *testing.T) {
+	m, _ := newTestManager(t)
+	if _, err := m.Interject("câu hỏi", "", nil); err != ErrNotRunning {
+		t.Errorf("interjecting before the debate = %v, want ErrNotRunning", err)
+	}
+	start(t, m, "Thuế carbon")
+	if _, err := m.Interject("   ", "", nil); err == nil {
+		t.Error("accepted an empty interjection")
+	}
+	if _, err := m.Interject("câu hỏi", "a9", nil); err == nil {
+		t.Error("accepted an addressee not on the panel")
+	}
+}
@@ -0,0 +1,215 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// ErrNothingToJudge is returned when no participant has spoken yet
+var ErrNothingToJudge = errors.New("no messages to judge")
+
+// Criterion is one rubric item the judge scores from 0 to 10
+type Criterion struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Weight      float64 `json:"weight,omitempty"` // Relative weight in the total (default 1)
+}
+
+// defaultCriteria are the criteria used when judging without a custom rubric
+var defaultCriteria = []string{"logic", "evidence", "rebuttal", "clarity"}
+
+// defaultRubric returns the default criteria named and described by a prompt
+// set's criterion_<id> and criterion_<id>_description templates
+func defaultRubric(setID string) []Criterion {
+	rubric := make([]Criterion, len(defaultCriteria))
+	for i, id := range defaultCriteria {
+		rubric[i] = Criterion{
+			ID:          id,
+			Name:        renderPrompt(setID, "criterion_"+id, PromptData{}),
+			Description: renderPrompt(setID, "criterion_"+id+"_description", PromptData{}),
+		}
+	}
+	return rubric
+}
+
+// AgentScore is the judge's rubric scores for one participant
+type AgentScore struct {
+	AgentID   string             `json:"agent_id"`
+	AgentName string             `json:"agent_name"`
+	Scores    map[string]float64 `json:"scores"` // Criterion ID -> 0-10
+	Total     float64            `json:"total"`  // Weighted average of the scores
+	Comment   string             `json:"comment,omitempty"`
+}
+
+// Verdict is the judge's structured result for a debate
+type Verdict struct {
+	Judge       string       `json:"judge"`
+	Rubric      []Criterion  `json:"rubric"`
+	Scores      []AgentScore `json:"scores"`
+	Winner      string       `json:"winner"`
+	WinnerName  string       `json:"winner_name"`
+	WinningSide Side         `json:"winning_side,omitempty"`
+	Rationale   string       `json:"rationale"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// judgeResponse is the JSON shape the judge is asked to return
+type judgeResponse struct {
+	Scores map[string]struct {
+		Scores  map[string]float64 `json:"scores"`
+		Comment string             `json:"comment"`
+	} `json:"scores"`
+	Winner    string `json:"winner"`
+	Rationale string `json:"rationale"`
+}
+
+// ValidateRubric checks criterion IDs are present and unique and weights non-negative
+func ValidateRubric(rubric []Criterion) error {
+	seen := make(map[string]bool, len(rubric))
+	for _, c := range rubric {
+		if c.ID == "" {
+			return fmt.Errorf("rubric criterion id is required")
+		}
+		if seen[c.ID] {
+			return fmt.Errorf("duplicate rubric criterion: %s", c.ID)
+		}
+		if c.Weight < 0 {
+			return fmt.Errorf("rubric weight must not be negative: %s", c.ID)
+		}
+		seen[c.ID] = true
+	}
+	return nil
+}
+
+// Judge asks the judge agent to score the current transcript on the rubric
+// (the default one if empty). The verdict is stored with the debate.
+func (m *Manager) Judge(ctx context.Context, judge *agent.Agent, rubric []Criterion) (*Verdict, error) {
+	if err := ValidateRubric(rubric); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	setID := m.promptSet
+	if len(rubric) == 0 {
+		rubric = defaultRubric(setID)
+	}
+	topic := m.topic
+	messages := append([]Message(nil), m.messages...)
+	sides := m.sides
+	// Only agents who actually spoke are scored
+	spoke := make(map[string]bool)
+	for _, msg := range messages {
+		spoke[msg.AgentID] = true
+	}
+	var participants []*agent.Agent
+	for _, a := range m.agents {
+		if spoke[a.ID] {
+			participants = append(participants, a)
+		}
+	}
+	m.mu.RUnlock()
+
+	if len(participants) == 0 {
+		return nil, ErrNothingToJudge
+	}
+
+	system := renderPrompt(setID, "judge_system", PromptData{})
+	resp, err := askAgent(ctx, judge, system, judgePrompt(setID, topic, participants, sides, rubric, messages))
+	if err != nil {
+		return nil, err
+	}
+	var parsed judgeResponse
+	if err := extractJSON(resp, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid judge response: %w", err)
+	}
+
+	verdict := &Verdict{
+		Judge:     judge.Name,
+		Rubric:    rubric,
+		Rationale: parsed.Rationale,
+		CreatedAt: time.Now(),
+	}
+	for _, a := range participants {
+		score := AgentScore{AgentID: a.ID, AgentName: a.Name, Scores: make(map[string]float64)}
+		entry, ok := parsed.Scores[a.ID]
+		if !ok {
+			entry, ok = parsed.Scores[a.Name]
+		}
+		if ok {
+			score.Comment = entry.Comment
+		}
+
+		var sum, weights float64
+		for _, c := range rubric {
+			v := clampScore(entry.Scores[c.ID])
+			score.Scores[c.ID] = v
+			w := c.Weight
+			if w == 0 {
+				w = 1
+			}
+			sum += v * w
+			weights += w
+		}
+		if weights > 0 {
+			score.Total = sum / weights
+		}
+		verdict.Scores = append(verdict.Scores, score)
+	}
+
+	// Trust the judge's winner if it names a participant, otherwise take the top total
+	winner := findAgent(participants, parsed.Winner)
+	if winner == nil {
+		best := -1.0
+		for i, score := range verdict.Scores {
+			if score.Total > best {
+				best = score.Total
+				winner = participants[i]
+			}
+		}
+	}
+	verdict.Winner = winner.ID
+	verdict.WinnerName = winner.Name
+	if side, ok := sides[winner.ID]; ok && side != SideNeutral {
+		verdict.WinningSide = side
+	}
+
+	m.mu.Lock()
+	m.verdict = verdict
+	m.mu.Unlock()
+	m.persist()
+
+	return verdict, nil
+}
+
+// GetVerdict returns the stored verdict for the current debate, nil if not judged
+func (m *Manager) GetVerdict() *Verdict {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.verdict
+}
+
+// judgePrompt builds the judge request with the rubric and full transcript
+func judgePrompt(setID, topic string, participants []*agent.Agent, sides map[string]Side, rubric []Criterion, messages []Message) string {
+	return renderPrompt(setID, "judge", PromptData{
+		Topic:      topic,
+		Agents:     promptAgents(participants, sides),
+		Rubric:     rubric,
+		Transcript: recentTranscript(messages, 0),
+	})
+}
+
+// clampScore keeps a rubric score within 0-10
+func clampScore(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 10 {
+		return 10
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+package debate
+
+import (
+	"context"
+	"testing"
+)
+
+func TestJudgeWeighsRubricAndPicksTopTotal(t *testing.T) {
+	m, _ := newTestManager(t)
+	if err := m.SetFormat("oxford", nil); err != nil {
+		t.Fatal(err)
+	}
+	start(t, m, "Thuế carbon")
+	judge := testAgent("j", "Trọng tài", replyProvider{reply: `Kết quả:
+{"scores": {
+  "a1": {"scores": {"logic": 12, "evidence": 4}, "comment": "chặt chẽ"},
+  "Gamma": {"scores": {"logic": 6, "evidence": 9}}
+}, "winner": "không rõ", "rationale": "sát nút"}`})
+	rubric := []Criterion{{ID: "logic", Name: "Lập luận", Weight: 3}, {ID: "evidence", Name: "Dẫn chứng"}}
+
+	if _, err := m.Judge(context.Background(), judge, rubric); err != ErrNothingToJudge {
+		t.Errorf("judging an empty debate = %v, want ErrNothingToJudge", err)
+	}
+	nextTurns(t, m, 2)
+
+	verdict, err := m.Judge(context.Background(), judge, rubric)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verdict.Scores) != 2 {
+		t.Fatalf("scores = %+v, want the two pro openers, a1 and a3", verdict.Scores)
+	}
+	// a1: logic clamped to 10, (10*3 + 4) / 4; a3 matched by name: (6*3 + 9) / 4
+	if a1, a3 := verdict.Scores[0], verdict.Scores[1]; a1.Total != 8.5 || a1.Comment != "chặt chẽ" || a3.Total != 6.75 {
+		t.Errorf("scores = %+v", verdict.Scores)
+	}
+	if verdict.Winner != "a1" || verdict.WinningSide != SidePro || verdict.Rationale != "sát nút" {
+		t.Errorf("verdict = %+v, want a1 winning for pro", verdict)
+	}
+	if m.GetVerdict() != verdict {
+		t.Error("the verdict was not stored")
+	}
+}
+
+func TestValidateRubric(t *testing.T) {
+	for _, rubric := range [][]Criterion{
+		{{Name: "no id"}},
+		{{ID: "logic"}, {ID: "logic"}},
+		{{ID: "logic", Weight: -1}},
+	} {
+		if err := ValidateRubric(rubric); err == nil {
+			t.Errorf("ValidateRubric(%+v) accepted an invalid rubric", rubric)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/user/talk/internal/agent"
+	"github.com/user/talk/internal/provider"
+)
+
+// askAgent sends a one-off prompt to an agent's model with a task-specific
+// system prompt (replacing its persona) and returns the full response text.
+func askAgent(ctx context.Context, a *agent.Agent, system, prompt string) (string, error) {
+	return streamAgent(ctx, a, system, prompt, nil)
+}
+
+// streamAgent is askAgent with onChunk called for every content chunk as it arrives
+func streamAgent(ctx context.Context, a *agent.Agent, system, prompt string, onChunk func(string)) (string, error) {
+	helper := *a
+	helper.SystemPrompt = system
+
+	respCh, err := helper.Chat(ctx, []provider.Message{{Role: "user", Content: prompt}}, provider.Options{})
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for chunk := range respCh {
+		if chunk.Error != nil {
+			return "", chunk.Error
+		}
+		if chunk.Content != "" {
+			sb.WriteString(chunk.Content)
+			if onChunk != nil {
+				onChunk(chunk.Content)
+			}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	// Only strip reasoning blocks; full cleaning would mangle JSON output
+	return strings.TrimSpace(thinkingBlockRegex.ReplaceAllString(sb.String(), "")), nil
+}
+
+// extractJSON unmarshals the outermost JSON object in text into v.
+// Models often wrap structured output in prose or code fences.
+func extractJSON(text string, v interface{}) error {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end <= start {
+		return fmt.Errorf("no JSON object in response")
+	}
+	return json.Unmarshal([]byte(text[start:end+1]), v)
+}
+
+// recentTranscript renders the last n public messages as plain text for
+// helper prompts
+func recentTranscript(messages []Message, n int) string {
+	messages = publicMessages(messages)
+	if n > 0 && len(messages) > n {
+		messages = messages[len(messages)-n:]
+	}
+	var sb strings.Builder
+	for _, msg := range messages {
+		if msg.AgentID == "system" {
+			continue
+		}
+		fmt.Fprintf(&sb, "[%s] %s:\n%s\n\n", msg.AgentID, msg.AgentName, msg.Content)
+	}
+	return strings.TrimSpace(sb.String())
+}
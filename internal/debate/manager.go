@@ -2,6 +2,7 @@ package debate
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -30,6 +31,15 @@ var (
 	contentArrayRegex    = regexp.MustCompile(`(?s)\[\s*\{[^]]*"type"\s*:\s*"(thinking|text)"[^]]*\}\s*(,\s*\{[^]]*\}\s*)*\]`)
 )
 
+// Errors returned by turn execution that callers may want to handle specifically
+var (
+	ErrNotRunning     = errors.New("debate is not running")
+	ErrTurnInProgress = errors.New("a turn is already in progress")
+)
+
+// EventFunc receives manager events (stream messages, run progress) for broadcasting
+type EventFunc func(event interface{})
+
 // Mode represents the debate mode
 type Mode string
 
@@ -40,23 +50,45 @@ const (
 
 // Message represents a debate message
 type Message struct {
-	ID        string    `json:"id"`
-	AgentID   string    `json:"agent_id"`
-	AgentName string    `json:"agent_name"`
-	Content   string    `json:"content"`
-	Timestamp time.Time `json:"timestamp"`
-	Color     string    `json:"color"`
+	ID        string              `json:"id"`
+	AgentID   string              `json:"agent_id"`
+	AgentName string              `json:"agent_name"`
+	Content   string              `json:"content"`
+	Timestamp time.Time           `json:"timestamp"`
+	Color     string              `json:"color"`
+	Citations []provider.Citation `json:"citations,omitempty"`
+	ParentID  string              `json:"parent_id,omitempty"` // Message this one follows
+	Children  []string            `json:"children,omitempty"`  // Branches forked after this message
+	Edits     []MessageEdit       `json:"edits,omitempty"`     // Previous versions, oldest first
+	To        string              `json:"to,omitempty"`        // Agent an interjection is addressed to
+	Audience  []string            `json:"audience,omitempty"`  // Agents a private message is shown to; empty for everyone
+	// Annotations are notes post-turn hooks attached to the message
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Meta records the provider, model and timing of a model's reply
+	Meta *MessageMeta `json:"meta,omitempty"`
 }
 
 // StreamMessage represents a streaming message chunk
 type StreamMessage struct {
-	Type      string `json:"type"` // "start", "chunk", "end", "error"
+	Type      string `json:"type"` // "start", "chunk", "retry", "end", "error"
 	AgentID   string `json:"agent_id,omitempty"`
 	AgentName string `json:"agent_name,omitempty"`
-	Content   string `json:"content,omitempty"`
+	Content   string `json:"content,omitempty"` // Chunk text; the committed content on "end" events
 	MessageID string `json:"message_id,omitempty"`
 	Color     string `json:"color,omitempty"`
 	Error     string `json:"error,omitempty"`
+	// Citations are attached to "end" events for sources-backed responses
+	Citations []provider.Citation `json:"citations,omitempty"`
+	// Annotations are the hooks' notes on the message, attached to "end" events
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Meta is the reply's provider, model and timing, attached to "end" events
+	Meta *MessageMeta `json:"meta,omitempty"`
+	// Kind is the summary kind on summary_* events
+	Kind string `json:"kind,omitempty"`
+	// Parallel marks events from a parallel round, where several streams interleave
+	Parallel bool `json:"parallel,omitempty"`
+	// Audience tags the events of a private turn with the agents it is shown to
+	Audience []string `json:"audience,omitempty"`
 }
 
 // Manager manages the debate between agents
@@ -72,6 +104,39 @@ type Manager struct {
 	ctx              context.Context
 	cancel           context.CancelFunc
 	msgCounter       int
+	run              *runState // Active autonomous run, nil if none
+	emitFn           EventFunc
+	debateID         string       // Persistent ID of the current debate
+	createdAt        time.Time    // When the current debate started
+	recorder         Recorder     // Persists snapshots, nil to keep debates in memory only
+	moderator        *agent.Agent // Picks speakers in free-form mode, nil for rotation
+	selector         SpeakerSelector
+	selectorOpts     SelectorOptions
+	format           *Format         // Structured format, nil for open discussion
+	sides            map[string]Side // Agent ID -> side in formats that use sides
+	phaseIndex       int             // Current phase of the format
+	phaseTurn        int             // Scheduled turns taken in the current phase
+	verdict          *Verdict        // Judge's verdict on the current debate
+	termOpts         TerminationOptions
+	ended            *Termination // Set when a termination condition ended the debate
+	summarizer       *agent.Agent // Writes summaries, nil to use the panel's synthesizer
+	summaries        []Summary
+	summaryCounter   int
+	summaryEvery     int                // Rolling summary interval in agent turns, 0 = off
+	branch           string             // Active branch ID ("" = main)
+	branches         map[string]*Branch // nil until the first fork
+	branchCounter    int
+	addressee        string    // Agent the last interjection asked directly; answers next
+	promptSet        string    // Prompt templates for this debate, "" = the default set
+	pipeline         *Pipeline // Turn hooks, nil for the default pipeline
+	humanTimeout     time.Duration
+	pendingHuman     *pendingHuman  // Human turn being waited on
+	eventMu          sync.Mutex     // Orders event log appends
+	eventDebate      string         // Debate eventSeq counts for
+	eventSeq         int64          // Last logged event's sequence number
+	playback         *playbackState // Replay being streamed, nil if none
+	agenda           []AgendaItem   // Sub-topics discussed in order, nil for none
+	agendaCounter    int
 }
 
 // NewManager creates a new debate manager
@@ -80,14 +145,48 @@ func NewManager(agents []*agent.Agent) *Manager {
 		agents:   agents,
 		messages: make([]Message, 0),
 		mode:     ModeRoundRobin,
+		selector: roundRobinSelector{},
+	}
+}
+
+// SetEventFunc sets the callback used to publish manager-originated events
+func (m *Manager) SetEventFunc(fn EventFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.emitFn = fn
+}
+
+// emit publishes an event if an event callback is set
+func (m *Manager) emit(event interface{}) {
+	m.mu.RLock()
+	fn := m.emitFn
+	m.mu.RUnlock()
+	if fn != nil {
+		fn(event)
 	}
 }
 
-// SetMode sets the debate mode
-func (m *Manager) SetMode(mode Mode) {
+// SetMode sets the debate mode and its speaker selector
+func (m *Manager) SetMode(mode Mode, opts SelectorOptions) error {
+	selector, err := NewSelector(mode, opts)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mode = mode
+	m.selector = selector
+	m.selectorOpts = opts
+	return nil
+}
+
+// SetSelector installs a custom speaker selector under the given mode name
+func (m *Manager) SetSelector(mode Mode, selector SpeakerSelector) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.mode = mode
+	m.selector = selector
+	m.selectorOpts = SelectorOptions{}
 }
 
 // GetMode returns current debate mode
@@ -97,6 +196,13 @@ func (m *Manager) GetMode() Mode {
 	return m.mode
 }
 
+// GetModeOptions returns the speaker selector's options
+func (m *Manager) GetModeOptions() SelectorOptions {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.selectorOpts
+}
+
 // Start begins a debate with the given topic
 func (m *Manager) Start(topic string) error {
 	m.mu.Lock()
@@ -107,38 +213,70 @@ func (m *Manager) Start(topic string) error {
 	m.topic = topic
 	m.messages = make([]Message, 0)
 	m.currentIndex = 0
+	m.phaseIndex = 0
+	m.phaseTurn = 0
+	m.verdict = nil
+	m.ended = nil
+	m.summaries = nil
+	m.summaryCounter = 0
+	m.branch, m.branches, m.branchCounter = "", nil, 0
+	m.addressee = ""
+	m.restartAgendaLocked()
 	m.isRunning = true
 	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.debateID = newDebateID()
+	m.createdAt = time.Now()
 	m.mu.Unlock()
+
+	m.persist()
+	m.logCreated()
 	return nil
 }
 
 // Continue changes topic but keeps conversation history
 func (m *Manager) Continue(newTopic string) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	m.changeTopicLocked(newTopic)
+	m.ended = nil
+	m.isRunning = true
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	created := m.debateID == ""
+	if created {
+		m.debateID = newDebateID()
+		m.createdAt = time.Now()
+	}
+	m.mu.Unlock()
 
-	// Add a system message about topic change
+	m.persist()
+	if created {
+		m.logCreated()
+	}
+	return nil
+}
+
+// changeTopicLocked switches to a new topic, noting the change in the
+// transcript if there is one, and returns the note's ID; callers must hold m.mu
+func (m *Manager) changeTopicLocked(topic string) string {
+	id := ""
 	if len(m.messages) > 0 {
 		m.msgCounter++
-		m.messages = append(m.messages, Message{
-			ID:        fmt.Sprintf("msg_%d", m.msgCounter),
+		id = fmt.Sprintf("msg_%d", m.msgCounter)
+		m.appendMessageLocked(Message{
+			ID:        id,
 			AgentID:   "system",
-			AgentName: "Hệ thống",
-			Content:   fmt.Sprintf("--- Chuyển sang chủ đề mới: %s ---", newTopic),
+			AgentName: renderPrompt(m.promptSet, "system_name", PromptData{}),
+			Content:   renderPrompt(m.promptSet, "topic_change", PromptData{Topic: topic}),
 			Timestamp: time.Now(),
 			Color:     "#888888",
 		})
 	}
-
-	m.topic = newTopic
-	m.isRunning = true
-	m.ctx, m.cancel = context.WithCancel(context.Background())
-	return nil
+	m.topic = topic
+	return id
 }
 
 // Stop stops the current debate
 func (m *Manager) Stop() {
+	m.StopPlayback()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.cancel != nil {
@@ -186,142 +324,162 @@ func (m *Manager) NextTurn(streamCh chan<- StreamMessage) error {
 	m.mu.Lock()
 	if !m.isRunning {
 		m.mu.Unlock()
-		return fmt.Errorf("debate is not running")
+		return ErrNotRunning
 	}
 	if m.isTurnInProgress {
 		m.mu.Unlock()
-		return fmt.Errorf("a turn is already in progress")
+		return ErrTurnInProgress
 	}
 	if len(m.agents) == 0 {
 		m.mu.Unlock()
 		return fmt.Errorf("no agents available")
 	}
 	m.isTurnInProgress = true
-
-	var currentAgent *agent.Agent
-	if m.mode == ModeRoundRobin {
-		currentAgent = m.agents[m.currentIndex]
-		m.currentIndex = (m.currentIndex + 1) % len(m.agents)
-	} else {
-		// Free form: pick next agent based on context
-		currentAgent = m.selectNextAgent()
-	}
-
 	ctx := m.ctx
 	m.mu.Unlock()
 
-	// Build conversation context
-	messages := m.buildContext(currentAgent)
-
-	// Generate message ID
-	m.mu.Lock()
-	m.msgCounter++
-	msgID := fmt.Sprintf("msg_%d", m.msgCounter)
-	m.mu.Unlock()
-
-	// Send start event
-	streamCh <- StreamMessage{
-		Type:      "start",
-		AgentID:   currentAgent.ID,
-		AgentName: currentAgent.Name,
-		MessageID: msgID,
-		Color:     currentAgent.Color,
+	if err := m.prepareAgendaTurn(ctx, streamCh); err != nil {
+		m.mu.Lock()
+		m.isTurnInProgress = false
+		m.mu.Unlock()
+		return err
 	}
 
-	// Get streaming response
-	respCh, err := currentAgent.Chat(ctx, messages, provider.Options{})
+	currentAgent, addressed, err := m.pickSpeaker(ctx)
 	if err != nil {
-		streamCh <- StreamMessage{
-			Type:  "error",
-			Error: err.Error(),
-		}
+		m.mu.Lock()
+		m.isTurnInProgress = false
+		m.mu.Unlock()
 		return err
 	}
 
-	// Collect full response
-	var fullContent strings.Builder
-
-	for chunk := range respCh {
-		if chunk.Error != nil {
-			// Check if it's a context cancellation (user stopped debate)
-			if chunk.Error == context.Canceled || strings.Contains(chunk.Error.Error(), "context canceled") {
-				m.mu.Lock()
-				m.isTurnInProgress = false
-				m.mu.Unlock()
-				// Don't send error, just end gracefully
-				streamCh <- StreamMessage{
-					Type:      "end",
-					AgentID:   currentAgent.ID,
-					MessageID: msgID,
-				}
-				return nil
-			}
-			m.mu.Lock()
-			m.isTurnInProgress = false
-			m.mu.Unlock()
-			streamCh <- StreamMessage{
-				Type:  "error",
-				Error: chunk.Error.Error(),
-			}
-			return chunk.Error
-		}
+	// A reply to an interjection is outside the format's schedule
+	return m.playTurn(ctx, currentAgent, addressed, !addressed, nil, streamCh)
+}
 
-		if chunk.Content != "" {
-			fullContent.WriteString(chunk.Content)
-			streamCh <- StreamMessage{
-				Type:      "chunk",
-				AgentID:   currentAgent.ID,
-				Content:   chunk.Content,
-				MessageID: msgID,
-			}
-		}
+// commitTurn stores a finished turn's message, releases the turn lock,
+// advances the format schedule if advance is set, sends the end event and
+// runs the post-turn checks, closing the agenda item if its budget ran out
+func (m *Manager) commitTurn(ctx context.Context, tc *TurnContext, advance bool, streamCh chan<- StreamMessage) {
+	m.mu.Lock()
+	content, marker := m.endMarkerLocked(tc.Content)
+	m.appendMessageLocked(turnMessage(tc, content))
+	m.countAgendaTurnsLocked(1)
+	m.isTurnInProgress = false
+	var phaseStatus *FormatStatus
+	if advance && m.advanceFormatLocked() {
+		phaseStatus = m.formatStatusLocked()
+	}
+	m.mu.Unlock()
 
-		if chunk.Done {
-			break
-		}
+	m.persist()
+	if phaseStatus != nil {
+		m.emit(map[string]interface{}{
+			"type":   "phase_changed",
+			"format": phaseStatus,
+		})
 	}
 
-	// Store the message (clean content before storing)
-	rawContent := fullContent.String()
-	cleanedContent := cleanMessageContent(rawContent)
+	streamCh <- endMessage(tc, content, false)
+
+	m.checkTermination(ctx, tc.Agent, marker)
+	m.maybeRollingSummary(ctx, streamCh)
+	m.advanceAgenda(ctx, false, streamCh)
+}
 
-	// Debug: log if content was cleaned
-	if len(rawContent) != len(cleanedContent) {
-		fmt.Printf("[DEBUG] Content cleaned: %d -> %d bytes\n", len(rawContent), len(cleanedContent))
+// endMessage is the end event of a committed turn, with its stored content
+// and what the post-turn hooks left on it
+func endMessage(tc *TurnContext, content string, parallel bool) StreamMessage {
+	return StreamMessage{
+		Type:        "end",
+		AgentID:     tc.Agent.ID,
+		MessageID:   tc.MessageID,
+		Content:     content,
+		Citations:   tc.Citations,
+		Annotations: tc.Annotations,
+		Meta:        tc.Meta,
+		Parallel:    parallel,
+		Audience:    tc.Audience,
 	}
+}
 
+// skipTurn ends a turn that produced no message, still counting it toward the
+// format schedule if advance is set
+func (m *Manager) skipTurn(speaker *agent.Agent, msgID string, advance bool, streamCh chan<- StreamMessage) {
 	m.mu.Lock()
-	m.messages = append(m.messages, Message{
-		ID:        msgID,
-		AgentID:   currentAgent.ID,
-		AgentName: currentAgent.Name,
-		Content:   cleanedContent,
-		Timestamp: time.Now(),
-		Color:     currentAgent.Color,
-	})
 	m.isTurnInProgress = false
+	var phaseStatus *FormatStatus
+	if advance && m.advanceFormatLocked() {
+		phaseStatus = m.formatStatusLocked()
+	}
 	m.mu.Unlock()
 
-	// Send end event
-	streamCh <- StreamMessage{
-		Type:      "end",
-		AgentID:   currentAgent.ID,
-		MessageID: msgID,
+	if phaseStatus != nil {
+		m.persist()
+		m.emit(map[string]interface{}{
+			"type":   "phase_changed",
+			"format": phaseStatus,
+		})
 	}
+	streamCh <- StreamMessage{Type: "end", AgentID: speaker.ID, MessageID: msgID}
+}
 
-	return nil
+// pickSpeaker chooses who speaks next: the agent an interjection addressed,
+// then the format schedule when a structured format is active, the mode's
+// selector otherwise. addressed reports the first case.
+func (m *Manager) pickSpeaker(ctx context.Context) (chosen *agent.Agent, addressed bool, err error) {
+	m.mu.Lock()
+	if a := m.takeAddresseeLocked(); a != nil {
+		m.mu.Unlock()
+		return a, true, nil
+	}
+	if m.format != nil {
+		defer m.mu.Unlock()
+		if a := m.formatSpeakerLocked(); a != nil {
+			return a, false, nil
+		}
+		return nil, false, ErrFormatComplete
+	}
+
+	state := SelectionState{
+		Topic:        m.topic,
+		Agents:       append([]*agent.Agent(nil), m.agents...),
+		Messages:     append([]Message(nil), m.messages...),
+		CurrentIndex: m.currentIndex,
+		Moderator:    m.moderator,
+		PromptSet:    m.promptSet,
+		emit:         m.emitFn,
+	}
+	selector := m.selector
+	m.mu.Unlock()
+
+	// Selectors may call models, so run them outside the lock
+	chosen, err = selector.Select(ctx, &state)
+	if err != nil {
+		return nil, false, err
+	}
+	if chosen == nil {
+		return nil, false, fmt.Errorf("speaker selector returned no agent")
+	}
+
+	m.mu.Lock()
+	// The panel may have changed while the selector ran
+	m.currentIndex = rotationIndex(state.Agents, m.agents, state.CurrentIndex)
+	m.mu.Unlock()
+	return chosen, false, nil
 }
 
-// TurnByAgent executes a specific agent's turn by agent ID
-func (m *Manager) TurnByAgent(agentID string, streamCh chan<- StreamMessage) error {
+// TurnByAgent executes a specific agent's turn by agent ID. A non-empty
+// audience makes it a private turn only those agents (and the speaker) see.
+func (m *Manager) TurnByAgent(agentID string, audience []string, streamCh chan<- StreamMessage) error {
 	m.mu.Lock()
 	if !m.isRunning {
 		m.mu.Unlock()
-		return fmt.Errorf("debate is not running")
+		return ErrNotRunning
 	}
 	if m.isTurnInProgress {
 		m.mu.Unlock()
-		return fmt.Errorf("a turn is already in progress")
+		return ErrTurnInProgress
 	}
 	if len(m.agents) == 0 {
 		m.mu.Unlock()
@@ -341,137 +499,55 @@ func (m *Manager) TurnByAgent(agentID string, streamCh chan<- StreamMessage) err
 		m.mu.Unlock()
 		return fmt.Errorf("agent not found: %s", agentID)
 	}
-
-	ctx := m.ctx
-	m.isTurnInProgress = true
-	m.mu.Unlock()
-
-	// Build conversation context
-	messages := m.buildContext(targetAgent)
-
-	// Generate message ID
-	m.mu.Lock()
-	m.msgCounter++
-	msgID := fmt.Sprintf("msg_%d", m.msgCounter)
-	m.mu.Unlock()
-
-	// Send start event
-	streamCh <- StreamMessage{
-		Type:      "start",
-		AgentID:   targetAgent.ID,
-		AgentName: targetAgent.Name,
-		MessageID: msgID,
-		Color:     targetAgent.Color,
-	}
-
-	// Get streaming response
-	respCh, err := targetAgent.Chat(ctx, messages, provider.Options{})
+	audience, err := m.whisperAudienceLocked(agentID, audience)
 	if err != nil {
-		streamCh <- StreamMessage{
-			Type:  "error",
-			Error: err.Error(),
-		}
+		m.mu.Unlock()
 		return err
 	}
-
-	// Collect full response
-	var fullContent strings.Builder
-
-	for chunk := range respCh {
-		if chunk.Error != nil {
-			// Check if it's a context cancellation (user stopped debate)
-			if chunk.Error == context.Canceled || strings.Contains(chunk.Error.Error(), "context canceled") {
-				m.mu.Lock()
-				m.isTurnInProgress = false
-				m.mu.Unlock()
-				// Don't send error, just end gracefully
-				streamCh <- StreamMessage{
-					Type:      "end",
-					AgentID:   targetAgent.ID,
-					MessageID: msgID,
-				}
-				return nil
-			}
-			m.mu.Lock()
-			m.isTurnInProgress = false
-			m.mu.Unlock()
-			streamCh <- StreamMessage{
-				Type:  "error",
-				Error: chunk.Error.Error(),
-			}
-			return chunk.Error
-		}
-
-		if chunk.Content != "" {
-			fullContent.WriteString(chunk.Content)
-			streamCh <- StreamMessage{
-				Type:      "chunk",
-				AgentID:   targetAgent.ID,
-				Content:   chunk.Content,
-				MessageID: msgID,
-			}
-		}
-
-		if chunk.Done {
-			break
-		}
-	}
-
-	// Store the message (clean content before storing)
-	rawContent := fullContent.String()
-	cleanedContent := cleanMessageContent(rawContent)
-
-	// Debug: log if content was cleaned
-	if len(rawContent) != len(cleanedContent) {
-		fmt.Printf("[DEBUG] Content cleaned: %d -> %d bytes\n", len(rawContent), len(cleanedContent))
+	addressed := m.addressee == agentID
+	if addressed {
+		m.addressee = ""
 	}
 
-	m.mu.Lock()
-	m.messages = append(m.messages, Message{
-		ID:        msgID,
-		AgentID:   targetAgent.ID,
-		AgentName: targetAgent.Name,
-		Content:   cleanedContent,
-		Timestamp: time.Now(),
-		Color:     targetAgent.Color,
-	})
-	m.isTurnInProgress = false
+	ctx := m.ctx
+	m.isTurnInProgress = true
 	m.mu.Unlock()
 
-	// Send end event
-	streamCh <- StreamMessage{
-		Type:      "end",
-		AgentID:   targetAgent.ID,
-		MessageID: msgID,
+	if err := m.prepareAgendaTurn(ctx, streamCh); err != nil {
+		m.mu.Lock()
+		m.isTurnInProgress = false
+		m.mu.Unlock()
+		return err
 	}
 
-	return nil
+	return m.playTurn(ctx, targetAgent, addressed, false, audience, streamCh)
 }
 
 // buildContext builds the conversation context for an agent
 func (m *Manager) buildContext(currentAgent *agent.Agent) []provider.Message {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
+	return m.buildContextLocked(currentAgent, m.messages)
+}
+
+// buildContextLocked builds an agent's context as if history were the whole
+// transcript; callers must hold m.mu
+func (m *Manager) buildContextLocked(currentAgent *agent.Agent, history []Message) []provider.Message {
+	return m.buildPromptLocked(m.promptSet, currentAgent, history)
+}
 
+// buildPromptLocked renders an agent's context with a prompt set; callers
+// must hold m.mu
+func (m *Manager) buildPromptLocked(setID string, currentAgent *agent.Agent, history []Message) []provider.Message {
 	messages := make([]provider.Message, 0)
+	data := m.promptDataLocked(setID, currentAgent, history)
+	history = m.visibleHistoryLocked(currentAgent, history)
+	names := anonymousNames{}
 
 	// Add topic as initial context
-	topicMsg := fmt.Sprintf(`Chủ đề thảo luận: "%s"
-
-Các thành viên tham gia:
-`, m.topic)
-
-	for _, a := range m.agents {
-		topicMsg += fmt.Sprintf("- %s: %s\n", a.Name, a.Role)
-	}
-
-	topicMsg += fmt.Sprintf(`
-Bạn là %s. Hãy đưa ra ý kiến của bạn về chủ đề này.
-Trả lời bằng tiếng Việt, ngắn gọn (2-4 đoạn). KHÔNG cần ghi tên của bạn ở đầu câu trả lời.`, currentAgent.Name)
-
 	messages = append(messages, provider.Message{
 		Role:    "user",
-		Content: topicMsg,
+		Content: renderPrompt(setID, "topic", data),
 	})
 
 	// Add previous messages as context
@@ -480,36 +556,61 @@ Trả lời bằng tiếng Việt, ngắn gọn (2-4 đoạn). KHÔNG cần ghi
 	// When using thinking models, the API requires valid signatures in assistant
 	// messages with thinking blocks, but we cannot preserve those signatures.
 	// The solution is to treat all previous messages as "user" role with agent name prefix.
-	for i, msg := range m.messages {
+	// Replies are cleaned when committed by the clean_content hook; cleaning
+	// again covers pipelines without it, edits and restored debates.
+	for _, msg := range history {
 		// Skip system messages
 		if msg.AgentID == "system" {
 			continue
 		}
 
-		// Clean content: remove any thinking block markers that may have leaked
-		content := cleanMessageContent(msg.Content)
-
-		// Debug: check for any remaining thinking markers
-		if strings.Contains(content, "<thinking") || strings.Contains(content, "signature") {
-			fmt.Printf("[DEBUG] Message %d still contains thinking markers after cleaning!\n", i)
-			fmt.Printf("[DEBUG] First 200 chars: %.200s\n", content)
-		}
-
 		// Always add agent name as context prefix for all messages
 		// This ensures Claude doesn't try to validate thinking signatures
-		content = fmt.Sprintf("**%s** nói:\n%s", msg.AgentName, content)
+		msgData := data
+		msgData.Speaker = msg.AgentName
+		msgData.Content = cleanMessageContent(msg.Content)
+		// Let other agents know which sources backed the claims
+		if len(msg.Citations) > 0 {
+			msgData.Sources = formatSources(msg.Citations)
+		}
+		// Private messages say so; anonymous contexts don't name the audience
+		msgData.Private = len(msg.Audience) > 0
+		if msgData.Private && !data.Anonymous {
+			msgData.Audience = m.audienceNamesLocked(msg.Audience, msg.AgentID)
+		}
+		name := "message"
+		switch {
+		case msg.AgentID == InterjectionAgentID:
+			name = "interjection"
+			msgData.Addressee = m.agentNameLocked(msg.To)
+		case msg.AgentID == summaryContextID:
+			name = "summary"
+		case data.Anonymous && msg.AgentID != currentAgent.ID:
+			msgData.Speaker = renderPrompt(setID, "anonymous_speaker", PromptData{Speaker: names.label(msg.AgentID)})
+		}
 
 		messages = append(messages, provider.Message{
 			Role:    "user",
-			Content: content,
+			Content: renderPrompt(setID, name, msgData),
 		})
 	}
 
-	// Add prompt for continuation
-	if len(m.messages) > 0 {
+	// A closing interjection comes first; structured formats replace the
+	// generic continuation prompt with the phase's instructions
+	closing := ""
+	if n := len(history); n > 0 && history[n-1].AgentID == InterjectionAgentID {
+		data.Addressed = history[n-1].To == currentAgent.ID
+		data.Private = len(history[n-1].Audience) > 0
+		closing = "interjection_reply"
+	} else if data.Phase != nil {
+		closing = "phase"
+	} else if len(history) > 0 {
+		closing = "continue"
+	}
+	if closing != "" {
 		messages = append(messages, provider.Message{
 			Role:    "user",
-			Content: "Hãy tiếp tục thảo luận. Phản hồi các ý kiến trước đó và đưa ra quan điểm của bạn. KHÔNG ghi tên bạn ở đầu.",
+			Content: renderPrompt(setID, closing, data),
 		})
 	}
 
@@ -585,32 +686,23 @@ func cleanMessageContent(content string) string {
 	return strings.TrimSpace(result)
 }
 
-// selectNextAgent selects the next agent in free-form mode
-func (m *Manager) selectNextAgent() *agent.Agent {
-	// Simple implementation: rotate but skip if just spoke
-	if len(m.messages) == 0 {
-		return m.agents[0]
-	}
-
-	lastSpeaker := m.messages[len(m.messages)-1].AgentID
-
-	// Find next agent that isn't the last speaker
-	for i, a := range m.agents {
-		if a.ID != lastSpeaker {
-			m.currentIndex = i
-			return a
+// formatSources renders citations as a compact numbered source list
+func formatSources(citations []provider.Citation) string {
+	var sb strings.Builder
+	for _, c := range citations {
+		if c.Title != "" {
+			fmt.Fprintf(&sb, "[%d] %s - %s\n", c.Index, c.Title, c.URL)
+		} else {
+			fmt.Fprintf(&sb, "[%d] %s\n", c.Index, c.URL)
 		}
 	}
-
-	// Fallback to next in rotation
-	m.currentIndex = (m.currentIndex + 1) % len(m.agents)
-	return m.agents[m.currentIndex]
+	return strings.TrimRight(sb.String(), "\n")
 }
 
 // Reset clears the debate state
 func (m *Manager) Reset() {
+	m.StopPlayback()
 	m.mu.Lock()
-	defer m.mu.Unlock()
 	if m.cancel != nil {
 		m.cancel()
 	}
@@ -619,12 +711,21 @@ func (m *Manager) Reset() {
 	m.isRunning = false
 	m.currentIndex = 0
 	m.msgCounter = 0
-}
+	m.phaseIndex = 0
+	m.phaseTurn = 0
+	m.verdict = nil
+	m.ended = nil
+	m.summaries = nil
+	m.summaryCounter = 0
+	m.branch, m.branches, m.branchCounter = "", nil, 0
+	m.addressee = ""
+	m.restartAgendaLocked()
+	// Saved debates stay in the store; the next Start begins a new one
+	id := m.debateID
+	m.debateID = ""
+	m.mu.Unlock()
 
-// UpdateAgents updates the agents list (used when API keys change)
-func (m *Manager) UpdateAgents(agents []*agent.Agent) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.agents = agents
-	m.currentIndex = 0
+	// Logged here since the reset broadcast comes after the debate is gone
+	m.logEvent(id, map[string]interface{}{"type": "debate_reset"})
+	m.closeEventLog()
 }
@@ -0,0 +1,96 @@
+package debate
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+	"github.com/user/talk/internal/provider"
+)
+
+// MessageMeta records how a model produced a message, for comparing models
+type MessageMeta struct {
+	Provider        string           `json:"provider"`
+	Model           string           `json:"model"`
+	Options         provider.Options `json:"options"` // Parameters after the agent's settings are applied
+	TTFTMs          int64            `json:"ttft_ms"` // Time to first token
+	DurationMs      int64            `json:"duration_ms"`
+	InputTokens     int              `json:"input_tokens"`
+	OutputTokens    int              `json:"output_tokens"`
+	TokensEstimated bool             `json:"tokens_estimated,omitempty"` // The provider reported no usage
+	FinishReason    string           `json:"finish_reason,omitempty"`
+	Attempts        int              `json:"attempts,omitempty"` // Set when hooks had the reply rewritten
+}
+
+// turnMeter times one streamed reply and collects what the provider reports
+type turnMeter struct {
+	meta     MessageMeta
+	messages []provider.Message // Sent to the provider, for estimating usage
+	started  time.Time
+	usage    *provider.Usage
+}
+
+// newTurnMeter starts timing a reply to messages, as the agent will send them
+func newTurnMeter(a *agent.Agent, messages []provider.Message) *turnMeter {
+	t := &turnMeter{started: time.Now()}
+	sent, opts, request, err := a.PreviewChat(messages, provider.Options{})
+	if err != nil {
+		return t
+	}
+	t.messages = sent
+	t.meta.Options, t.meta.Model = opts, opts.Model
+	if request != nil {
+		t.meta.Provider, t.meta.Model = request.Provider, request.Model
+	}
+	return t
+}
+
+// chunk records a chunk from the provider
+func (t *turnMeter) chunk(c provider.StreamChunk) {
+	if c.Content != "" && t.meta.TTFTMs == 0 {
+		t.meta.TTFTMs = time.Since(t.started).Milliseconds()
+	}
+	if c.Done {
+		t.meta.FinishReason = c.FinishReason
+		if c.Usage != nil && (c.Usage.InputTokens > 0 || c.Usage.OutputTokens > 0) {
+			t.usage = c.Usage
+		}
+	}
+}
+
+// finish completes the metadata for a reply, estimating token counts the
+// provider didn't report
+func (t *turnMeter) finish(content string, attempt int) *MessageMeta {
+	meta := t.meta
+	meta.DurationMs = time.Since(t.started).Milliseconds()
+	if t.usage != nil {
+		meta.InputTokens, meta.OutputTokens = t.usage.InputTokens, t.usage.OutputTokens
+	} else {
+		meta.InputTokens = provider.EstimateTokens(t.messages)
+		meta.OutputTokens = provider.EstimateTokens([]provider.Message{{Content: content}})
+		meta.TokensEstimated = true
+	}
+	if attempt > 0 {
+		meta.Attempts = attempt + 1
+	}
+	return &meta
+}
+
+// String summarizes the metadata on one line, as in exports
+func (meta *MessageMeta) String() string {
+	parts := []string{meta.Provider + " / " + meta.Model}
+	if meta.Options.Temperature > 0 {
+		parts = append(parts, fmt.Sprintf("temperature %g", meta.Options.Temperature))
+	}
+	parts = append(parts, fmt.Sprintf("TTFT %.1fs", float64(meta.TTFTMs)/1000), fmt.Sprintf("%.1fs", float64(meta.DurationMs)/1000))
+	tokens := fmt.Sprintf("%d → %d token", meta.InputTokens, meta.OutputTokens)
+	if meta.TokensEstimated {
+		tokens = "~" + tokens
+	}
+	parts = append(parts, tokens)
+	if meta.FinishReason != "" {
+		parts = append(parts, meta.FinishReason)
+	}
+	return strings.Join(parts, " · ")
+}
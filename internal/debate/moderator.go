@@ -0,0 +1,111 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// moderatorWindow is how many recent messages the moderator reads
+const moderatorWindow = 8
+
+// ModeratorChoice is the moderator's structured decision
+type ModeratorChoice struct {
+	NextSpeaker string `json:"next_speaker"`
+	Reason      string `json:"reason"`
+}
+
+// SetModerator sets the agent that picks speakers in free-form mode (nil to disable)
+func (m *Manager) SetModerator(a *agent.Agent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.moderator = a
+}
+
+// GetModerator returns info about the current moderator, nil if none
+func (m *Manager) GetModerator() *agent.AgentInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.moderator == nil {
+		return nil
+	}
+	info := m.moderator.Info()
+	return &info
+}
+
+// moderatedSelect asks the moderator who should speak next, falling back to
+// rotation if the call fails or the answer can't be parsed. The choice and
+// reasoning are broadcast as a "moderator_choice" event.
+func moderatedSelect(ctx context.Context, state *SelectionState) *agent.Agent {
+	moderator := state.Moderator
+
+	var choice ModeratorChoice
+	chosen, err := func() (*agent.Agent, error) {
+		system := renderPrompt(state.PromptSet, "moderator_system", PromptData{})
+		resp, err := askAgent(ctx, moderator, system, moderatorPrompt(state))
+		if err != nil {
+			return nil, err
+		}
+		if err := extractJSON(resp, &choice); err != nil {
+			return nil, err
+		}
+		if a := findAgent(state.Agents, choice.NextSpeaker); a != nil {
+			return a, nil
+		}
+		return nil, fmt.Errorf("unknown speaker %q", choice.NextSpeaker)
+	}()
+
+	fallback := err != nil
+	if fallback {
+		log.Printf("Moderator fallback to rotation: %v", err)
+		chosen = state.rotateSkippingLast()
+		choice.Reason = ""
+	} else {
+		state.point(chosen)
+	}
+
+	if state.emit != nil {
+		event := map[string]interface{}{
+			"type":       "moderator_choice",
+			"agent_id":   chosen.ID,
+			"agent_name": chosen.Name,
+			"reason":     choice.Reason,
+			"fallback":   fallback,
+			"moderator":  moderator.Name,
+		}
+		if fallback {
+			event["error"] = err.Error()
+		}
+		state.emit(event)
+	}
+
+	return chosen
+}
+
+// moderatorPrompt builds the moderator request from the selection state
+func moderatorPrompt(state *SelectionState) string {
+	return renderPrompt(state.PromptSet, "moderator", PromptData{
+		Topic:      state.Topic,
+		Agents:     promptAgents(state.Agents, nil),
+		Transcript: recentTranscript(state.Messages, moderatorWindow),
+	})
+}
+
+// findAgent finds an agent by ID, falling back to a case-insensitive name match
+func findAgent(agents []*agent.Agent, key string) *agent.Agent {
+	key = strings.TrimSpace(key)
+	for _, a := range agents {
+		if a.ID == key {
+			return a
+		}
+	}
+	for _, a := range agents {
+		if strings.EqualFold(a.Name, key) {
+			return a
+		}
+	}
+	return nil
+}
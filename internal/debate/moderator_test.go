@@ -0,0 +1,46 @@
+package debate
+
+import (
+	"context"
+	"testing"
+)
+
+func TestModeratorPicksNamedSpeaker(t *testing.T) {
+	var events []map[string]interface{}
+	state := &SelectionState{
+		Agents:    selectorAgents(),
+		Messages:  []Message{{AgentID: "a1", Content: "mở đầu"}},
+		Moderator: testAgent("mod", "Điều phối", replyProvider{reply: `Chọn: {"next_speaker": "nhà phản biện", "reason": "cần phản biện"}`}),
+		emit:      func(ev interface{}) { events = append(events, ev.(map[string]interface{})) },
+	}
+
+	a, err := freeFormSelector{}.Select(context.Background(), state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != "a3" || state.CurrentIndex != 0 {
+		t.Errorf("picked %s at index %d, want a3 with the rotation after it", a.ID, state.CurrentIndex)
+	}
+	if len(events) != 1 || events[0]["reason"] != "cần phản biện" || events[0]["fallback"] != false {
+		t.Errorf("events = %v, want the moderator's reason", events)
+	}
+}
+
+func TestModeratorFallsBackToRotation(t *testing.T) {
+	for _, reply := range []string{"không có JSON", `{"next_speaker": "a9"}`} {
+		var events []map[string]interface{}
+		state := &SelectionState{
+			Agents:    selectorAgents(),
+			Messages:  []Message{{AgentID: "a1"}},
+			Moderator: testAgent("mod", "Điều phối", replyProvider{reply: reply}),
+			emit:      func(ev interface{}) { events = append(events, ev.(map[string]interface{})) },
+		}
+		a, _ := freeFormSelector{}.Select(context.Background(), state)
+		if a.ID != "a2" {
+			t.Errorf("reply %q: picked %s, want a2 by rotation skipping a1", reply, a.ID)
+		}
+		if len(events) != 1 || events[0]["fallback"] != true || events[0]["error"] == nil {
+			t.Errorf("reply %q: events = %v, want a fallback with its error", reply, events)
+		}
+	}
+}
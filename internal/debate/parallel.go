@@ -0,0 +1,210 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/user/talk/internal/agent"
+	"github.com/user/talk/internal/provider"
+)
+
+// parallelReply is one agent's turn in a parallel round
+type parallelReply struct {
+	turn *TurnContext
+	err  error
+}
+
+// ParallelRound has every agent answer the same context concurrently. Streams
+// are tagged by agent and message ID; replies are committed in panel order
+// once all agents finish. With critique, a second round shows each agent the
+// others' answers to critique. A parallel round holds the turn lock throughout.
+func (m *Manager) ParallelRound(critique bool, streamCh chan<- StreamMessage) error {
+	m.mu.Lock()
+	if !m.isRunning {
+		m.mu.Unlock()
+		return ErrNotRunning
+	}
+	if m.isTurnInProgress {
+		m.mu.Unlock()
+		return ErrTurnInProgress
+	}
+	if len(m.agents) == 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("no agents available")
+	}
+	m.isTurnInProgress = true
+	m.addressee = "" // Everyone answers, the addressed agent included
+	ctx := m.ctx
+	// Human seats answer one at a time, so they sit parallel rounds out
+	var agents []*agent.Agent
+	for _, a := range m.agents {
+		if !a.IsHuman() {
+			agents = append(agents, a)
+		}
+	}
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.isTurnInProgress = false
+		m.mu.Unlock()
+	}()
+
+	if len(agents) == 0 {
+		return fmt.Errorf("no model agents for a parallel round")
+	}
+	if err := m.prepareAgendaTurn(ctx, streamCh); err != nil {
+		return err
+	}
+
+	answered, marker, err := m.parallelStep(ctx, "answer", agents, streamCh)
+	if err != nil {
+		return err
+	}
+
+	// Only agents who answered critique, and only if there is someone to critique
+	if critique && len(answered) > 1 && ctx.Err() == nil {
+		var critiqueMarker *agent.Agent
+		if _, critiqueMarker, err = m.parallelStep(ctx, "critique", answered, streamCh); err != nil {
+			return err
+		}
+		if marker == nil {
+			marker = critiqueMarker
+		}
+	}
+
+	m.checkTermination(ctx, marker, marker != nil)
+	m.maybeRollingSummary(ctx, streamCh)
+	m.advanceAgenda(ctx, false, streamCh)
+	return nil
+}
+
+// parallelStep runs one concurrent round for agents, telling them what to do
+// with the parallel_<round> template, and commits the replies in panel order.
+// It returns the agents whose replies were committed and the first of them
+// that wrote the end marker.
+func (m *Manager) parallelStep(ctx context.Context, round string, agents []*agent.Agent, streamCh chan<- StreamMessage) ([]*agent.Agent, *agent.Agent, error) {
+	// Every agent sees the same transcript: nothing is committed until all finish
+	m.mu.Lock()
+	replies := make([]parallelReply, len(agents))
+	for i, a := range agents {
+		m.msgCounter++
+		tc := m.newTurnLocked(a, fmt.Sprintf("msg_%d", m.msgCounter), m.messages)
+		instructions := renderPrompt(m.promptSet, "parallel_"+round, m.promptDataLocked(m.promptSet, a, m.messages))
+		tc.Messages = append(tc.Messages, provider.Message{Role: "user", Content: instructions})
+		replies[i] = parallelReply{turn: tc}
+	}
+	pipeline := m.pipelineLocked()
+	m.mu.Unlock()
+
+	m.emit(map[string]interface{}{
+		"type":   "parallel_started",
+		"round":  round,
+		"agents": parallelMessageIDs(replies),
+	})
+
+	var wg sync.WaitGroup
+	for i := range replies {
+		wg.Add(1)
+		go func(r *parallelReply) {
+			defer wg.Done()
+			if r.err = pipeline.beforeTurn(ctx, r.turn); r.err != nil {
+				m.emitDropped(r.turn, "turn_vetoed", r.err)
+				return
+			}
+			r.turn.Content, r.turn.Citations, r.err = streamReply(ctx, r.turn, pipeline, true, streamCh)
+			if r.err != nil || r.turn.Content == "" {
+				return
+			}
+			// Rejected replies are dropped: the other agents already answered
+			if r.err = pipeline.afterTurn(ctx, r.turn); r.err != nil {
+				m.emitDropped(r.turn, "turn_rejected", r.err)
+				streamCh <- StreamMessage{Type: "end", AgentID: r.turn.Agent.ID, MessageID: r.turn.MessageID, Parallel: true, Audience: r.turn.Audience}
+			}
+		}(&replies[i])
+	}
+	wg.Wait()
+
+	var committed []*agent.Agent
+	var markerAgent *agent.Agent
+	contents := make([]string, len(replies))
+	m.mu.Lock()
+	for i, r := range replies {
+		if r.err != nil || r.turn.Content == "" {
+			continue
+		}
+		content, marker := m.endMarkerLocked(r.turn.Content)
+		if marker && markerAgent == nil {
+			markerAgent = r.turn.Agent
+		}
+		m.appendMessageLocked(turnMessage(r.turn, content))
+		committed = append(committed, r.turn.Agent)
+		contents[i] = content
+	}
+	m.countAgendaTurnsLocked(len(committed))
+	m.mu.Unlock()
+
+	m.persist()
+	for i, r := range replies {
+		if r.err == nil {
+			streamCh <- endMessage(r.turn, contents[i], true)
+		}
+	}
+
+	m.emit(map[string]interface{}{
+		"type":      "parallel_finished",
+		"round":     round,
+		"committed": len(committed),
+	})
+
+	if len(committed) == 0 && ctx.Err() == nil {
+		return nil, nil, fmt.Errorf("no agent answered in the %s round", round)
+	}
+	return committed, markerAgent, nil
+}
+
+// streamReply streams one agent's reply under a reserved message ID without
+// committing it. It sends the start event; the caller sends the end event
+// once the post-turn hooks ran. A reply stopped by the user or failing ends
+// here, quietly with ctx.Err() or with an error event.
+func streamReply(ctx context.Context, tc *TurnContext, pipeline *Pipeline, parallel bool, streamCh chan<- StreamMessage) (string, []provider.Citation, error) {
+	a := tc.Agent
+	streamCh <- StreamMessage{
+		Type:      "start",
+		AgentID:   a.ID,
+		AgentName: a.Name,
+		MessageID: tc.MessageID,
+		Color:     a.Color,
+		Parallel:  parallel,
+		Audience:  tc.Audience,
+	}
+
+	content, citations, err := streamChunks(ctx, tc, pipeline, parallel, streamCh)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Stopped by the user: end quietly and drop the partial reply
+			streamCh <- StreamMessage{Type: "end", AgentID: a.ID, MessageID: tc.MessageID, Parallel: parallel, Audience: tc.Audience}
+			return "", nil, ctx.Err()
+		}
+		streamCh <- StreamMessage{
+			Type:      "error",
+			AgentID:   a.ID,
+			MessageID: tc.MessageID,
+			Error:     err.Error(),
+			Parallel:  parallel,
+			Audience:  tc.Audience,
+		}
+		return "", nil, err
+	}
+	return content, citations, nil
+}
+
+// parallelMessageIDs maps agent IDs to the message IDs reserved for their replies
+func parallelMessageIDs(replies []parallelReply) []map[string]string {
+	result := make([]map[string]string, len(replies))
+	for i, r := range replies {
+		result[i] = map[string]string{"agent_id": r.turn.Agent.ID, "message_id": r.turn.MessageID}
+	}
+	return result
+}
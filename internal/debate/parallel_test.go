@@ -0,0 +1,54 @@
+package debate
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// annotateHook notes on every reply that it was checked
+type annotateHook struct{}
+
+func (annotateHook) Name() string { return "annotate" }
+
+func (annotateHook) AfterTurn(_ context.Context, tc *TurnContext) error {
+	tc.Annotate("checked", "yes")
+	return nil
+}
+
+func TestParallelEndCarriesPostTurnResult(t *testing.T) {
+	m, _ := newTestManager(t, annotateHook{})
+	start(t, m, "Thuế carbon")
+
+	var ends []StreamMessage
+	ch := make(chan StreamMessage, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- m.ParallelRound(false, ch)
+		close(ch)
+	}()
+	for msg := range ch {
+		if msg.Type == "end" {
+			ends = append(ends, msg)
+		}
+	}
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, end := range ends {
+		ids = append(ids, end.MessageID)
+		if !end.Parallel || end.Annotations["checked"] != "yes" || end.Meta == nil {
+			t.Errorf("end %s = %+v, want the hooks' annotations and the meta", end.MessageID, end)
+		}
+	}
+	if want := []string{"msg_1", "msg_2", "msg_3"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("end events = %v, want one per committed reply in panel order %v", ids, want)
+	}
+	for _, msg := range m.GetMessages() {
+		if msg.Annotations["checked"] != "yes" {
+			t.Errorf("stored %s lacks the annotation: %+v", msg.ID, msg.Annotations)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package debate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// UpdateAgents replaces the panel (session edits, config reloads, casting).
+// Rotation resumes with the agent that was due next, or the next one still
+// on the panel. In formats with sides, newcomers join the smaller side. Once
+// the debate has messages, joins and leaves are noted in the transcript.
+// Clients get agents_updated with the diff.
+func (m *Manager) UpdateAgents(agents []*agent.Agent) {
+	m.mu.Lock()
+	changed := !sameAgentIDs(m.agents, agents)
+	joined, left := diffAgents(m.agents, agents)
+	m.currentIndex = rotationIndex(m.agents, agents, m.currentIndex)
+	m.agents = agents
+	if m.format != nil && m.format.UsesSides {
+		m.sides = joinSides(agents, m.sides)
+	}
+	if m.addressee != "" && m.findAgentLocked(m.addressee) == nil {
+		m.addressee = ""
+	}
+	notes := make([]Message, 0, len(joined)+len(left))
+	if len(m.messages) > 0 {
+		for _, a := range left {
+			notes = append(notes, m.noteParticipantLocked("agent_left", a))
+		}
+		for _, a := range joined {
+			notes = append(notes, m.noteParticipantLocked("agent_joined", a))
+		}
+	}
+	m.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if len(notes) > 0 {
+		m.persist()
+	}
+	m.emit(map[string]interface{}{
+		"type":     "agents_updated",
+		"joined":   agentInfos(joined),
+		"left":     agentInfos(left),
+		"messages": notes,
+	})
+}
+
+// noteParticipantLocked adds a system note that an agent joined or left and
+// returns it; callers must hold m.mu
+func (m *Manager) noteParticipantLocked(template string, a *agent.Agent) Message {
+	m.msgCounter++
+	m.appendMessageLocked(Message{
+		ID:        fmt.Sprintf("msg_%d", m.msgCounter),
+		AgentID:   "system",
+		AgentName: renderPrompt(m.promptSet, "system_name", PromptData{}),
+		Content:   renderPrompt(m.promptSet, template, PromptData{Speaker: a.Name}),
+		Timestamp: time.Now(),
+		Color:     "#888888",
+	})
+	return m.messages[len(m.messages)-1]
+}
+
+// joinSides gives agents without a side the side with fewer members on the
+// panel, pro on a tie, keeping everyone else's
+func joinSides(agents []*agent.Agent, sides map[string]Side) map[string]Side {
+	result := make(map[string]Side, len(sides)+len(agents))
+	counts := make(map[Side]int)
+	for id, side := range sides {
+		result[id] = side
+		if agentByID(agents, id) != nil {
+			counts[side]++
+		}
+	}
+	for _, a := range agents {
+		if _, ok := result[a.ID]; ok {
+			continue
+		}
+		side := SidePro
+		if counts[SideCon] < counts[SidePro] {
+			side = SideCon
+		}
+		result[a.ID] = side
+		counts[side]++
+	}
+	return result
+}
+
+// rotationIndex maps a rotation position on the old panel to the new one:
+// the agent that was due keeps its turn, or the next old agent still present
+func rotationIndex(old, updated []*agent.Agent, current int) int {
+	if len(old) == 0 || len(updated) == 0 {
+		return 0
+	}
+	for i := 0; i < len(old); i++ {
+		due := old[(current+i)%len(old)]
+		for j, a := range updated {
+			if a.ID == due.ID {
+				return j
+			}
+		}
+	}
+	return 0
+}
+
+// diffAgents returns the agents only in updated and those only in old
+func diffAgents(old, updated []*agent.Agent) (joined, left []*agent.Agent) {
+	for _, a := range updated {
+		if agentByID(old, a.ID) == nil {
+			joined = append(joined, a)
+		}
+	}
+	for _, a := range old {
+		if agentByID(updated, a.ID) == nil {
+			left = append(left, a)
+		}
+	}
+	return joined, left
+}
+
+// sameAgentIDs reports whether two panels have the same agents in the same order
+func sameAgentIDs(a, b []*agent.Agent) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ID != b[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+// agentByID returns the agent with exactly this ID, nil if none
+func agentByID(agents []*agent.Agent, id string) *agent.Agent {
+	for _, a := range agents {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// agentInfos returns the public info of each agent
+func agentInfos(agents []*agent.Agent) []agent.AgentInfo {
+	result := make([]agent.AgentInfo, len(agents))
+	for i, a := range agents {
+		result[i] = a.Info()
+	}
+	return result
+}
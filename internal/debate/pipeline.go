@@ -0,0 +1,387 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+	"github.com/user/talk/internal/provider"
+)
+
+// TurnContext is a turn as seen by hooks
+type TurnContext struct {
+	Agent       *agent.Agent
+	MessageID   string
+	Topic       string
+	History     []Message          // Transcript before the turn (a copy)
+	Messages    []provider.Message // Context sent to the model; pre-turn hooks may change it
+	Addressed   bool               // The agent answers an interjection addressed to it
+	Attempt     int                // 0 for the first try, then one more per retry
+	Content     string             // The finished reply; post-turn hooks may rewrite it
+	Citations   []provider.Citation
+	Annotations map[string]string // Stored on the message
+	Meta        *MessageMeta      // How the model produced the reply; nil for humans
+	Audience    []string          // Agents a private turn is shown to; nil for everyone
+}
+
+// Annotate attaches a note to the message the turn produces
+func (tc *TurnContext) Annotate(key, value string) {
+	if tc.Annotations == nil {
+		tc.Annotations = make(map[string]string)
+	}
+	tc.Annotations[key] = value
+}
+
+// Hook is a named step of the turn pipeline. A hook implements any of
+// PreTurnHook, StreamHook and PostTurnHook.
+type Hook interface {
+	Name() string
+}
+
+// PreTurnHook runs before the model is called. It may change tc.Messages;
+// returning an error vetoes the turn.
+type PreTurnHook interface {
+	Hook
+	BeforeTurn(ctx context.Context, tc *TurnContext) error
+}
+
+// StreamHook transforms each streamed chunk before clients see it. Chunks are
+// split arbitrarily, so matches spanning two chunks are missed.
+type StreamHook interface {
+	Hook
+	OnChunk(tc *TurnContext, chunk string) string
+}
+
+// PostTurnHook runs on the finished reply. It may rewrite tc.Content or
+// annotate it; returning an error rejects the message, and a *Rejection with
+// Retry set asks the agent to try again.
+type PostTurnHook interface {
+	Hook
+	AfterTurn(ctx context.Context, tc *TurnContext) error
+}
+
+// Rejection is returned by post-turn hooks to reject a reply
+type Rejection struct {
+	Reason string
+	Retry  bool // Have the agent write the reply again
+}
+
+func (r *Rejection) Error() string {
+	return r.Reason
+}
+
+// Pipeline runs hooks around every turn, in order
+type Pipeline struct {
+	hooks []Hook
+}
+
+// NewPipeline creates a pipeline from hooks, run in the given order
+func NewPipeline(hooks ...Hook) *Pipeline {
+	return &Pipeline{hooks: hooks}
+}
+
+// Names returns the pipeline's hook names in order
+func (p *Pipeline) Names() []string {
+	names := make([]string, len(p.hooks))
+	for i, h := range p.hooks {
+		names[i] = h.Name()
+	}
+	return names
+}
+
+func (p *Pipeline) beforeTurn(ctx context.Context, tc *TurnContext) error {
+	for _, h := range p.hooks {
+		if pre, ok := h.(PreTurnHook); ok {
+			if err := pre.BeforeTurn(ctx, tc); err != nil {
+				return fmt.Errorf("%s: %w", h.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Pipeline) onChunk(tc *TurnContext, chunk string) string {
+	for _, h := range p.hooks {
+		if sh, ok := h.(StreamHook); ok {
+			chunk = sh.OnChunk(tc, chunk)
+		}
+	}
+	return chunk
+}
+
+func (p *Pipeline) afterTurn(ctx context.Context, tc *TurnContext) error {
+	for _, h := range p.hooks {
+		if post, ok := h.(PostTurnHook); ok {
+			if err := post.AfterTurn(ctx, tc); err != nil {
+				return fmt.Errorf("%s: %w", h.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// HookConfig configures one hook of the pipeline (the hooks list in config.yaml)
+type HookConfig struct {
+	Name    string      `yaml:"name" json:"name"`
+	Options HookOptions `yaml:"options,omitempty" json:"options,omitempty"`
+}
+
+// HookFactory creates a hook from its options
+type HookFactory func(opts HookOptions) (Hook, error)
+
+var (
+	hookMu          sync.RWMutex
+	hookFactories   = map[string]HookFactory{}
+	defaultPipeline = NewPipeline(cleanContentHook{})
+)
+
+// RegisterHook makes a hook available to BuildPipeline under name
+func RegisterHook(name string, factory HookFactory) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	hookFactories[name] = factory
+}
+
+// HookNames returns the registered hook names, sorted
+func HookNames() []string {
+	hookMu.RLock()
+	defer hookMu.RUnlock()
+	names := make([]string, 0, len(hookFactories))
+	for name := range hookFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuildPipeline creates a pipeline from hook configs
+func BuildPipeline(configs []HookConfig) (*Pipeline, error) {
+	hookMu.RLock()
+	defer hookMu.RUnlock()
+	hooks := make([]Hook, 0, len(configs))
+	for _, cfg := range configs {
+		factory, ok := hookFactories[cfg.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown hook: %s", cfg.Name)
+		}
+		h, err := factory(cfg.Options)
+		if err != nil {
+			return nil, fmt.Errorf("hook %s: %w", cfg.Name, err)
+		}
+		hooks = append(hooks, h)
+	}
+	return NewPipeline(hooks...), nil
+}
+
+// SetDefaultPipeline sets the pipeline used by managers that don't set their own
+func SetDefaultPipeline(p *Pipeline) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+	defaultPipeline = p
+}
+
+// DefaultPipeline returns the pipeline used by managers that don't set their own
+func DefaultPipeline() *Pipeline {
+	hookMu.RLock()
+	defer hookMu.RUnlock()
+	return defaultPipeline
+}
+
+// SetPipeline overrides the turn pipeline for this debate (nil = the default)
+func (m *Manager) SetPipeline(p *Pipeline) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pipeline = p
+}
+
+// pipelineLocked returns the debate's pipeline; callers must hold m.mu
+func (m *Manager) pipelineLocked() *Pipeline {
+	if m.pipeline != nil {
+		return m.pipeline
+	}
+	return DefaultPipeline()
+}
+
+// newTurnLocked prepares speaker's turn as if history were the whole
+// transcript; callers must hold m.mu
+func (m *Manager) newTurnLocked(speaker *agent.Agent, msgID string, history []Message) *TurnContext {
+	return &TurnContext{
+		Agent:     speaker,
+		MessageID: msgID,
+		Topic:     m.topic,
+		History:   append([]Message(nil), history...),
+		Messages:  m.buildContextLocked(speaker, history),
+	}
+}
+
+// playTurn runs one turn through the pipeline: pre-turn hooks, the model's
+// streamed reply (or a human's answer), then post-turn hooks, retrying a
+// rejected reply when a hook asks for it. The caller holds the turn lock;
+// playTurn releases it. advance counts the turn toward the format schedule;
+// a non-empty audience makes the turn private.
+func (m *Manager) playTurn(ctx context.Context, speaker *agent.Agent, addressed, advance bool, audience []string, streamCh chan<- StreamMessage) error {
+	m.mu.Lock()
+	m.msgCounter++
+	tc := m.newTurnLocked(speaker, fmt.Sprintf("msg_%d", m.msgCounter), m.messages)
+	tc.Addressed = addressed
+	m.whisperLocked(tc, audience)
+	pipeline := m.pipelineLocked()
+	m.mu.Unlock()
+
+	if err := pipeline.beforeTurn(ctx, tc); err != nil {
+		m.dropTurn(tc, "turn_vetoed", err, advance, streamCh)
+		return nil
+	}
+
+	if speaker.IsHuman() {
+		return m.humanTurn(ctx, tc, pipeline, advance, streamCh)
+	}
+
+	streamCh <- StreamMessage{
+		Type:      "start",
+		AgentID:   speaker.ID,
+		AgentName: speaker.Name,
+		MessageID: tc.MessageID,
+		Color:     speaker.Color,
+		Audience:  tc.Audience,
+	}
+
+	for {
+		content, citations, err := streamChunks(ctx, tc, pipeline, false, streamCh)
+		if err != nil {
+			m.mu.Lock()
+			m.isTurnInProgress = false
+			m.mu.Unlock()
+			if ctx.Err() != nil {
+				// Stopped by the user: end gracefully without an error
+				streamCh <- StreamMessage{Type: "end", AgentID: speaker.ID, MessageID: tc.MessageID, Audience: tc.Audience}
+				return nil
+			}
+			streamCh <- StreamMessage{
+				Type:      "error",
+				AgentID:   speaker.ID,
+				MessageID: tc.MessageID,
+				Error:     err.Error(),
+				Audience:  tc.Audience,
+			}
+			return err
+		}
+		tc.Content, tc.Citations = content, citations
+
+		err = pipeline.afterTurn(ctx, tc)
+		if err == nil {
+			break
+		}
+		var rejection *Rejection
+		if !errors.As(err, &rejection) || !rejection.Retry || ctx.Err() != nil {
+			m.dropTurn(tc, "turn_rejected", err, advance, streamCh)
+			return nil
+		}
+
+		log.Printf("Retrying %s's turn: %v", speaker.Name, err)
+		tc.Attempt++
+		tc.Messages = append(tc.Messages, provider.Message{Role: "user", Content: m.retryPrompt(tc, rejection.Reason)})
+		streamCh <- StreamMessage{
+			Type:      "retry",
+			AgentID:   speaker.ID,
+			MessageID: tc.MessageID,
+			Content:   err.Error(),
+			Audience:  tc.Audience,
+		}
+	}
+
+	m.commitTurn(ctx, tc, advance, streamCh)
+	return nil
+}
+
+// dropTurn ends a turn a hook vetoed or whose reply it rejected
+func (m *Manager) dropTurn(tc *TurnContext, event string, reason error, advance bool, streamCh chan<- StreamMessage) {
+	m.emitDropped(tc, event, reason)
+	m.skipTurn(tc.Agent, tc.MessageID, advance, streamCh)
+}
+
+// emitDropped tells clients a hook vetoed a turn or rejected its reply, so
+// they drop the streamed message
+func (m *Manager) emitDropped(tc *TurnContext, event string, reason error) {
+	log.Printf("%s: %s's turn (%s): %v", strings.ReplaceAll(event, "_", " "), tc.Agent.Name, tc.MessageID, reason)
+	m.emit(map[string]interface{}{
+		"type":       event,
+		"agent_id":   tc.Agent.ID,
+		"agent_name": tc.Agent.Name,
+		"message_id": tc.MessageID,
+		"reason":     reason.Error(),
+	})
+}
+
+// retryPrompt asks the agent to rewrite a rejected reply
+func (m *Manager) retryPrompt(tc *TurnContext, reason string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data := m.promptDataLocked(m.promptSet, tc.Agent, tc.History)
+	data.Content = tc.Content
+	data.Reason = reason
+	return renderPrompt(m.promptSet, "retry", data)
+}
+
+// streamChunks streams one attempt at a reply, passing each chunk through the
+// pipeline's stream hooks. The caller sends the start and end events.
+func streamChunks(ctx context.Context, tc *TurnContext, pipeline *Pipeline, parallel bool, streamCh chan<- StreamMessage) (string, []provider.Citation, error) {
+	meter := newTurnMeter(tc.Agent, tc.Messages)
+	respCh, err := tc.Agent.Chat(ctx, tc.Messages, provider.Options{})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var content strings.Builder
+	var citations []provider.Citation
+	for chunk := range respCh {
+		if chunk.Error != nil {
+			if ctx.Err() != nil {
+				return "", nil, ctx.Err()
+			}
+			return "", nil, chunk.Error
+		}
+		meter.chunk(chunk)
+		if len(chunk.Citations) > 0 {
+			citations = chunk.Citations
+		}
+		if text := pipeline.onChunk(tc, chunk.Content); text != "" {
+			content.WriteString(text)
+			streamCh <- StreamMessage{
+				Type:      "chunk",
+				AgentID:   tc.Agent.ID,
+				Content:   text,
+				MessageID: tc.MessageID,
+				Parallel:  parallel,
+				Audience:  tc.Audience,
+			}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	tc.Meta = meter.finish(content.String(), tc.Attempt)
+	return content.String(), citations, nil
+}
+
+// turnMessage is the message a finished turn stores
+func turnMessage(tc *TurnContext, content string) Message {
+	return Message{
+		ID:          tc.MessageID,
+		AgentID:     tc.Agent.ID,
+		AgentName:   tc.Agent.Name,
+		Content:     content,
+		Timestamp:   time.Now(),
+		Color:       tc.Agent.Color,
+		Citations:   tc.Citations,
+		Annotations: tc.Annotations,
+		Meta:        tc.Meta,
+		Audience:    tc.Audience,
+	}
+}
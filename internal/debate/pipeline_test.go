@@ -0,0 +1,93 @@
+package debate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildPipeline(t *testing.T) {
+	p, err := BuildPipeline([]HookConfig{
+		{Name: "clean_content"},
+		{Name: "length", Options: HookOptions{"min_words": 2, "max_words": 50}},
+		{Name: "redact", Options: HookOptions{"words": []interface{}{"bí mật"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Names(); !reflect.DeepEqual(got, []string{"clean_content", "length", "redact"}) {
+		t.Errorf("names = %v", got)
+	}
+
+	for _, cfg := range []HookConfig{
+		{Name: "missing"},
+		{Name: "length", Options: HookOptions{"min_words": 10, "max_words": 5}},
+		{Name: "redact"},
+	} {
+		if _, err := BuildPipeline([]HookConfig{cfg}); err == nil {
+			t.Errorf("BuildPipeline(%+v) accepted an invalid hook", cfg)
+		}
+	}
+}
+
+func TestContextCleansThinkingMarkers(t *testing.T) {
+	m, _ := newTestManager(t)
+	m.SetPipeline(NewPipeline())
+	start(t, m, "Thuế carbon")
+
+	m.mu.Lock()
+	m.appendMessageLocked(Message{ID: "msg_1", AgentID: "a1", AgentName: "Alpha", Content: "<thinking>nháp</thinking>Ý chính"})
+	built := m.buildContextLocked(m.agents[1], m.messages)
+	m.mu.Unlock()
+
+	for _, msg := range built {
+		if strings.Contains(msg.Content, "thinking") || strings.Contains(msg.Content, "nháp") {
+			t.Errorf("context carries the thinking block: %q", msg.Content)
+		}
+	}
+	if !strings.Contains(built[1].Content, "Ý chính") {
+		t.Errorf("context lost the reply: %q", built[1].Content)
+	}
+}
+
+func TestLengthHookDropsReplyAfterRetries(t *testing.T) {
+	length, err := newLengthHook(HookOptions{"min_words": 10, "retries": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, rec := newTestManager(t, length)
+	start(t, m, "Thuế carbon")
+	nextTurns(t, m, 1)
+
+	if msgs := m.GetMessages(); len(msgs) != 0 {
+		t.Fatalf("messages = %+v, want the short reply dropped", msgs)
+	}
+	var retries, rejected int
+	events, _ := rec.LoadEvents(m.GetDebateID())
+	for _, ev := range events {
+		switch ev.Type {
+		case "retry":
+			retries++
+		case "turn_rejected":
+			rejected++
+		}
+	}
+	if retries != 1 || rejected != 1 {
+		t.Errorf("got %d retries and %d rejections, want 1 and 1", retries, rejected)
+	}
+}
+
+func TestRedactHookMasksStoredReply(t *testing.T) {
+	redact, err := newRedactHook(HookOptions{"words": []interface{}{"trả lời"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := newTestManager(t, redact)
+	start(t, m, "Thuế carbon")
+	nextTurns(t, m, 1)
+
+	msgs := m.GetMessages()
+	if len(msgs) != 1 || msgs[0].Content != "câu *** số 1" {
+		t.Errorf("messages = %+v, want the word split across chunks masked", msgs)
+	}
+}
@@ -0,0 +1,199 @@
+package debate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Playback speed limits; 1 replays events with their recorded timing
+const (
+	MinPlaybackSpeed = 0.1
+	MaxPlaybackSpeed = 50.0
+)
+
+// maxPlaybackGap caps the recorded pause between two events, so a debate
+// left idle for an hour doesn't stall its playback
+const maxPlaybackGap = 2 * time.Second
+
+// PlaybackStatus describes the playback in progress
+type PlaybackStatus struct {
+	DebateID  string    `json:"debate_id"`
+	Speed     float64   `json:"speed"`
+	Position  int       `json:"position"` // Events sent so far
+	Total     int       `json:"total"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// playbackState holds the bookkeeping for a playback
+type playbackState struct {
+	status PlaybackStatus
+	cancel context.CancelFunc
+	wake   chan struct{} // Signalled when the speed changes
+}
+
+// validPlaybackSpeed checks a speed is within the supported range
+func validPlaybackSpeed(speed float64) error {
+	if speed < MinPlaybackSpeed || speed > MaxPlaybackSpeed {
+		return fmt.Errorf("speed must be between %g and %g", MinPlaybackSpeed, MaxPlaybackSpeed)
+	}
+	return nil
+}
+
+// StartPlayback re-streams a debate's logged events to this session's clients
+// as "playback" events, keeping their recorded pacing scaled by speed. Turns
+// are blocked until the playback finishes or is stopped; the manager's own
+// state is not changed.
+func (m *Manager) StartPlayback(debateID string, events []Event, speed float64) error {
+	if len(events) == 0 {
+		return fmt.Errorf("debate %s has no recorded events", debateID)
+	}
+	if err := validPlaybackSpeed(speed); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	if m.isTurnInProgress {
+		m.mu.Unlock()
+		return ErrTurnInProgress
+	}
+	if m.run != nil && m.run.status.State != RunStateFinished {
+		m.mu.Unlock()
+		return fmt.Errorf("an autonomous run is active")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &playbackState{
+		status: PlaybackStatus{
+			DebateID:  debateID,
+			Speed:     speed,
+			Total:     len(events),
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+		wake:   make(chan struct{}, 1),
+	}
+	m.playback = p
+	m.isTurnInProgress = true
+	m.mu.Unlock()
+
+	m.emit(map[string]interface{}{
+		"type":     "playback_started",
+		"playback": p.status,
+	})
+
+	go m.playbackLoop(ctx, p, events)
+	return nil
+}
+
+// playbackLoop sends the events one by one, waiting between them
+func (m *Manager) playbackLoop(ctx context.Context, p *playbackState, events []Event) {
+	reason := "finished"
+	for i, ev := range events {
+		if i > 0 && !m.waitPlayback(ctx, p, ev.Time.Sub(events[i-1].Time)) {
+			reason = "stopped"
+			break
+		}
+		m.mu.Lock()
+		p.status.Position = i + 1
+		m.mu.Unlock()
+
+		m.emit(map[string]interface{}{
+			"type":  "playback",
+			"seq":   ev.Seq,
+			"time":  ev.Time,
+			"event": json.RawMessage(ev.Data),
+		})
+	}
+
+	m.mu.Lock()
+	if m.playback == p {
+		m.playback = nil
+		m.isTurnInProgress = false
+	}
+	status := p.status
+	m.mu.Unlock()
+	p.cancel()
+
+	m.emit(map[string]interface{}{
+		"type":     "playback_finished",
+		"reason":   reason,
+		"playback": status,
+	})
+}
+
+// waitPlayback sleeps for a recorded gap at the current speed. A speed change
+// restarts the wait with the new speed. It returns false when stopped.
+func (m *Manager) waitPlayback(ctx context.Context, p *playbackState, gap time.Duration) bool {
+	if gap > maxPlaybackGap {
+		gap = maxPlaybackGap
+	}
+	for {
+		m.mu.RLock()
+		speed := p.status.Speed
+		m.mu.RUnlock()
+
+		timer := time.NewTimer(time.Duration(float64(gap) / speed))
+		select {
+		case <-timer.C:
+			return true
+		case <-p.wake:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		}
+	}
+}
+
+// SetPlaybackSpeed changes the speed of the playback in progress
+func (m *Manager) SetPlaybackSpeed(speed float64) error {
+	if err := validPlaybackSpeed(speed); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	p := m.playback
+	if p == nil {
+		m.mu.Unlock()
+		return fmt.Errorf("no playback in progress")
+	}
+	p.status.Speed = speed
+	status := p.status
+	m.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	m.emit(map[string]interface{}{
+		"type":     "playback_speed",
+		"playback": status,
+	})
+	return nil
+}
+
+// StopPlayback ends the playback in progress, if any. Turns are allowed again
+// immediately; the playback goroutine sends playback_finished when it exits.
+func (m *Manager) StopPlayback() {
+	m.mu.Lock()
+	p := m.playback
+	if p != nil {
+		m.playback = nil
+		m.isTurnInProgress = false
+	}
+	m.mu.Unlock()
+	if p != nil {
+		p.cancel()
+	}
+}
+
+// GetPlayback returns the playback in progress, nil if none
+func (m *Manager) GetPlayback() *PlaybackStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.playback == nil {
+		return nil
+	}
+	status := m.playback.status
+	return &status
+}
@@ -0,0 +1,58 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/user/talk/internal/provider"
+)
+
+// TurnPreview is what an agent's next turn would send to its provider
+type TurnPreview struct {
+	AgentID   string                   `json:"agent_id"`
+	AgentName string                   `json:"agent_name"`
+	Provider  string                   `json:"provider"`
+	Messages  []provider.Message       `json:"messages"` // With the system prompt, before provider conversion
+	Options   provider.Options         `json:"options"`  // After the agent's settings are applied
+	Request   *provider.RequestPreview `json:"request,omitempty"`
+	Estimate  provider.Estimate        `json:"estimate"`
+	Vetoed    string                   `json:"vetoed,omitempty"` // Set when a pre-turn hook would veto the turn
+}
+
+// PreviewTurn builds an agent's next turn without calling the model: the
+// context after pre-turn hooks, merged with the agent's system prompt and
+// options, then converted by the provider, with a rough token and cost
+// estimate. The debate is not changed.
+func (m *Manager) PreviewTurn(ctx context.Context, agentID string) (*TurnPreview, error) {
+	m.mu.RLock()
+	a := m.findAgentLocked(agentID)
+	if a == nil {
+		m.mu.RUnlock()
+		return nil, fmt.Errorf("agent not found: %s", agentID)
+	}
+	tc := m.newTurnLocked(a, fmt.Sprintf("msg_%d", m.msgCounter+1), m.messages)
+	tc.Addressed = m.addressee == a.ID
+	pipeline := m.pipelineLocked()
+	m.mu.RUnlock()
+
+	preview := &TurnPreview{AgentID: a.ID, AgentName: a.Name}
+	if err := pipeline.beforeTurn(ctx, tc); err != nil {
+		preview.Vetoed = err.Error()
+	}
+
+	messages, opts, request, err := a.PreviewChat(tc.Messages, provider.Options{})
+	if err != nil {
+		return nil, err
+	}
+	preview.Provider = a.Provider.Name()
+	preview.Messages, preview.Options, preview.Request = messages, opts, request
+	if request != nil {
+		preview.Estimate = provider.EstimateRequest(messages, *request)
+	} else {
+		preview.Estimate = provider.Estimate{
+			InputTokens:     provider.EstimateTokens(messages),
+			MaxOutputTokens: opts.MaxTokens,
+		}
+	}
+	return preview, nil
+}
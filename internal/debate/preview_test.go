@@ -0,0 +1,46 @@
+package debate
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestPreviewTurnLeavesDebateUnchanged(t *testing.T) {
+	m, rec := newTestManager(t, vetoHook{agentID: "a3"})
+	m.agents[1].SystemPrompt = "Bạn là nhà kinh tế học."
+	start(t, m, "Thuế carbon")
+	nextTurns(t, m, 1)
+	events, _ := rec.LoadEvents(m.GetDebateID())
+	logged := len(events)
+
+	preview, err := m.PreviewTurn(context.Background(), "a2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.AgentID != "a2" || preview.Provider != "script" || preview.Vetoed != "" {
+		t.Errorf("preview = %+v", preview)
+	}
+	var sawReply bool
+	for _, msg := range preview.Messages {
+		sawReply = sawReply || strings.Contains(msg.Content, "câu trả lời số 1")
+	}
+	if preview.Messages[0].Content != "Bạn là nhà kinh tế học." || !sawReply {
+		t.Errorf("messages = %+v, want the system prompt and a1's reply", preview.Messages)
+	}
+	if preview.Estimate.InputTokens == 0 {
+		t.Error("no token estimate")
+	}
+
+	if preview, err := m.PreviewTurn(context.Background(), "a3"); err != nil || preview.Vetoed == "" {
+		t.Errorf("preview for a3 = %+v (%v), want the veto reported", preview, err)
+	}
+	if _, err := m.PreviewTurn(context.Background(), "a9"); err == nil {
+		t.Error("previewed an agent not on the panel")
+	}
+
+	events, _ = rec.LoadEvents(m.GetDebateID())
+	if len(m.GetMessages()) != 1 || len(events) != logged {
+		t.Error("previewing changed the debate")
+	}
+}
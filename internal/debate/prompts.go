@@ -0,0 +1,330 @@
+package debate
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/user/talk/internal/agent"
+	"github.com/user/talk/internal/provider"
+)
+
+//go:embed prompts/*.tmpl
+var builtinPromptFS embed.FS
+
+// DefaultPromptSet is the built-in prompt language used when none is chosen
+const DefaultPromptSet = "vi"
+
+// Templates every prompt set must define. The others (helper prompts for the
+// judge, summarizer, moderator and polls, format and rubric texts, notes and
+// export labels) are optional and fall back to the default set.
+var requiredPrompts = []string{"topic", "message", "interjection", "interjection_reply", "phase", "continue", "topic_change", "retry"}
+
+// PromptSet is a named group of prompt templates, usually one per language
+type PromptSet struct {
+	ID      string `json:"id"`
+	Builtin bool   `json:"builtin"`
+	Path    string `json:"path,omitempty"` // Source file for sets loaded from disk
+	tmpl    *template.Template
+}
+
+var (
+	promptMu         sync.RWMutex
+	promptSets       = map[string]*PromptSet{}
+	defaultPromptSet = DefaultPromptSet
+)
+
+func init() {
+	entries, err := builtinPromptFS.ReadDir("prompts")
+	if err != nil {
+		panic(err)
+	}
+	for _, e := range entries {
+		data, err := builtinPromptFS.ReadFile("prompts/" + e.Name())
+		if err != nil {
+			panic(err)
+		}
+		id := strings.TrimSuffix(e.Name(), ".tmpl")
+		set, err := parsePromptSet(id, string(data))
+		if err != nil {
+			panic(err)
+		}
+		set.Builtin = true
+		promptSets[id] = set
+	}
+}
+
+// parsePromptSet parses a template file and checks it defines every prompt
+func parsePromptSet(id, text string) (*PromptSet, error) {
+	tmpl, err := template.New(id).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range requiredPrompts {
+		if tmpl.Lookup(name) == nil {
+			return nil, fmt.Errorf("prompt set %s: missing template %q", id, name)
+		}
+	}
+	return &PromptSet{ID: id, tmpl: tmpl}, nil
+}
+
+// LoadPromptDir adds every *.tmpl file in dir as a prompt set named after the
+// file; a file named like a built-in set overrides it. Broken files are
+// skipped and reported together.
+func LoadPromptDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		id := strings.TrimSuffix(filepath.Base(path), ".tmpl")
+		set, err := parsePromptSet(id, string(data))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		set.Path = path
+
+		promptMu.Lock()
+		promptSets[id] = set
+		promptMu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// PromptSets returns the available prompt sets, sorted by ID
+func PromptSets() []PromptSet {
+	promptMu.RLock()
+	defer promptMu.RUnlock()
+	sets := make([]PromptSet, 0, len(promptSets))
+	for _, s := range promptSets {
+		sets = append(sets, *s)
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].ID < sets[j].ID })
+	return sets
+}
+
+// SetDefaultPromptSet chooses the prompt set for debates that don't pick one
+func SetDefaultPromptSet(id string) error {
+	promptMu.Lock()
+	defer promptMu.Unlock()
+	if _, ok := promptSets[id]; !ok {
+		return fmt.Errorf("unknown prompt set: %s", id)
+	}
+	defaultPromptSet = id
+	return nil
+}
+
+// GetDefaultPromptSet returns the prompt set used when a debate doesn't pick one
+func GetDefaultPromptSet() string {
+	promptMu.RLock()
+	defer promptMu.RUnlock()
+	return defaultPromptSet
+}
+
+// lookupPromptSet returns a prompt set, the default one for an empty ID
+func lookupPromptSet(id string) (*PromptSet, bool) {
+	promptMu.RLock()
+	defer promptMu.RUnlock()
+	if id == "" {
+		id = defaultPromptSet
+	}
+	set, ok := promptSets[id]
+	return set, ok
+}
+
+// PromptAgent is an agent as seen by prompt templates
+type PromptAgent struct {
+	ID   string
+	Name string
+	Role string
+	Side Side // Empty outside formats that use sides
+}
+
+// PromptPhase is the current phase of a structured format
+type PromptPhase struct {
+	Index        int // 1-based
+	Total        int
+	Name         string
+	Instructions string
+	WordLimit    int
+}
+
+// PromptData holds the variables available to prompt templates
+type PromptData struct {
+	Topic     string
+	Agent     PromptAgent   // Agent whose context is being built
+	Agents    []PromptAgent // The whole panel
+	Departed  []PromptAgent // Agents who spoke earlier but have left the panel
+	Anonymous bool          // The agent must not learn who wrote what (Delphi rounds)
+	Round     int           // 1-based; a round is one turn per agent
+	Format    *Format       // nil for open discussion
+	Phase     *PromptPhase  // nil unless a format phase is active
+	EndMarker string        // Set when agents may end the debate
+
+	// Set for the message, interjection, summary, anonymous_speaker and
+	// agent_joined/agent_left templates (Addressed and Private also for
+	// interjection_reply)
+	Speaker   string
+	Content   string
+	Sources   string
+	Addressee string // Name of the agent an interjection asked, empty for everyone
+	Addressed bool   // The interjection asked the agent being prompted
+	Private   bool   // The message (or closing interjection) has a restricted audience
+	Audience  string // Names of a private message's audience besides its author; also set for the whisper template
+
+	// Set for the retry and debate_ended templates
+	Reason string // Why a hook rejected the previous reply, or why the debate ended
+
+	// Set for the helper prompts (judge, summaries, moderator, bid, consensus)
+	Transcript string      // Public messages as plain text, empty before anyone speaks
+	Previous   string      // The last rolling summary, for summary_rolling
+	Rubric     []Criterion // For judge
+
+	// Set for the cast, branch_name, ended_* and summary_kind templates
+	Count      int
+	Total      int
+	Similarity float64
+	Providers  string
+	Kind       string
+}
+
+// renderPrompt executes a named template from a set. Templates the set
+// leaves out come from the built-in default, and a broken custom set falls
+// back to it so a debate never stalls on a typo.
+func renderPrompt(setID, name string, data PromptData) string {
+	set, ok := lookupPromptSet(setID)
+	if !ok {
+		set, _ = lookupPromptSet(DefaultPromptSet)
+	}
+	if set.tmpl.Lookup(name) == nil && set.ID != DefaultPromptSet {
+		return renderPrompt(DefaultPromptSet, name, data)
+	}
+	var buf bytes.Buffer
+	if err := set.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		log.Printf("Warning: prompt %s/%s failed: %v", set.ID, name, err)
+		if set.ID == DefaultPromptSet {
+			return ""
+		}
+		return renderPrompt(DefaultPromptSet, name, data)
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// SetPromptSet chooses the prompt set for this debate (empty = the default)
+func (m *Manager) SetPromptSet(id string) error {
+	if id != "" {
+		if _, ok := lookupPromptSet(id); !ok {
+			return fmt.Errorf("unknown prompt set: %s", id)
+		}
+	}
+	m.mu.Lock()
+	m.promptSet = id
+	m.mu.Unlock()
+
+	m.persist()
+	return nil
+}
+
+// GetPromptSet returns the prompt set this debate uses
+func (m *Manager) GetPromptSet() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.promptSet == "" {
+		return GetDefaultPromptSet()
+	}
+	return m.promptSet
+}
+
+// PreviewPrompt returns the messages an agent would be sent for its next
+// turn, rendered with setID (empty = the debate's own set)
+func (m *Manager) PreviewPrompt(agentID, setID string) ([]provider.Message, error) {
+	if setID != "" {
+		if _, ok := lookupPromptSet(setID); !ok {
+			return nil, fmt.Errorf("unknown prompt set: %s", setID)
+		}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a := m.findAgentLocked(agentID)
+	if a == nil {
+		return nil, fmt.Errorf("agent not found: %s", agentID)
+	}
+	if setID == "" {
+		setID = m.promptSet
+	}
+	return m.buildPromptLocked(setID, a, m.messages), nil
+}
+
+// promptDataLocked fills the template variables shared by every prompt for
+// speaker, with format texts from setID; callers must hold m.mu
+func (m *Manager) promptDataLocked(setID string, speaker *agent.Agent, history []Message) PromptData {
+	data := PromptData{
+		Topic:     m.topic,
+		Agent:     m.promptAgentLocked(speaker),
+		Agents:    make([]PromptAgent, len(m.agents)),
+		Round:     1,
+		Anonymous: speaker.Visibility.Anonymous,
+	}
+	for i, a := range m.agents {
+		data.Agents[i] = m.promptAgentLocked(a)
+	}
+	// Earlier speakers no longer on the panel, so their messages aren't a puzzle
+	seen := make(map[string]bool)
+	for _, msg := range agentMessages(m.visibleHistoryLocked(speaker, history)) {
+		if seen[msg.AgentID] || msg.AgentID == summaryContextID || m.findAgentLocked(msg.AgentID) != nil {
+			continue
+		}
+		seen[msg.AgentID] = true
+		data.Departed = append(data.Departed, PromptAgent{ID: msg.AgentID, Name: msg.AgentName})
+	}
+	if len(m.agents) > 0 {
+		data.Round = len(agentMessages(history))/len(m.agents) + 1
+	}
+	if m.format != nil {
+		format := m.format.localized(setID)
+		data.Format = &format
+		if m.phaseIndex < len(format.Phases) {
+			phase := format.Phases[m.phaseIndex]
+			data.Phase = &PromptPhase{
+				Index:        m.phaseIndex + 1,
+				Total:        len(format.Phases),
+				Name:         phase.Name,
+				Instructions: phase.Instructions,
+				WordLimit:    phase.WordLimit,
+			}
+		}
+	}
+	if m.termOpts.EndMarker {
+		data.EndMarker = renderPrompt(setID, "end_marker", PromptData{})
+	}
+	return data
+}
+
+// promptAgentLocked describes an agent for templates; callers must hold m.mu
+func (m *Manager) promptAgentLocked(a *agent.Agent) PromptAgent {
+	return PromptAgent{ID: a.ID, Name: a.Name, Role: a.Role, Side: m.sides[a.ID]}
+}
+
+// promptAgents describes agents for helper templates, with their sides if any
+func promptAgents(agents []*agent.Agent, sides map[string]Side) []PromptAgent {
+	result := make([]PromptAgent, len(agents))
+	for i, a := range agents {
+		result[i] = PromptAgent{ID: a.ID, Name: a.Name, Role: a.Role, Side: sides[a.ID]}
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package debate
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/user/talk/internal/provider"
+)
+
+// replyProvider always streams the same reply
+type replyProvider struct{ reply string }
+
+func (p replyProvider) Name() string { return "reply" }
+
+func (p replyProvider) Chat(context.Context, []provider.Message, provider.Options) (<-chan provider.StreamChunk, error) {
+	ch := make(chan provider.StreamChunk, 2)
+	ch <- provider.StreamChunk{Content: p.reply}
+	ch <- provider.StreamChunk{Done: true, FinishReason: "stop"}
+	close(ch)
+	return ch, nil
+}
+
+func templateNames(tmpl *template.Template) []string {
+	var names []string
+	for _, t := range tmpl.Templates() {
+		if t.Name() != tmpl.Name() {
+			names = append(names, t.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestBuiltinPromptSetsMatch(t *testing.T) {
+	vi, _ := lookupPromptSet("vi")
+	en, _ := lookupPromptSet("en")
+	if got, want := strings.Join(templateNames(en.tmpl), " "), strings.Join(templateNames(vi.tmpl), " "); got != want {
+		t.Errorf("en templates differ from vi\n en: %s\n vi: %s", got, want)
+	}
+}
+
+func TestEnglishPromptsHaveNoVietnamese(t *testing.T) {
+	data := PromptData{
+		Topic:      "Carbon tax",
+		Agent:      PromptAgent{ID: "a1", Name: "Alpha", Role: "r", Side: SidePro},
+		Agents:     []PromptAgent{{ID: "a1", Name: "Alpha", Role: "r", Side: SidePro}},
+		Transcript: "[a1] Alpha:\nfirst",
+		Previous:   "so far",
+		Rubric:     defaultRubric("en"),
+		Format:     &Format{Name: "Oxford", UsesSides: true},
+		Phase:      &PromptPhase{Index: 1, Total: 3, Name: "Opening"},
+		EndMarker:  "[END]",
+		Speaker:    "Alpha",
+		Content:    "text",
+		Reason:     "too short",
+		Count:      3,
+		Total:      4,
+		Kind:       SummaryFinal,
+	}
+	en, _ := lookupPromptSet("en")
+	for _, name := range templateNames(en.tmpl) {
+		if name == "side" {
+			continue
+		}
+		out := renderPrompt("en", name, data)
+		if strings.ContainsAny(out, "ăâđêôơưạảấầẩẫậắằẳẵặẹẻẽếềểễệỉịọỏốồổỗộớờởỡợụủứừửữựỳỵỷỹ") {
+			t.Errorf("en template %s renders Vietnamese: %q", name, out)
+		}
+	}
+}
+
+func TestStripEndMarkerUsesPromptSet(t *testing.T) {
+	if content, ok := stripEndMarker("en", "Done here. [END]"); !ok || content != "Done here." {
+		t.Errorf("en marker: got %q, %v", content, ok)
+	}
+	if _, ok := stripEndMarker("en", "Xong. [KẾT THÚC]"); ok {
+		t.Error("the vi marker ended an en debate")
+	}
+	if content, ok := stripEndMarker("vi", "Xong. [KẾT THÚC]"); !ok || content != "Xong." {
+		t.Errorf("vi marker: got %q, %v", content, ok)
+	}
+}
+
+func TestEnglishDebateEndsOnItsMarker(t *testing.T) {
+	m, _ := newTestManager(t)
+	for _, a := range m.agents {
+		a.Provider = replyProvider{reply: "We agree. [END]"}
+	}
+	if err := m.SetPromptSet("en"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.SetTermination(TerminationOptions{EndMarker: true}); err != nil {
+		t.Fatal(err)
+	}
+	start(t, m, "Carbon tax")
+	nextTurns(t, m, 1)
+
+	if ended := m.GetEnded(); ended == nil || ended.Detail != "Alpha proposed ending the discussion" {
+		t.Fatalf("ended = %+v, want the end marker to stop the debate", ended)
+	}
+	msgs := m.GetMessages()
+	if len(msgs) != 2 || msgs[0].Content != "We agree." {
+		t.Fatalf("messages = %+v, want the stripped turn and an end note", msgs)
+	}
+	if note := msgs[1]; note.AgentName != "System" || note.Content != "--- Discussion ended: Alpha proposed ending the discussion ---" {
+		t.Errorf("end note = %q by %q", note.Content, note.AgentName)
+	}
+}
@@ -0,0 +1,322 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Run states reported by RunStatus
+const (
+	RunStateIdle     = "idle"
+	RunStateRunning  = "running"
+	RunStatePaused   = "paused"
+	RunStateFinished = "finished"
+)
+
+// RunOptions controls an autonomous, server-side debate run
+type RunOptions struct {
+	Rounds         int           // Full rotations through the panel (0 = no round limit)
+	StopAfterTurns int           // Hard cap on turns (0 = no cap)
+	Delay          time.Duration // Pause between turns
+	Parallel       bool          // Each step is a parallel round where all agents answer at once
+	Critique       bool          // Parallel rounds add a cross-critique round
+}
+
+// RunStatus describes the progress of the current or last autonomous run
+type RunStatus struct {
+	State      string    `json:"state"`
+	Turn       int       `json:"turn"`
+	TotalTurns int       `json:"total_turns"`
+	Round      int       `json:"round"`
+	DelayMs    int64     `json:"delay_ms"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	Reason     string    `json:"reason,omitempty"` // Why the run finished
+}
+
+// runState holds the bookkeeping for an autonomous run
+type runState struct {
+	status   RunStatus
+	panel    int // Panel size at start, used to compute rounds
+	parallel bool
+	critique bool
+	cancel   context.CancelFunc
+	resumeCh chan struct{} // Non-nil while paused, closed on resume
+}
+
+// retryDelay is how long the runner waits when a manual turn is in progress
+const retryDelay = 500 * time.Millisecond
+
+// StartRun starts an autonomous run that executes turns in a goroutine until
+// the configured rounds or turns are exhausted, the run is stopped, or the
+// debate itself is stopped.
+func (m *Manager) StartRun(opts RunOptions) error {
+	m.mu.Lock()
+	if !m.isRunning {
+		m.mu.Unlock()
+		return ErrNotRunning
+	}
+	if m.run != nil && m.run.status.State != RunStateFinished {
+		m.mu.Unlock()
+		return fmt.Errorf("an autonomous run is already active")
+	}
+	if len(m.agents) == 0 {
+		m.mu.Unlock()
+		return fmt.Errorf("no agents available")
+	}
+
+	total := opts.StopAfterTurns
+	if opts.Rounds > 0 {
+		roundTurns := opts.Rounds * len(m.agents)
+		if total == 0 || roundTurns < total {
+			total = roundTurns
+		}
+	}
+	// An agenda with turn budgets runs to its end unless capped
+	if remaining, ok := m.remainingAgendaTurnsLocked(); total == 0 && len(m.agenda) > 0 && ok {
+		total = remaining
+	}
+	// Structured formats run to the end of their phases unless capped
+	if remaining := m.remainingFormatTurnsLocked(); m.format != nil && (total == 0 || remaining < total) {
+		total = remaining
+	}
+	if total <= 0 {
+		m.mu.Unlock()
+		if m.format != nil {
+			return ErrFormatComplete
+		}
+		if len(m.agenda) > 0 {
+			return ErrAgendaComplete
+		}
+		return fmt.Errorf("rounds or stop_after_turns must be greater than 0")
+	}
+
+	// Derive from the debate context so Stop/Reset also end the run
+	ctx, cancel := context.WithCancel(m.ctx)
+	r := &runState{
+		status: RunStatus{
+			State:      RunStateRunning,
+			TotalTurns: total,
+			DelayMs:    opts.Delay.Milliseconds(),
+			StartedAt:  time.Now(),
+		},
+		panel:    len(m.agents),
+		parallel: opts.Parallel,
+		critique: opts.Critique,
+		cancel:   cancel,
+	}
+	m.run = r
+	m.mu.Unlock()
+
+	m.emit(map[string]interface{}{
+		"type":        "run_started",
+		"total_turns": total,
+		"delay_ms":    opts.Delay.Milliseconds(),
+	})
+
+	go m.runLoop(ctx, r, opts.Delay)
+	return nil
+}
+
+// PauseRun pauses the autonomous run after the current turn
+func (m *Manager) PauseRun() error {
+	m.mu.Lock()
+	r := m.run
+	if r == nil || r.status.State != RunStateRunning {
+		m.mu.Unlock()
+		return fmt.Errorf("no active run to pause")
+	}
+	r.status.State = RunStatePaused
+	r.resumeCh = make(chan struct{})
+	status := r.status
+	m.mu.Unlock()
+
+	m.emit(map[string]interface{}{
+		"type":   "run_paused",
+		"status": status,
+	})
+	return nil
+}
+
+// ResumeRun resumes a paused autonomous run
+func (m *Manager) ResumeRun() error {
+	m.mu.Lock()
+	r := m.run
+	if r == nil || r.status.State != RunStatePaused {
+		m.mu.Unlock()
+		return fmt.Errorf("no paused run to resume")
+	}
+	r.status.State = RunStateRunning
+	close(r.resumeCh)
+	r.resumeCh = nil
+	status := r.status
+	m.mu.Unlock()
+
+	m.emit(map[string]interface{}{
+		"type":   "run_resumed",
+		"status": status,
+	})
+	return nil
+}
+
+// StopRun cancels the autonomous run. A turn already streaming is allowed to finish.
+func (m *Manager) StopRun() error {
+	m.mu.Lock()
+	r := m.run
+	if r == nil || r.status.State == RunStateFinished {
+		m.mu.Unlock()
+		return fmt.Errorf("no active run to stop")
+	}
+	r.cancel()
+	m.mu.Unlock()
+	return nil
+}
+
+// GetRunStatus returns the status of the current or last autonomous run
+func (m *Manager) GetRunStatus() RunStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.run == nil {
+		return RunStatus{State: RunStateIdle}
+	}
+	return m.run.status
+}
+
+// runLoop executes turns until the run completes or is cancelled
+func (m *Manager) runLoop(ctx context.Context, r *runState, delay time.Duration) {
+	reason := "completed"
+	var runErr error
+
+	defer func() {
+		m.mu.Lock()
+		r.cancel()
+		// A termination condition stops the debate, which cancels the run
+		if m.ended != nil && !m.isRunning {
+			reason = m.ended.Reason
+		}
+		r.status.State = RunStateFinished
+		r.status.Reason = reason
+		status := r.status
+		m.mu.Unlock()
+
+		event := map[string]interface{}{
+			"type":   "run_finished",
+			"reason": reason,
+			"status": status,
+		}
+		if runErr != nil {
+			event["error"] = runErr.Error()
+		}
+		m.emit(event)
+	}()
+
+	for {
+		m.mu.RLock()
+		done := r.status.Turn >= r.status.TotalTurns
+		resumeCh := r.resumeCh
+		m.mu.RUnlock()
+
+		if done {
+			return
+		}
+
+		// Wait while paused
+		if resumeCh != nil {
+			select {
+			case <-resumeCh:
+			case <-ctx.Done():
+				reason = "cancelled"
+				return
+			}
+		}
+
+		if ctx.Err() != nil {
+			reason = "cancelled"
+			return
+		}
+
+		err := m.runTurn(r)
+		if errors.Is(err, ErrTurnInProgress) {
+			// A manual turn is streaming; try again shortly
+			select {
+			case <-time.After(retryDelay):
+				continue
+			case <-ctx.Done():
+				reason = "cancelled"
+				return
+			}
+		}
+		if errors.Is(err, ErrNotRunning) {
+			reason = "stopped"
+			return
+		}
+		if errors.Is(err, ErrFormatComplete) {
+			reason = "format_complete"
+			return
+		}
+		if errors.Is(err, ErrAgendaComplete) {
+			reason = "agenda_complete"
+			return
+		}
+		if err != nil {
+			reason = "error"
+			runErr = err
+			log.Printf("Autonomous run stopped on error: %v", err)
+			return
+		}
+
+		m.mu.Lock()
+		// A parallel round gives every agent a turn
+		if r.parallel {
+			r.status.Turn += r.panel
+		} else {
+			r.status.Turn++
+		}
+		r.status.Round = (r.status.Turn + r.panel - 1) / r.panel
+		status := r.status
+		m.mu.Unlock()
+
+		m.emit(map[string]interface{}{
+			"type":   "run_progress",
+			"status": status,
+		})
+
+		if status.Turn >= status.TotalTurns {
+			return
+		}
+
+		if delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				reason = "cancelled"
+				return
+			}
+		}
+	}
+}
+
+// runTurn executes a single turn (or parallel round), forwarding its stream as events
+func (m *Manager) runTurn(r *runState) error {
+	streamCh := make(chan StreamMessage, 100)
+	forwarded := make(chan struct{})
+
+	go func() {
+		defer close(forwarded)
+		for msg := range streamCh {
+			m.emit(msg)
+		}
+	}()
+
+	var err error
+	if r.parallel {
+		err = m.ParallelRound(r.critique, streamCh)
+	} else {
+		err = m.NextTurn(streamCh)
+	}
+	close(streamCh)
+	<-forwarded
+	return err
+}
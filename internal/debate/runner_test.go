@@ -0,0 +1,169 @@
+package debate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/user/talk/internal/provider"
+)
+
+// gateProvider holds each reply until the test lets it through
+type gateProvider struct{ gate chan struct{} }
+
+func (p gateProvider) Name() string { return "gate" }
+
+func (p gateProvider) Chat(ctx context.Context, _ []provider.Message, _ provider.Options) (<-chan provider.StreamChunk, error) {
+	ch := make(chan provider.StreamChunk, 2)
+	go func() {
+		defer close(ch)
+		select {
+		case <-p.gate:
+		case <-ctx.Done():
+			ch <- provider.StreamChunk{Error: ctx.Err()}
+			return
+		}
+		ch <- provider.StreamChunk{Content: "lượt nói"}
+		ch <- provider.StreamChunk{Done: true, FinishReason: "stop"}
+	}()
+	return ch, nil
+}
+
+// eventTypes returns a manager whose events are also sent, by type, to the
+// returned channel
+func eventTypes(t *testing.T, m *Manager) <-chan string {
+	t.Helper()
+	types := make(chan string, 1000)
+	m.SetEventFunc(func(ev interface{}) {
+		switch e := ev.(type) {
+		case StreamMessage:
+			types <- e.Type
+		case map[string]interface{}:
+			types <- e["type"].(string)
+		}
+	})
+	return types
+}
+
+// waitEvent reads events until one of the given type arrives
+func waitEvent(t *testing.T, types <-chan string, want string) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case got := <-types:
+			if got == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+}
+
+func TestRunCompletesRounds(t *testing.T) {
+	m, _ := newTestManager(t)
+	types := eventTypes(t, m)
+	start(t, m, "Thuế carbon")
+
+	if err := m.StartRun(RunOptions{Rounds: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.StartRun(RunOptions{Rounds: 1}); err == nil {
+		t.Error("a second run started while the first was active")
+	}
+	waitEvent(t, types, "run_finished")
+
+	status := m.GetRunStatus()
+	if status.State != RunStateFinished || status.Reason != "completed" || status.Turn != 6 || status.Round != 2 {
+		t.Errorf("status = %+v, want 6 turns over 2 rounds completed", status)
+	}
+	if n := len(m.GetMessages()); n != 6 {
+		t.Errorf("messages = %d, want 6", n)
+	}
+}
+
+func TestRunPauseResumeStop(t *testing.T) {
+	m, _ := newTestManager(t)
+	gate := make(chan struct{})
+	for _, a := range m.agents {
+		a.Provider = gateProvider{gate: gate}
+	}
+	types := eventTypes(t, m)
+	start(t, m, "Thuế carbon")
+
+	if err := m.StartRun(RunOptions{StopAfterTurns: 10}); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, types, "start")
+
+	// Pausing lets the streaming turn finish, then holds the run
+	if err := m.PauseRun(); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.PauseRun(); err == nil {
+		t.Error("paused a run that was already paused")
+	}
+	gate <- struct{}{}
+	waitEvent(t, types, "run_progress")
+	select {
+	case gate <- struct{}{}:
+		t.Fatal("a turn started while the run was paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if status := m.GetRunStatus(); status.State != RunStatePaused || status.Turn != 1 {
+		t.Fatalf("status = %+v, want paused after 1 turn", status)
+	}
+
+	if err := m.ResumeRun(); err != nil {
+		t.Fatal(err)
+	}
+	gate <- struct{}{}
+	waitEvent(t, types, "run_progress")
+	waitEvent(t, types, "start")
+
+	// Stopping also lets the streaming turn finish
+	if err := m.StopRun(); err != nil {
+		t.Fatal(err)
+	}
+	gate <- struct{}{}
+	waitEvent(t, types, "run_finished")
+
+	status := m.GetRunStatus()
+	if status.State != RunStateFinished || status.Reason != "cancelled" {
+		t.Errorf("status = %+v, want cancelled", status)
+	}
+	if n := len(m.GetMessages()); n != 3 {
+		t.Errorf("messages = %d, want the 3 turns that started", n)
+	}
+	if err := m.StopRun(); err == nil {
+		t.Error("stopped a finished run")
+	}
+}
+
+func TestRunEndsWhenDebateStops(t *testing.T) {
+	m, _ := newTestManager(t)
+	gate := make(chan struct{})
+	for _, a := range m.agents {
+		a.Provider = gateProvider{gate: gate}
+	}
+	types := eventTypes(t, m)
+	start(t, m, "Thuế carbon")
+
+	if err := m.StartRun(RunOptions{StopAfterTurns: 5}); err != nil {
+		t.Fatal(err)
+	}
+	waitEvent(t, types, "start")
+	m.Stop()
+	waitEvent(t, types, "run_finished")
+
+	if status := m.GetRunStatus(); status.State != RunStateFinished || status.Reason != "cancelled" {
+		t.Errorf("status = %+v, want cancelled", status)
+	}
+	if n := len(m.GetMessages()); n != 0 {
+		t.Errorf("messages = %d, want the stopped turn dropped", n)
+	}
+	if err := m.StartRun(RunOptions{Rounds: 1}); err != ErrNotRunning {
+		t.Errorf("StartRun on a stopped debate = %v, want ErrNotRunning", err)
+	}
+}
@@ -0,0 +1,319 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// Additional speaker-selection modes
+const (
+	ModeWeightedRandom Mode = "weighted_random"
+	ModeLeastSpoken    Mode = "least_spoken"
+	ModeMention        Mode = "mention"
+	ModeBidding        Mode = "bidding"
+)
+
+// SelectionState is what a selector sees when picking the next speaker
+type SelectionState struct {
+	Topic        string
+	Agents       []*agent.Agent
+	Messages     []Message
+	CurrentIndex int          // Rotation position; rotating selectors advance it
+	Moderator    *agent.Agent // Optional moderator (free-form mode)
+	PromptSet    string       // Prompt set for the moderator and bids
+
+	emit EventFunc
+}
+
+// SpeakerSelector picks the next agent to speak. Select runs outside the
+// manager lock and may call models; it must return one of state.Agents.
+type SpeakerSelector interface {
+	Select(ctx context.Context, state *SelectionState) (*agent.Agent, error)
+}
+
+// SelectorOptions holds per-mode tuning passed through /api/debate/mode
+type SelectorOptions struct {
+	Weights map[string]float64 `json:"weights,omitempty"` // weighted_random: agent ID -> weight (default 1)
+}
+
+var selectorFactories = map[Mode]func(SelectorOptions) SpeakerSelector{
+	ModeRoundRobin:     func(SelectorOptions) SpeakerSelector { return roundRobinSelector{} },
+	ModeFreeForm:       func(SelectorOptions) SpeakerSelector { return freeFormSelector{} },
+	ModeWeightedRandom: func(o SelectorOptions) SpeakerSelector { return weightedRandomSelector{weights: o.Weights} },
+	ModeLeastSpoken:    func(SelectorOptions) SpeakerSelector { return leastSpokenSelector{} },
+	ModeMention:        func(SelectorOptions) SpeakerSelector { return mentionSelector{} },
+	ModeBidding:        func(SelectorOptions) SpeakerSelector { return biddingSelector{} },
+}
+
+// IsValidMode reports whether mode is a supported debate mode
+func IsValidMode(mode Mode) bool {
+	_, ok := selectorFactories[mode]
+	return ok
+}
+
+// Modes returns all supported modes, sorted by name
+func Modes() []Mode {
+	modes := make([]Mode, 0, len(selectorFactories))
+	for mode := range selectorFactories {
+		modes = append(modes, mode)
+	}
+	sort.Slice(modes, func(i, j int) bool { return modes[i] < modes[j] })
+	return modes
+}
+
+// NewSelector creates the built-in selector for a mode
+func NewSelector(mode Mode, opts SelectorOptions) (SpeakerSelector, error) {
+	factory, ok := selectorFactories[mode]
+	if !ok {
+		return nil, fmt.Errorf("invalid mode: %s", mode)
+	}
+	return factory(opts), nil
+}
+
+// lastSpeaker returns the ID of the last agent who spoke, ignoring system messages
+func (s *SelectionState) lastSpeaker() string {
+	for i := len(s.Messages) - 1; i >= 0; i-- {
+		if s.Messages[i].AgentID != "system" {
+			return s.Messages[i].AgentID
+		}
+	}
+	return ""
+}
+
+// rotate returns the agent at the rotation position and advances it
+func (s *SelectionState) rotate() *agent.Agent {
+	idx := s.CurrentIndex % len(s.Agents)
+	s.CurrentIndex = (idx + 1) % len(s.Agents)
+	return s.Agents[idx]
+}
+
+// rotateSkippingLast rotates, skipping the last speaker when someone else is available
+func (s *SelectionState) rotateSkippingLast() *agent.Agent {
+	last := s.lastSpeaker()
+	for range s.Agents {
+		a := s.rotate()
+		if a.ID != last || len(s.Agents) == 1 {
+			return a
+		}
+	}
+	return s.rotate()
+}
+
+// point moves the rotation position to just after the chosen agent
+func (s *SelectionState) point(chosen *agent.Agent) {
+	for i, a := range s.Agents {
+		if a.ID == chosen.ID {
+			s.CurrentIndex = (i + 1) % len(s.Agents)
+			return
+		}
+	}
+}
+
+// roundRobinSelector cycles through agents in panel order
+type roundRobinSelector struct{}
+
+func (roundRobinSelector) Select(_ context.Context, state *SelectionState) (*agent.Agent, error) {
+	return state.rotate(), nil
+}
+
+// freeFormSelector lets the moderator choose, or rotates without repeating the last speaker
+type freeFormSelector struct{}
+
+func (freeFormSelector) Select(ctx context.Context, state *SelectionState) (*agent.Agent, error) {
+	if state.Moderator != nil {
+		return moderatedSelect(ctx, state), nil
+	}
+	return state.rotateSkippingLast(), nil
+}
+
+// weightedRandomSelector picks randomly, proportional to per-agent weights
+type weightedRandomSelector struct {
+	weights map[string]float64
+}
+
+func (s weightedRandomSelector) Select(_ context.Context, state *SelectionState) (*agent.Agent, error) {
+	last := state.lastSpeaker()
+
+	candidates := make([]*agent.Agent, 0, len(state.Agents))
+	weights := make([]float64, 0, len(state.Agents))
+	total := 0.0
+	for _, a := range state.Agents {
+		if a.ID == last && len(state.Agents) > 1 {
+			continue
+		}
+		w, ok := s.weights[a.ID]
+		if !ok {
+			w = 1
+		}
+		if w <= 0 {
+			continue
+		}
+		candidates = append(candidates, a)
+		weights = append(weights, w)
+		total += w
+	}
+
+	if len(candidates) == 0 {
+		return state.rotateSkippingLast(), nil
+	}
+
+	r := rand.Float64() * total
+	chosen := candidates[len(candidates)-1]
+	for i, w := range weights {
+		if r < w {
+			chosen = candidates[i]
+			break
+		}
+		r -= w
+	}
+	state.point(chosen)
+	return chosen, nil
+}
+
+// leastSpokenSelector gives the floor to whoever has spoken least so far
+type leastSpokenSelector struct{}
+
+func (leastSpokenSelector) Select(_ context.Context, state *SelectionState) (*agent.Agent, error) {
+	counts := make(map[string]int, len(state.Agents))
+	for _, msg := range state.Messages {
+		counts[msg.AgentID]++
+	}
+	last := state.lastSpeaker()
+
+	// Walk in rotation order so ties go to whoever is next in line
+	var chosen *agent.Agent
+	n := len(state.Agents)
+	for i := 0; i < n; i++ {
+		a := state.Agents[(state.CurrentIndex+i)%n]
+		if a.ID == last && n > 1 {
+			continue
+		}
+		if chosen == nil || counts[a.ID] < counts[chosen.ID] {
+			chosen = a
+		}
+	}
+	if chosen == nil {
+		return state.rotate(), nil
+	}
+	state.point(chosen)
+	return chosen, nil
+}
+
+// mentionRegex matches @mentions by ID or quoted name, e.g. @critic or @"Nhà Phản Biện"
+var mentionRegex = regexp.MustCompile(`@(?:"([^"]+)"|([\p{L}\p{N}_-]+))`)
+
+// mentionSelector lets an @named agent reply next, rotating otherwise
+type mentionSelector struct{}
+
+func (mentionSelector) Select(_ context.Context, state *SelectionState) (*agent.Agent, error) {
+	if len(state.Messages) > 0 {
+		last := state.Messages[len(state.Messages)-1]
+		if chosen := mentionedAgent(state.Agents, last); chosen != nil {
+			state.point(chosen)
+			return chosen, nil
+		}
+	}
+	return state.rotateSkippingLast(), nil
+}
+
+// mentionedAgent returns the first agent @named in msg other than its author
+func mentionedAgent(agents []*agent.Agent, msg Message) *agent.Agent {
+	for _, match := range mentionRegex.FindAllStringSubmatch(msg.Content, -1) {
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+		if a := findAgent(agents, name); a != nil && a.ID != msg.AgentID {
+			return a
+		}
+	}
+	return nil
+}
+
+// Bid is an agent's self-reported urge to speak next
+type Bid struct {
+	AgentID string  `json:"agent_id"`
+	Score   float64 `json:"score"`
+	Reason  string  `json:"reason,omitempty"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// biddingSelector asks every agent how much it wants to speak and picks the highest bid
+type biddingSelector struct{}
+
+func (biddingSelector) Select(ctx context.Context, state *SelectionState) (*agent.Agent, error) {
+	last := state.lastSpeaker()
+	transcript := recentTranscript(state.Messages, moderatorWindow)
+	system := renderPrompt(state.PromptSet, "bid_system", PromptData{})
+
+	bids := make([]Bid, len(state.Agents))
+	var wg sync.WaitGroup
+	for i, a := range state.Agents {
+		bids[i] = Bid{AgentID: a.ID, Score: -1}
+		if a.ID == last && len(state.Agents) > 1 {
+			continue
+		}
+		if a.IsHuman() {
+			bids[i].Error = "human participants don't bid"
+			continue
+		}
+		wg.Add(1)
+		go func(i int, a *agent.Agent) {
+			defer wg.Done()
+			prompt := renderPrompt(state.PromptSet, "bid", PromptData{
+				Topic:      state.Topic,
+				Agent:      PromptAgent{ID: a.ID, Name: a.Name, Role: a.Role},
+				Transcript: transcript,
+			})
+
+			resp, err := askAgent(ctx, a, system, prompt)
+			if err == nil {
+				err = extractJSON(resp, &bids[i])
+			}
+			if err != nil {
+				bids[i].Score = -1
+				bids[i].Error = err.Error()
+			}
+			bids[i].AgentID = a.ID
+		}(i, a)
+	}
+	wg.Wait()
+
+	// Highest bid wins; ties go to whoever is next in rotation
+	var chosen *agent.Agent
+	best := -1.0
+	n := len(state.Agents)
+	for i := 0; i < n; i++ {
+		idx := (state.CurrentIndex + i) % n
+		if bids[idx].Score > best {
+			best = bids[idx].Score
+			chosen = state.Agents[idx]
+		}
+	}
+
+	fallback := chosen == nil
+	if fallback {
+		log.Printf("Bidding fallback to rotation: no valid bids")
+		chosen = state.rotateSkippingLast()
+	} else {
+		state.point(chosen)
+	}
+
+	if state.emit != nil {
+		state.emit(map[string]interface{}{
+			"type":       "bids",
+			"bids":       bids,
+			"agent_id":   chosen.ID,
+			"agent_name": chosen.Name,
+			"fallback":   fallback,
+		})
+	}
+	return chosen, nil
+}
@@ -0,0 +1,106 @@
+package debate
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/user/talk/internal/agent"
+)
+
+func selectorAgents() []*agent.Agent {
+	p := &scriptProvider{}
+	return []*agent.Agent{
+		testAgent("a1", "Alpha", p),
+		testAgent("a2", "Beta", p),
+		testAgent("a3", "Nhà Phản Biện", p),
+	}
+}
+
+// picks runs the selector n times, appending each pick as a message
+func picks(t *testing.T, sel SpeakerSelector, state *SelectionState, n int) []string {
+	t.Helper()
+	var ids []string
+	for i := 0; i < n; i++ {
+		a, err := sel.Select(context.Background(), state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, a.ID)
+		state.Messages = append(state.Messages, Message{AgentID: a.ID})
+	}
+	return ids
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	state := &SelectionState{Agents: selectorAgents()}
+	got := picks(t, roundRobinSelector{}, state, 4)
+	if want := []string{"a1", "a2", "a3", "a1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("picks = %v, want %v", got, want)
+	}
+}
+
+func TestLeastSpokenSelector(t *testing.T) {
+	state := &SelectionState{
+		Agents:   selectorAgents(),
+		Messages: []Message{{AgentID: "a1"}, {AgentID: "a3"}, {AgentID: "a1"}, {AgentID: "system"}},
+	}
+	got := picks(t, leastSpokenSelector{}, state, 3)
+	if want := []string{"a2", "a3", "a2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("picks = %v, want %v", got, want)
+	}
+}
+
+func TestMentionSelector(t *testing.T) {
+	state := &SelectionState{
+		Agents:   selectorAgents(),
+		Messages: []Message{{AgentID: "a1", Content: `Tôi hỏi @"Nhà Phản Biện" và @a1`}},
+	}
+	a, _ := mentionSelector{}.Select(context.Background(), state)
+	if a.ID != "a3" || state.CurrentIndex != 0 {
+		t.Errorf("picked %s at index %d, want a3 with the rotation after it", a.ID, state.CurrentIndex)
+	}
+
+	// Mentioning only yourself falls back to rotation, skipping the last speaker
+	state.Messages = []Message{{AgentID: "a1", Content: "@a1 nói tiếp"}}
+	if a, _ := (mentionSelector{}).Select(context.Background(), state); a.ID != "a2" {
+		t.Errorf("picked %s, want a2", a.ID)
+	}
+}
+
+func TestWeightedRandomSelector(t *testing.T) {
+	sel, err := NewSelector(ModeWeightedRandom, SelectorOptions{Weights: map[string]float64{"a1": 0, "a3": 3}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := &SelectionState{Agents: selectorAgents()}
+	counts := map[string]int{}
+	for _, id := range picks(t, sel, state, 300) {
+		counts[id]++
+	}
+	if counts["a1"] != 0 {
+		t.Errorf("a1 with weight 0 spoke %d times", counts["a1"])
+	}
+	// a2 and a3 alternate, since neither may follow itself
+	if counts["a2"] < 140 || counts["a3"] < 140 {
+		t.Errorf("counts = %v, want a2 and a3 alternating", counts)
+	}
+
+	// With every weight at zero it falls back to rotation
+	sel, _ = NewSelector(ModeWeightedRandom, SelectorOptions{Weights: map[string]float64{"a1": 0, "a2": 0, "a3": 0}})
+	state = &SelectionState{Agents: selectorAgents()}
+	if got := picks(t, sel, state, 3); !reflect.DeepEqual(got, []string{"a1", "a2", "a3"}) {
+		t.Errorf("picks = %v, want panel order", got)
+	}
+}
+
+func TestNewSelectorRejectsUnknownMode(t *testing.T) {
+	if _, err := NewSelector("loudest", SelectorOptions{}); err == nil {
+		t.Error("NewSelector accepted an unknown mode")
+	}
+	for _, mode := range Modes() {
+		if _, err := NewSelector(mode, SelectorOptions{}); err != nil {
+			t.Errorf("NewSelector(%s): %v", mode, err)
+		}
+	}
+}
@@ -0,0 +1,480 @@
+package debate
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// DefaultSessionID is the session served by the legacy /api/debate routes
+const DefaultSessionID = "default"
+
+// Session is an isolated debate with its own manager and agent subset
+type Session struct {
+	ID        string
+	Name      string
+	AgentIDs  []string       // Subset of the agent pool, empty means all agents
+	Panel     []*agent.Agent // Temporary agents outside the pool, replaces AgentIDs when set
+	CreatedAt time.Time
+	Manager   *Manager
+	mu        sync.RWMutex // Guards Name, AgentIDs and Panel
+	panelMu   sync.Mutex   // Serializes panel changes, from reading the panel to updating the manager
+}
+
+// SessionInfo is the public view of a session (for API responses)
+type SessionInfo struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	AgentIDs     []string          `json:"agent_ids,omitempty"`
+	Agents       []agent.AgentInfo `json:"agents"`
+	Temporary    bool              `json:"temporary_panel,omitempty"`
+	Topic        string            `json:"topic"`
+	Mode         Mode              `json:"mode"`
+	IsRunning    bool              `json:"is_running"`
+	MessageCount int               `json:"message_count"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// Info returns public info about the session
+func (s *Session) Info() SessionInfo {
+	s.mu.RLock()
+	name, agentIDs, temporary := s.Name, s.AgentIDs, s.Panel != nil
+	s.mu.RUnlock()
+
+	return SessionInfo{
+		ID:           s.ID,
+		Name:         name,
+		AgentIDs:     agentIDs,
+		Agents:       s.Manager.GetAgents(),
+		Temporary:    temporary,
+		Topic:        s.Manager.GetTopic(),
+		Mode:         s.Manager.GetMode(),
+		IsRunning:    s.Manager.IsRunning(),
+		MessageCount: len(s.Manager.GetMessages()),
+		CreatedAt:    s.CreatedAt,
+	}
+}
+
+// SessionEventFunc receives manager events tagged with their session ID
+type SessionEventFunc func(sessionID string, event interface{})
+
+// Sessions is a registry of concurrent debate sessions sharing one agent pool
+type Sessions struct {
+	sessions map[string]*Session
+	order    []string // Creation order for listing
+	agents   []*agent.Agent
+	counter  int
+	eventFn  SessionEventFunc
+	recorder Recorder
+	mu       sync.RWMutex
+}
+
+// NewSessions creates a registry with the default session
+func NewSessions(agents []*agent.Agent) *Sessions {
+	s := &Sessions{
+		sessions: make(map[string]*Session),
+		agents:   agents,
+	}
+	s.add(&Session{
+		ID:        DefaultSessionID,
+		Name:      "Default",
+		CreatedAt: time.Now(),
+		Manager:   NewManager(agents),
+	})
+	return s
+}
+
+// SetEventFunc sets the callback for events from all current and future sessions
+func (s *Sessions) SetEventFunc(fn SessionEventFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.eventFn = fn
+	for _, sess := range s.sessions {
+		s.bindEvents(sess)
+	}
+}
+
+// SetRecorder sets the debate recorder for all current and future sessions
+func (s *Sessions) SetRecorder(r Recorder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recorder = r
+	for _, sess := range s.sessions {
+		sess.Manager.SetRecorder(r)
+	}
+}
+
+// bindEvents routes a session manager's events through the registry callback
+func (s *Sessions) bindEvents(sess *Session) {
+	if s.eventFn == nil {
+		return
+	}
+	fn, id := s.eventFn, sess.ID
+	sess.Manager.SetEventFunc(func(event interface{}) {
+		fn(id, event)
+	})
+}
+
+// add registers a session; callers must not hold s.mu
+func (s *Sessions) add(sess *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[sess.ID] = sess
+	s.order = append(s.order, sess.ID)
+	s.bindEvents(sess)
+	if s.recorder != nil {
+		sess.Manager.SetRecorder(s.recorder)
+	}
+}
+
+// Default returns the default session
+func (s *Sessions) Default() *Session {
+	sess, _ := s.Get(DefaultSessionID)
+	return sess
+}
+
+// Agent returns an agent from the shared pool by ID
+func (s *Sessions) Agent(id string) (*agent.Agent, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, a := range s.agents {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// Get returns a session by ID
+func (s *Sessions) Get(id string) (*Session, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sess, ok := s.sessions[id]
+	return sess, ok
+}
+
+// ByDebate returns the session whose manager has a saved debate open
+func (s *Sessions) ByDebate(debateID string) (*Session, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, sess := range s.sessions {
+		if debateID != "" && sess.Manager.GetDebateID() == debateID {
+			return sess, true
+		}
+	}
+	return nil, false
+}
+
+// List returns info for all sessions in creation order
+func (s *Sessions) List() []SessionInfo {
+	s.mu.RLock()
+	sessions := make([]*Session, 0, len(s.order))
+	for _, id := range s.order {
+		sessions = append(sessions, s.sessions[id])
+	}
+	s.mu.RUnlock()
+
+	result := make([]SessionInfo, len(sessions))
+	for i, sess := range sessions {
+		result[i] = sess.Info()
+	}
+	return result
+}
+
+// Create creates a new session using the given agent subset (empty for all agents)
+func (s *Sessions) Create(name string, agentIDs []string, mode Mode) (*Session, error) {
+	if mode == "" {
+		mode = ModeRoundRobin
+	}
+
+	s.mu.Lock()
+	agents, err := filterAgents(s.agents, agentIDs)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.counter++
+	id := fmt.Sprintf("sess_%d", s.counter)
+	s.mu.Unlock()
+
+	if name == "" {
+		name = id
+	}
+
+	manager := NewManager(agents)
+	if err := manager.SetMode(mode, SelectorOptions{}); err != nil {
+		return nil, err
+	}
+
+	sess := &Session{
+		ID:        id,
+		Name:      name,
+		AgentIDs:  agentIDs,
+		CreatedAt: time.Now(),
+		Manager:   manager,
+	}
+	s.add(sess)
+	return sess, nil
+}
+
+// Fork creates a session with the same agents, temporary panel, mode and
+// selector options as session id. An empty name is derived from the
+// source's, in its debate's prompt set.
+func (s *Sessions) Fork(id, name string) (*Session, error) {
+	source, ok := s.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	source.mu.RLock()
+	sourceName := source.Name
+	agentIDs := append([]string(nil), source.AgentIDs...)
+	panel := append([]*agent.Agent(nil), source.Panel...)
+	source.mu.RUnlock()
+
+	if name == "" {
+		name = renderPrompt(source.Manager.GetPromptSet(), "session_fork_name", PromptData{Content: sourceName})
+	}
+
+	forked, err := s.Create(name, agentIDs, ModeRoundRobin)
+	if err != nil {
+		return nil, err
+	}
+	if len(panel) > 0 {
+		s.SetPanel(forked.ID, panel)
+	}
+	// Custom selectors aren't registered modes and stay round-robin
+	if mode := source.Manager.GetMode(); IsValidMode(mode) {
+		forked.Manager.SetMode(mode, source.Manager.GetModeOptions())
+	}
+	return forked, nil
+}
+
+// Update renames a session and/or changes its agent subset, dropping any temporary panel
+func (s *Sessions) Update(id, name string, agentIDs []string) (*Session, error) {
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	agents, err := filterAgents(s.agents, agentIDs)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.mu.Unlock()
+
+	sess.panelMu.Lock()
+	defer sess.panelMu.Unlock()
+	sess.mu.Lock()
+	if name != "" {
+		sess.Name = name
+	}
+	sess.AgentIDs = agentIDs
+	sess.Panel = nil
+	sess.mu.Unlock()
+
+	sess.Manager.UpdateAgents(agents)
+	return sess, nil
+}
+
+// SetPanel gives a session temporary agents that are not in the pool.
+// They stay until the session's agent subset is updated again.
+func (s *Sessions) SetPanel(id string, agents []*agent.Agent) (*Session, error) {
+	sess, ok := s.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("panel is empty")
+	}
+
+	sess.panelMu.Lock()
+	defer sess.panelMu.Unlock()
+	sess.mu.Lock()
+	sess.AgentIDs = nil
+	sess.Panel = agents
+	sess.mu.Unlock()
+
+	sess.Manager.UpdateAgents(agents)
+	return sess, nil
+}
+
+// Join adds a pool agent to a session's panel, last in the rotation. The
+// debate may be running; the manager notes the join in the transcript.
+func (s *Sessions) Join(id, agentID string) (*Session, error) {
+	sess, ok := s.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	a, ok := s.Agent(agentID)
+	if !ok {
+		return nil, fmt.Errorf("agent not found: %s", agentID)
+	}
+
+	sess.panelMu.Lock()
+	defer sess.panelMu.Unlock()
+	panel := s.panel(sess)
+	if agentByID(panel, agentID) != nil {
+		return nil, fmt.Errorf("agent %s is already a participant", agentID)
+	}
+	s.setPanelAgents(sess, append(panel, a))
+	return sess, nil
+}
+
+// Leave removes an agent from a session's panel. The debate may be running;
+// the manager notes the leave in the transcript.
+func (s *Sessions) Leave(id, agentID string) (*Session, error) {
+	sess, ok := s.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+
+	sess.panelMu.Lock()
+	defer sess.panelMu.Unlock()
+	panel := s.panel(sess)
+	if agentByID(panel, agentID) == nil {
+		return nil, fmt.Errorf("agent %s is not a participant", agentID)
+	}
+	if len(panel) == 1 {
+		return nil, fmt.Errorf("the last participant cannot leave")
+	}
+	rest := make([]*agent.Agent, 0, len(panel)-1)
+	for _, a := range panel {
+		if a.ID != agentID {
+			rest = append(rest, a)
+		}
+	}
+	s.setPanelAgents(sess, rest)
+	return sess, nil
+}
+
+// panel returns a session's current agents: its temporary panel or its pool subset
+func (s *Sessions) panel(sess *Session) []*agent.Agent {
+	sess.mu.RLock()
+	temporary, agentIDs := sess.Panel, sess.AgentIDs
+	sess.mu.RUnlock()
+	if temporary != nil {
+		return append([]*agent.Agent(nil), temporary...)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(agentIDs) == 0 {
+		return append([]*agent.Agent(nil), s.agents...)
+	}
+	result := make([]*agent.Agent, 0, len(agentIDs))
+	for _, id := range agentIDs {
+		if a := agentByID(s.agents, id); a != nil {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// setPanelAgents stores a changed panel the way the session keeps it: a
+// temporary panel stays temporary, otherwise it becomes the pool subset.
+// Callers must hold sess.panelMu.
+func (s *Sessions) setPanelAgents(sess *Session, agents []*agent.Agent) {
+	sess.mu.Lock()
+	if sess.Panel != nil {
+		sess.Panel = agents
+	} else {
+		ids := make([]string, len(agents))
+		for i, a := range agents {
+			ids[i] = a.ID
+		}
+		sess.AgentIDs = ids
+	}
+	sess.mu.Unlock()
+
+	sess.Manager.UpdateAgents(agents)
+}
+
+// Delete stops and removes a session. The default session cannot be deleted.
+func (s *Sessions) Delete(id string) error {
+	if id == DefaultSessionID {
+		return fmt.Errorf("the default session cannot be deleted")
+	}
+
+	s.mu.Lock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("session not found: %s", id)
+	}
+	delete(s.sessions, id)
+	for i, sid := range s.order {
+		if sid == id {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	sess.Manager.Reset()
+	return nil
+}
+
+// UpdateAgents replaces the agent pool and refreshes every session's subset
+func (s *Sessions) UpdateAgents(agents []*agent.Agent) {
+	s.mu.Lock()
+	s.agents = agents
+	sessions := make([]*Session, 0, len(s.sessions))
+	for _, sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.mu.Unlock()
+
+	for _, sess := range sessions {
+		sess.panelMu.Lock()
+		sess.mu.RLock()
+		agentIDs, temporary := sess.AgentIDs, sess.Panel != nil
+		sess.mu.RUnlock()
+		if temporary {
+			sess.panelMu.Unlock()
+			continue
+		}
+
+		// Agents removed from the pool simply drop out of the subset
+		subset := make([]*agent.Agent, 0, len(agents))
+		if len(agentIDs) == 0 {
+			subset = agents
+		} else {
+			for _, id := range agentIDs {
+				for _, a := range agents {
+					if a.ID == id {
+						subset = append(subset, a)
+						break
+					}
+				}
+			}
+		}
+		sess.Manager.UpdateAgents(subset)
+		sess.panelMu.Unlock()
+	}
+}
+
+// filterAgents returns the agents matching ids in the given order (all agents if ids is empty)
+func filterAgents(pool []*agent.Agent, ids []string) ([]*agent.Agent, error) {
+	if len(ids) == 0 {
+		return pool, nil
+	}
+	result := make([]*agent.Agent, 0, len(ids))
+	for _, id := range ids {
+		var found *agent.Agent
+		for _, a := range pool {
+			if a.ID == id {
+				found = a
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("agent not found: %s", id)
+		}
+		result = append(result, found)
+	}
+	return result, nil
+}
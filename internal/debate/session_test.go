@@ -0,0 +1,140 @@
+package debate
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/user/talk/internal/agent"
+)
+
+func TestConcurrentJoinsKeepEveryAgent(t *testing.T) {
+	p := &scriptProvider{}
+	var pool []*agent.Agent
+	for i := 1; i <= 8; i++ {
+		pool = append(pool, testAgent(fmt.Sprintf("a%d", i), fmt.Sprintf("Agent %d", i), p))
+	}
+	sessions := NewSessions(pool)
+	sess, err := sessions.Create("Phiên", []string{"a1"}, ModeRoundRobin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for _, a := range pool[1:] {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if _, err := sessions.Join(sess.ID, id); err != nil {
+				t.Error(err)
+			}
+		}(a.ID)
+	}
+	wg.Wait()
+
+	if info := sess.Info(); len(info.AgentIDs) != len(pool) || len(info.Agents) != len(pool) {
+		t.Errorf("after concurrent joins: %d IDs, %d agents; want %d", len(info.AgentIDs), len(info.Agents), len(pool))
+	}
+
+	for _, a := range pool[1:] {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if _, err := sessions.Leave(sess.ID, id); err != nil {
+				t.Error(err)
+			}
+		}(a.ID)
+	}
+	wg.Wait()
+
+	if info := sess.Info(); len(info.AgentIDs) != 1 || len(info.Agents) != 1 {
+		t.Errorf("after concurrent leaves: %v, %d agents; want only a1", info.AgentIDs, len(info.Agents))
+	}
+}
+
+func TestJoinerTakesSmallerSide(t *testing.T) {
+	p := &scriptProvider{}
+	a1, a2, a3, a4 := testAgent("a1", "Alpha", p), testAgent("a2", "Beta", p), testAgent("a3", "Gamma", p), testAgent("a4", "Delta", p)
+	m := NewManager([]*agent.Agent{a1, a2, a3})
+	if err := m.SetFormat("oxford", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	m.UpdateAgents([]*agent.Agent{a1, a2, a3, a4})
+	sides := m.GetFormatStatus().Sides
+	if sides["a1"] != SidePro || sides["a2"] != SideCon || sides["a3"] != SidePro || sides["a4"] != SideCon {
+		t.Fatalf("sides = %v, want a4 on the smaller con side", sides)
+	}
+
+	// a1 leaves: pro is now the smaller side
+	a5 := testAgent("a5", "Epsilon", p)
+	m.UpdateAgents([]*agent.Agent{a2, a3, a4, a5})
+	if side := m.GetFormatStatus().Sides["a5"]; side != SidePro {
+		t.Errorf("a5 side = %q, want pro", side)
+	}
+	m.mu.RLock()
+	speakers := m.phaseSpeakersLocked(Phase{Order: []Side{SidePro}})
+	m.mu.RUnlock()
+	if len(speakers) != 2 {
+		t.Errorf("pro speakers = %d, want a3 and a5", len(speakers))
+	}
+}
+
+func TestSessionsRunIndependently(t *testing.T) {
+	p := &scriptProvider{}
+	pool := []*agent.Agent{testAgent("a1", "Alpha", p), testAgent("a2", "Beta", p), testAgent("a3", "Gamma", p)}
+	sessions := NewSessions(pool)
+	var mu sync.Mutex
+	seen := make(map[string]int)
+	sessions.SetEventFunc(func(id string, _ interface{}) {
+		mu.Lock()
+		seen[id]++
+		mu.Unlock()
+	})
+
+	if _, err := sessions.Create("x", []string{"a9"}, ModeRoundRobin); err == nil {
+		t.Error("created a session with an agent not in the pool")
+	}
+	if _, err := sessions.Create("x", nil, "loudest"); err == nil {
+		t.Error("created a session with an unknown mode")
+	}
+	sess, err := sessions.Create("", []string{"a3", "a1"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := sess.Info(); info.Name != sess.ID || len(info.Agents) != 2 || info.Agents[0].ID != "a3" {
+		t.Errorf("info = %+v, want the subset in the given order, named after its ID", info)
+	}
+
+	if err := sess.Manager.Start("Thuế carbon"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Manager.NextTurn(make(chan StreamMessage, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if sessions.Default().Manager.IsRunning() || len(sessions.Default().Manager.GetMessages()) != 0 {
+		t.Error("the default session picked up the new session's debate")
+	}
+	if msgs := sess.Manager.GetMessages(); len(msgs) != 1 || msgs[0].AgentID != "a3" {
+		t.Errorf("messages = %+v, want a3 opening", msgs)
+	}
+	if _, err := sess.Manager.Interject("Còn chi phí thì sao?", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if seen[sess.ID] != 1 || seen[DefaultSessionID] != 0 {
+		t.Errorf("events by session = %v", seen)
+	}
+
+	if err := sessions.Delete(DefaultSessionID); err == nil {
+		t.Error("deleted the default session")
+	}
+	if err := sessions.Delete(sess.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sessions.Get(sess.ID); ok || len(sessions.List()) != 1 {
+		t.Errorf("sessions = %+v, want only the default left", sessions.List())
+	}
+	if sess.Manager.IsRunning() {
+		t.Error("the deleted session's debate is still running")
+	}
+}
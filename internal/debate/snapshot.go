@@ -0,0 +1,228 @@
+package debate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// AgentSnapshot records who took part in a debate
+type AgentSnapshot struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Role         string `json:"role"`
+	Color        string `json:"color"`
+	ProviderType string `json:"provider_type"`
+	Model        string `json:"model"`
+}
+
+// Snapshot is a serializable copy of a debate's state
+type Snapshot struct {
+	ID           string          `json:"id"`
+	Topic        string          `json:"topic"`
+	Mode         Mode            `json:"mode"`
+	ModeOptions  SelectorOptions `json:"mode_options,omitempty"`
+	Agents       []AgentSnapshot `json:"agents"`
+	Messages     []Message       `json:"messages"`
+	MsgCounter   int             `json:"msg_counter"`
+	CurrentIndex int             `json:"current_index"`
+	Format       string          `json:"format,omitempty"`
+	Sides        map[string]Side `json:"sides,omitempty"`
+	PhaseIndex   int             `json:"phase_index,omitempty"`
+	PhaseTurn    int             `json:"phase_turn,omitempty"`
+	Verdict      *Verdict        `json:"verdict,omitempty"`
+	Ended        *Termination    `json:"ended,omitempty"`
+	Summaries    []Summary       `json:"summaries,omitempty"`
+	PromptSet    string          `json:"prompt_set,omitempty"`
+	Branch       string          `json:"branch,omitempty"`   // Active branch; Messages holds its transcript
+	Branches     []Branch        `json:"branches,omitempty"` // All branches, with transcripts for inactive ones
+	Agenda       []AgendaItem    `json:"agenda,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// Recorder persists debate snapshots
+type Recorder interface {
+	SaveDebate(snap Snapshot) error
+}
+
+// newDebateID returns a sortable, unique debate ID
+func newDebateID() string {
+	b := make([]byte, 3)
+	rand.Read(b)
+	return fmt.Sprintf("%s-%s", time.Now().Format("20060102-150405"), hex.EncodeToString(b))
+}
+
+// SetRecorder sets where debate snapshots are persisted after each change
+func (m *Manager) SetRecorder(r Recorder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recorder = r
+}
+
+// GetDebateID returns the ID of the current debate (empty before Start)
+func (m *Manager) GetDebateID() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.debateID
+}
+
+// Snapshot returns a copy of the current debate state
+func (m *Manager) Snapshot() Snapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.snapshotLocked()
+}
+
+// snapshotLocked builds a snapshot; callers must hold m.mu
+func (m *Manager) snapshotLocked() Snapshot {
+	agents := make([]AgentSnapshot, len(m.agents))
+	for i, a := range m.agents {
+		agents[i] = AgentSnapshot{
+			ID:           a.ID,
+			Name:         a.Name,
+			Role:         a.Role,
+			Color:        a.Color,
+			ProviderType: a.ProviderType,
+			Model:        a.Model,
+		}
+	}
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+
+	snap := Snapshot{
+		ID:           m.debateID,
+		Topic:        m.topic,
+		Mode:         m.mode,
+		ModeOptions:  m.selectorOpts,
+		Agents:       agents,
+		Messages:     messages,
+		MsgCounter:   m.msgCounter,
+		CurrentIndex: m.currentIndex,
+		CreatedAt:    m.createdAt,
+		UpdatedAt:    time.Now(),
+		Verdict:      m.verdict,
+		Ended:        m.ended,
+		Summaries:    append([]Summary(nil), m.summaries...),
+		PromptSet:    m.promptSet,
+		Agenda:       append([]AgendaItem(nil), m.agenda...),
+	}
+	if m.branches != nil {
+		snap.Branch = m.activeBranchLocked()
+		for _, b := range m.branches {
+			branch := *b
+			branch.Messages = append([]Message(nil), b.Messages...)
+			branch.Summaries = append([]Summary(nil), b.Summaries...)
+			branch.Agenda = append([]AgendaItem(nil), b.Agenda...)
+			snap.Branches = append(snap.Branches, branch)
+		}
+		sort.Slice(snap.Branches, func(i, j int) bool { return snap.Branches[i].CreatedAt.Before(snap.Branches[j].CreatedAt) })
+	}
+	if m.format != nil {
+		snap.Format = m.format.ID
+		snap.Sides = m.sides
+		snap.PhaseIndex = m.phaseIndex
+		snap.PhaseTurn = m.phaseTurn
+	}
+	return snap
+}
+
+// persist saves the current state if a recorder is set and a debate has started
+func (m *Manager) persist() {
+	m.mu.RLock()
+	recorder := m.recorder
+	if recorder == nil || m.debateID == "" {
+		m.mu.RUnlock()
+		return
+	}
+	snap := m.snapshotLocked()
+	m.mu.RUnlock()
+
+	if err := recorder.SaveDebate(snap); err != nil {
+		log.Printf("Warning: Failed to save debate %s: %v", snap.ID, err)
+	}
+}
+
+// Restore replaces the manager state with a saved debate so it can continue.
+// The current agents are kept; the snapshot's agent list is informational.
+func (m *Manager) Restore(snap Snapshot) error {
+	m.mu.Lock()
+	if m.isTurnInProgress {
+		m.mu.Unlock()
+		return ErrTurnInProgress
+	}
+	if m.cancel != nil {
+		m.cancel()
+	}
+
+	m.debateID = snap.ID
+	m.createdAt = snap.CreatedAt
+	m.topic = snap.Topic
+	if selector, err := NewSelector(snap.Mode, snap.ModeOptions); err == nil {
+		m.mode = snap.Mode
+		m.selector = selector
+		m.selectorOpts = snap.ModeOptions
+	}
+	m.messages = make([]Message, len(snap.Messages))
+	copy(m.messages, snap.Messages)
+	m.msgCounter = snap.MsgCounter
+	m.currentIndex = 0
+	if snap.CurrentIndex < len(m.agents) {
+		m.currentIndex = snap.CurrentIndex
+	}
+	m.verdict = snap.Verdict
+	m.summaries = append([]Summary(nil), snap.Summaries...)
+	m.branch, m.branches, m.branchCounter = "", nil, 0
+	m.addressee = ""
+	m.promptSet = ""
+	if _, ok := lookupPromptSet(snap.PromptSet); ok {
+		m.promptSet = snap.PromptSet
+	}
+	if len(snap.Branches) > 0 {
+		m.branches = make(map[string]*Branch, len(snap.Branches))
+		for i := range snap.Branches {
+			b := snap.Branches[i]
+			b.Messages = append([]Message(nil), b.Messages...)
+			b.Summaries = append([]Summary(nil), b.Summaries...)
+			b.Agenda = append([]AgendaItem(nil), b.Agenda...)
+			m.branches[b.ID] = &b
+		}
+		m.branch = snap.Branch
+		m.branchCounter = len(snap.Branches) - 1
+	}
+	m.summaryCounter = len(snap.Summaries)
+	m.agenda = append([]AgendaItem(nil), snap.Agenda...)
+	m.agendaCounter = agendaCounterFor(snap.Agenda)
+	// Resuming reopens a debate that a termination condition ended
+	m.ended = nil
+	m.format, m.sides = nil, nil
+	m.phaseIndex, m.phaseTurn = 0, 0
+	if f, ok := GetFormat(snap.Format); ok {
+		m.format = &f
+		m.sides = snap.Sides
+		if f.UsesSides {
+			m.sides = assignSides(m.agents, snap.Sides)
+		}
+		m.phaseIndex = snap.PhaseIndex
+		m.phaseTurn = snap.PhaseTurn
+	}
+	m.isRunning = true
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	er := m.eventRecorderLocked()
+	m.mu.Unlock()
+
+	m.persist()
+	// Debates saved before event logging, or forked, start their log here
+	if er != nil && lastEventSeq(er, snap.ID) == 0 {
+		m.logEvent(snap.ID, map[string]interface{}{
+			"type":      "debate_restored",
+			"debate_id": snap.ID,
+			"snapshot":  snap,
+		})
+	}
+	return nil
+}
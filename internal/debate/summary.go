@@ -0,0 +1,261 @@
+package debate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// Summary kinds
+const (
+	SummaryFinal   = "final"
+	SummaryRolling = "rolling"
+	SummaryAgenda  = "agenda" // Written when an agenda item closes
+)
+
+// Errors returned by Summarize
+var (
+	ErrNoSummarizer       = errors.New("no summarizer configured and no synthesizer agent in the panel")
+	ErrNothingToSummarize = errors.New("no messages to summarize")
+)
+
+// synthesizerIDs are the IDs the default and sample configs give the synthesizer agent
+var synthesizerIDs = []string{"synthesizer", "tong_hop"}
+
+// Summary is a summarizer's digest of the debate so far
+type Summary struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"` // SummaryFinal or SummaryRolling
+	AgentID   string    `json:"agent_id"`
+	AgentName string    `json:"agent_name"`
+	Content   string    `json:"content"`
+	UpTo      string    `json:"up_to"`                 // ID of the last message covered
+	Item      string    `json:"agenda_item,omitempty"` // Agenda item an agenda summary covers
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SetSummarizer sets the agent that writes summaries (nil to use the panel's synthesizer)
+func (m *Manager) SetSummarizer(a *agent.Agent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.summarizer = a
+}
+
+// GetSummarizer returns info about the agent that will write summaries, nil if none
+func (m *Manager) GetSummarizer() *agent.AgentInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a := m.summarizerLocked()
+	if a == nil {
+		return nil
+	}
+	info := a.Info()
+	return &info
+}
+
+// SetRollingSummaryEvery writes a rolling summary every n agent turns (0 = off)
+func (m *Manager) SetRollingSummaryEvery(n int) error {
+	if n < 0 {
+		return fmt.Errorf("rolling_every must not be negative")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.summaryEvery = n
+	return nil
+}
+
+// GetRollingSummaryEvery returns the rolling summary interval (0 = off)
+func (m *Manager) GetRollingSummaryEvery() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.summaryEvery
+}
+
+// GetSummaries returns the summaries written for the current debate
+func (m *Manager) GetSummaries() []Summary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]Summary, len(m.summaries))
+	copy(result, m.summaries)
+	return result
+}
+
+// summarizerLocked returns the configured summarizer, falling back to the
+// panel's synthesizer (by ID, then by the prompt set's synthesizer_role in
+// its role); callers must hold m.mu
+func (m *Manager) summarizerLocked() *agent.Agent {
+	if m.summarizer != nil {
+		return m.summarizer
+	}
+	for _, id := range synthesizerIDs {
+		if a := m.findAgentLocked(id); a != nil {
+			return a
+		}
+	}
+	keyword := strings.ToLower(renderPrompt(m.promptSet, "synthesizer_role", PromptData{}))
+	if keyword == "" {
+		return nil
+	}
+	for _, a := range m.agents {
+		if strings.Contains(strings.ToLower(a.Role), keyword) {
+			return a
+		}
+	}
+	return nil
+}
+
+// Summarize writes a summary of the given kind, streaming it to streamCh as
+// summary_start/summary_chunk/summary_end events, and stores it on the debate.
+// A final summary covers the whole transcript; a rolling one updates the
+// previous rolling summary with the messages since.
+func (m *Manager) Summarize(ctx context.Context, kind string, streamCh chan<- StreamMessage) (*Summary, error) {
+	if kind != SummaryFinal && kind != SummaryRolling {
+		return nil, fmt.Errorf("invalid summary kind: %s", kind)
+	}
+	return m.summarize(ctx, kind, nil, streamCh)
+}
+
+// summarize writes a summary of the given kind; item is the agenda item an
+// agenda summary covers
+func (m *Manager) summarize(ctx context.Context, kind string, item *AgendaItem, streamCh chan<- StreamMessage) (*Summary, error) {
+	m.mu.Lock()
+	summarizer := m.summarizerLocked()
+	if summarizer == nil {
+		m.mu.Unlock()
+		return nil, ErrNoSummarizer
+	}
+	setID := m.promptSet
+	topic := m.topic
+	agents := append([]*agent.Agent(nil), m.agents...)
+	messages := agentMessages(m.messages)
+	if item != nil {
+		topic = item.Topic
+		messages = agentMessages(messagesFrom(m.messages, item.FirstMessage))
+	}
+	var previous *Summary
+	for i := len(m.summaries) - 1; i >= 0; i-- {
+		if m.summaries[i].Kind == SummaryRolling {
+			previous = &m.summaries[i]
+			break
+		}
+	}
+	if len(messages) == 0 {
+		m.mu.Unlock()
+		return nil, ErrNothingToSummarize
+	}
+	m.summaryCounter++
+	summary := &Summary{
+		ID:        fmt.Sprintf("sum_%d", m.summaryCounter),
+		Kind:      kind,
+		AgentID:   summarizer.ID,
+		AgentName: summarizer.Name,
+		UpTo:      messages[len(messages)-1].ID,
+	}
+	var prompt string
+	switch kind {
+	case SummaryFinal:
+		prompt = finalSummaryPrompt(setID, topic, agents, messages)
+	case SummaryAgenda:
+		summary.Item = item.ID
+		prompt = agendaSummaryPrompt(setID, topic, messages)
+	default:
+		prompt = rollingSummaryPrompt(setID, topic, previous, messages)
+	}
+	m.mu.Unlock()
+
+	streamCh <- StreamMessage{
+		Type:      "summary_start",
+		AgentID:   summarizer.ID,
+		AgentName: summarizer.Name,
+		MessageID: summary.ID,
+		Color:     summarizer.Color,
+		Kind:      kind,
+	}
+
+	system := renderPrompt(setID, "summary_system", PromptData{})
+	content, err := streamAgent(ctx, summarizer, system, prompt, func(chunk string) {
+		streamCh <- StreamMessage{
+			Type:      "summary_chunk",
+			AgentID:   summarizer.ID,
+			Content:   chunk,
+			MessageID: summary.ID,
+			Kind:      kind,
+		}
+	})
+	if err != nil {
+		streamCh <- StreamMessage{
+			Type:      "summary_error",
+			AgentID:   summarizer.ID,
+			MessageID: summary.ID,
+			Error:     err.Error(),
+		}
+		return nil, err
+	}
+
+	summary.Content = cleanMessageContent(content)
+	summary.Timestamp = time.Now()
+
+	m.mu.Lock()
+	m.summaries = append(m.summaries, *summary)
+	m.mu.Unlock()
+	m.persist()
+
+	streamCh <- StreamMessage{
+		Type:      "summary_end",
+		AgentID:   summarizer.ID,
+		MessageID: summary.ID,
+		Kind:      kind,
+	}
+	return summary, nil
+}
+
+// maybeRollingSummary writes a rolling summary if one is due after a turn
+func (m *Manager) maybeRollingSummary(ctx context.Context, streamCh chan<- StreamMessage) {
+	m.mu.RLock()
+	every := m.summaryEvery
+	running := m.isRunning
+	turns := len(agentMessages(m.messages))
+	m.mu.RUnlock()
+
+	if every <= 0 || !running || turns == 0 || turns%every != 0 {
+		return
+	}
+	if _, err := m.Summarize(ctx, SummaryRolling, streamCh); err != nil {
+		log.Printf("Rolling summary failed: %v", err)
+	}
+}
+
+// finalSummaryPrompt asks for the structured end-of-debate summary
+func finalSummaryPrompt(setID, topic string, agents []*agent.Agent, messages []Message) string {
+	return renderPrompt(setID, "summary_final", PromptData{
+		Topic:      topic,
+		Agents:     promptAgents(agents, nil),
+		Transcript: recentTranscript(messages, 0),
+	})
+}
+
+// rollingSummaryPrompt asks to update the previous rolling summary with newer messages
+func rollingSummaryPrompt(setID, topic string, previous *Summary, messages []Message) string {
+	data := PromptData{Topic: topic}
+	if previous != nil {
+		data.Previous = previous.Content
+		for i, msg := range messages {
+			if msg.ID == previous.UpTo {
+				messages = messages[i+1:]
+				break
+			}
+		}
+	}
+	data.Transcript = recentTranscript(messages, 0)
+	return renderPrompt(setID, "summary_rolling", data)
+}
+
+// agendaSummaryPrompt asks for the minutes of one agenda item
+func agendaSummaryPrompt(setID, topic string, messages []Message) string {
+	return renderPrompt(setID, "summary_agenda", PromptData{Topic: topic, Transcript: recentTranscript(messages, 0)})
+}
@@ -0,0 +1,67 @@
+package debate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/user/talk/internal/agent"
+)
+
+func TestSummarizerFallsBackToSynthesizerRole(t *testing.T) {
+	p := &scriptProvider{}
+	vi := &agent.Agent{ID: "x1", Name: "Tổng kết", Role: "Người tổng hợp ý kiến", Provider: p}
+	en := &agent.Agent{ID: "x2", Name: "Wrap-up", Role: "Synthesizer of views", Provider: p}
+	m := NewManager([]*agent.Agent{testAgent("a1", "Alpha", p), vi, en})
+
+	if got := m.GetSummarizer(); got == nil || got.ID != "x1" {
+		t.Errorf("vi summarizer = %+v, want x1", got)
+	}
+	if err := m.SetPromptSet("en"); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.GetSummarizer(); got == nil || got.ID != "x2" {
+		t.Errorf("en summarizer = %+v, want x2", got)
+	}
+
+	byID := &agent.Agent{ID: "synthesizer", Name: "S", Role: "r", Provider: p}
+	m.UpdateAgents([]*agent.Agent{vi, byID})
+	if got := m.GetSummarizer(); got == nil || got.ID != "synthesizer" {
+		t.Errorf("summarizer = %+v, want the agent with the synthesizer ID", got)
+	}
+}
+
+func TestRollingAndFinalSummaries(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Thuế carbon")
+	ch := make(chan StreamMessage, 100)
+	if _, err := m.Summarize(context.Background(), SummaryFinal, ch); err != ErrNoSummarizer {
+		t.Errorf("summarizing without a summarizer = %v, want ErrNoSummarizer", err)
+	}
+	m.SetSummarizer(testAgent("s", "Thư ký", replyProvider{reply: "<thinking>nháp</thinking>Tóm tắt"}))
+	if _, err := m.Summarize(context.Background(), SummaryFinal, ch); err != ErrNothingToSummarize {
+		t.Errorf("summarizing an empty debate = %v, want ErrNothingToSummarize", err)
+	}
+	if _, err := m.Summarize(context.Background(), SummaryAgenda, ch); err == nil {
+		t.Error("accepted an agenda summary outside an agenda")
+	}
+	if err := m.SetRollingSummaryEvery(2); err != nil {
+		t.Fatal(err)
+	}
+
+	nextTurns(t, m, 5)
+	summaries := m.GetSummaries()
+	if len(summaries) != 2 || summaries[0].UpTo != "msg_2" || summaries[1].UpTo != "msg_4" || summaries[1].Kind != SummaryRolling {
+		t.Fatalf("summaries = %+v, want rolling summaries after turns 2 and 4", summaries)
+	}
+
+	final, err := m.Summarize(context.Background(), SummaryFinal, ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if final.ID != "sum_3" || final.UpTo != "msg_5" || final.Content != "Tóm tắt" || final.AgentID != "s" {
+		t.Errorf("final = %+v, want a cleaned summary up to msg_5", final)
+	}
+	if len(m.GetMessages()) != 5 {
+		t.Error("summaries were added to the transcript")
+	}
+}
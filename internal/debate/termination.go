@@ -0,0 +1,308 @@
+package debate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// Reasons reported when a termination condition ends the debate
+const (
+	TerminationConsensus  = "consensus"
+	TerminationStagnation = "stagnation"
+	TerminationEndMarker  = "end_marker"
+)
+
+// Defaults for unset thresholds
+const (
+	defaultConsensusThreshold  = 1.0
+	defaultStagnationThreshold = 0.5
+	consensusWindow            = 12
+)
+
+// TerminationOptions configures when the manager ends a debate on its own.
+// The zero value disables every condition.
+type TerminationOptions struct {
+	ConsensusEvery      int     `json:"consensus_every"`      // Ask every agent agree/disagree every N turns (0 = off)
+	ConsensusThreshold  float64 `json:"consensus_threshold"`  // Fraction of votes that must agree (default 1 = unanimous)
+	StagnationWindow    int     `json:"stagnation_window"`    // Consecutive similar messages that mean stagnation (0 = off)
+	StagnationThreshold float64 `json:"stagnation_threshold"` // Similarity 0-1 at which messages count as repetitive (default 0.5)
+	EndMarker           bool    `json:"end_marker"`           // Let agents end the debate by writing the prompt set's end_marker
+}
+
+// Validate checks the option ranges
+func (o TerminationOptions) Validate() error {
+	if o.ConsensusEvery < 0 || o.StagnationWindow < 0 {
+		return fmt.Errorf("consensus_every and stagnation_window must not be negative")
+	}
+	if o.StagnationWindow == 1 {
+		return fmt.Errorf("stagnation_window must be at least 2")
+	}
+	if o.ConsensusThreshold < 0 || o.ConsensusThreshold > 1 || o.StagnationThreshold < 0 || o.StagnationThreshold > 1 {
+		return fmt.Errorf("thresholds must be between 0 and 1")
+	}
+	return nil
+}
+
+// Termination records why and when a debate ended on its own
+type Termination struct {
+	Reason string    `json:"reason"`
+	Detail string    `json:"detail"`
+	At     time.Time `json:"at"`
+}
+
+// ConsensusVote is one agent's answer to a consensus check
+type ConsensusVote struct {
+	AgentID   string `json:"agent_id"`
+	AgentName string `json:"agent_name"`
+	Agree     bool   `json:"agree"`
+	Position  string `json:"position,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// SetTermination sets the automatic termination conditions
+func (m *Manager) SetTermination(opts TerminationOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.termOpts = opts
+	return nil
+}
+
+// GetTermination returns the configured termination conditions
+func (m *Manager) GetTermination() TerminationOptions {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.termOpts
+}
+
+// GetEnded returns why the debate ended on its own, nil if it didn't
+func (m *Manager) GetEnded() *Termination {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ended
+}
+
+// stripEndMarker removes a prompt set's end marker from content and reports
+// whether it was present
+func stripEndMarker(setID, content string) (string, bool) {
+	marker := renderPrompt(setID, "end_marker", PromptData{})
+	if marker == "" || !strings.Contains(content, marker) {
+		return content, false
+	}
+	return strings.TrimSpace(strings.ReplaceAll(content, marker, "")), true
+}
+
+// endMarkerLocked strips the end marker from a reply when agents may end the
+// debate with it; otherwise the reply is kept as written. Callers must hold
+// m.mu.
+func (m *Manager) endMarkerLocked(content string) (string, bool) {
+	if !m.termOpts.EndMarker {
+		return content, false
+	}
+	return stripEndMarker(m.promptSet, content)
+}
+
+// checkTermination evaluates the enabled conditions after a committed turn
+// and ends the debate if one fires. marker reports whether the turn's
+// message carried the end marker.
+func (m *Manager) checkTermination(ctx context.Context, speaker *agent.Agent, marker bool) {
+	m.mu.RLock()
+	opts := m.termOpts
+	running := m.isRunning
+	setID := m.promptSet
+	topic := m.topic
+	agents := append([]*agent.Agent(nil), m.agents...)
+	messages := append([]Message(nil), m.messages...)
+	m.mu.RUnlock()
+
+	if !running {
+		return
+	}
+
+	if marker && opts.EndMarker {
+		m.endDebate(TerminationEndMarker, renderPrompt(setID, "ended_by_marker", PromptData{Speaker: speaker.Name}))
+		return
+	}
+
+	spoken := agentMessages(messages)
+
+	if opts.StagnationWindow > 0 {
+		threshold := opts.StagnationThreshold
+		if threshold == 0 {
+			threshold = defaultStagnationThreshold
+		}
+		if sim, ok := stagnated(spoken, opts.StagnationWindow, threshold); ok {
+			m.endDebate(TerminationStagnation, renderPrompt(setID, "ended_stagnation", PromptData{Count: opts.StagnationWindow, Similarity: sim}))
+			return
+		}
+	}
+
+	if opts.ConsensusEvery > 0 && len(spoken) > 0 && len(spoken)%opts.ConsensusEvery == 0 {
+		threshold := opts.ConsensusThreshold
+		if threshold == 0 {
+			threshold = defaultConsensusThreshold
+		}
+		votes := consensusVotes(ctx, setID, topic, agents, messages)
+		agreed, valid := 0, 0
+		for _, v := range votes {
+			if v.Error != "" {
+				continue
+			}
+			valid++
+			if v.Agree {
+				agreed++
+			}
+		}
+		reached := valid >= 2 && float64(agreed)/float64(valid) >= threshold
+
+		m.emit(map[string]interface{}{
+			"type":    "consensus_check",
+			"votes":   votes,
+			"agreed":  agreed,
+			"valid":   valid,
+			"reached": reached,
+		})
+
+		if reached {
+			m.endDebate(TerminationConsensus, renderPrompt(setID, "ended_consensus", PromptData{Count: agreed, Total: valid}))
+		}
+	}
+}
+
+// endDebate stops the debate, records a system message and broadcasts the reason
+func (m *Manager) endDebate(reason, detail string) {
+	m.mu.Lock()
+	if !m.isRunning {
+		m.mu.Unlock()
+		return
+	}
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.isRunning = false
+	m.ended = &Termination{Reason: reason, Detail: detail, At: time.Now()}
+	m.msgCounter++
+	m.appendMessageLocked(Message{
+		ID:        fmt.Sprintf("msg_%d", m.msgCounter),
+		AgentID:   "system",
+		AgentName: renderPrompt(m.promptSet, "system_name", PromptData{}),
+		Content:   renderPrompt(m.promptSet, "debate_ended", PromptData{Reason: detail}),
+		Timestamp: time.Now(),
+		Color:     "#888888",
+	})
+	m.mu.Unlock()
+
+	log.Printf("Debate ended (%s): %s", reason, detail)
+	m.persist()
+	m.emit(map[string]interface{}{
+		"type":   "debate_ended",
+		"reason": reason,
+		"detail": detail,
+	})
+}
+
+// agentMessages drops system messages and interjections, leaving agent turns
+func agentMessages(messages []Message) []Message {
+	result := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		if msg.AgentID != "system" && msg.AgentID != InterjectionAgentID {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+// stagnated reports whether each of the last window messages is at least
+// threshold-similar to the one before it, returning the lowest similarity seen
+func stagnated(messages []Message, window int, threshold float64) (float64, bool) {
+	if len(messages) < window {
+		return 0, false
+	}
+	recent := messages[len(messages)-window:]
+	lowest := 1.0
+	for i := 1; i < len(recent); i++ {
+		sim := similarity(recent[i-1].Content, recent[i].Content)
+		if sim < threshold {
+			return sim, false
+		}
+		if sim < lowest {
+			lowest = sim
+		}
+	}
+	return lowest, true
+}
+
+// similarity is the Jaccard similarity of two texts' word bigrams
+func similarity(a, b string) float64 {
+	sa, sb := bigrams(a), bigrams(b)
+	if len(sa) == 0 || len(sb) == 0 {
+		return 0
+	}
+	shared := 0
+	for g := range sa {
+		if sb[g] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(sa)+len(sb)-shared)
+}
+
+// bigrams returns the set of lowercase word pairs in text. Pairs rather than
+// single words, since Vietnamese words are mostly common syllables.
+func bigrams(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	set := make(map[string]bool, len(words))
+	for i := 1; i < len(words); i++ {
+		set[words[i-1]+" "+words[i]] = true
+	}
+	return set
+}
+
+// consensusVotes asks every agent, concurrently, whether the panel has reached agreement
+func consensusVotes(ctx context.Context, setID, topic string, agents []*agent.Agent, messages []Message) []ConsensusVote {
+	transcript := recentTranscript(messages, consensusWindow)
+	system := renderPrompt(setID, "consensus_system", PromptData{})
+
+	votes := make([]ConsensusVote, len(agents))
+	var wg sync.WaitGroup
+	for i, a := range agents {
+		votes[i] = ConsensusVote{AgentID: a.ID, AgentName: a.Name}
+		if a.IsHuman() {
+			votes[i].Error = "human participants are not polled"
+			continue
+		}
+		wg.Add(1)
+		go func(i int, a *agent.Agent) {
+			defer wg.Done()
+			prompt := renderPrompt(setID, "consensus", PromptData{
+				Topic:      topic,
+				Agent:      PromptAgent{ID: a.ID, Name: a.Name, Role: a.Role},
+				Transcript: transcript,
+			})
+
+			resp, err := askAgent(ctx, a, system, prompt)
+			if err == nil {
+				err = extractJSON(resp, &votes[i])
+			}
+			if err != nil {
+				votes[i].Agree = false
+				votes[i].Error = err.Error()
+			}
+			votes[i].AgentID = a.ID
+			votes[i].AgentName = a.Name
+		}(i, a)
+	}
+	wg.Wait()
+	return votes
+}
@@ -0,0 +1,47 @@
+package debate
+
+import "testing"
+
+func TestEndMarkerKeptWhenOff(t *testing.T) {
+	m, _ := newTestManager(t)
+	for _, a := range m.agents {
+		a.Provider = replyProvider{reply: "Trích dẫn thẻ [KẾT THÚC] trong câu."}
+	}
+	start(t, m, "Định dạng transcript")
+	nextTurns(t, m, 1)
+	play(t, m, func(ch chan<- StreamMessage) error {
+		_, err := m.RegenerateMessage("msg_1", "", ch)
+		return err
+	})
+	play(t, m, func(ch chan<- StreamMessage) error {
+		return m.ParallelRound(false, ch)
+	})
+
+	if ended := m.GetEnded(); ended != nil {
+		t.Fatalf("ended = %+v with the end marker off", ended)
+	}
+	for _, msg := range m.GetMessages() {
+		if msg.Content != "Trích dẫn thẻ [KẾT THÚC] trong câu." {
+			t.Errorf("%s = %q, want the reply as written", msg.ID, msg.Content)
+		}
+	}
+}
+
+func TestEndMarkerStrippedWhenOn(t *testing.T) {
+	m, _ := newTestManager(t)
+	for _, a := range m.agents {
+		a.Provider = replyProvider{reply: "Đồng ý. [KẾT THÚC]"}
+	}
+	if err := m.SetTermination(TerminationOptions{EndMarker: true}); err != nil {
+		t.Fatal(err)
+	}
+	start(t, m, "Thuế carbon")
+	nextTurns(t, m, 1)
+
+	if ended := m.GetEnded(); ended == nil || ended.Reason != TerminationEndMarker {
+		t.Fatalf("ended = %+v, want the end marker to stop the debate", ended)
+	}
+	if msgs := m.GetMessages(); msgs[0].Content != "Đồng ý." {
+		t.Errorf("first message = %q, want the marker stripped", msgs[0].Content)
+	}
+}
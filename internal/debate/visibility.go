@@ -0,0 +1,82 @@
+package debate
+
+import (
+	"strconv"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// summaryContextID marks summaries standing in for the transcript of an
+// agent that only sees summaries
+const summaryContextID = "summary"
+
+// visibleHistoryLocked applies the speaker's visibility policy to history:
+// blind agents see nothing, summary-only agents see the summaries covering
+// it, and the others see their filtered, possibly truncated share of it.
+// Private messages the speaker is not part of are always left out.
+// Callers must hold m.mu.
+func (m *Manager) visibleHistoryLocked(speaker *agent.Agent, history []Message) []Message {
+	history = visibleTo(history, speaker.ID)
+	vis := speaker.Visibility
+	switch vis.Mode {
+	case agent.VisibilityBlind:
+		return nil
+	case agent.VisibilitySummaries:
+		return m.summaryHistoryLocked(history)
+	}
+	if len(vis.From) == 0 && vis.LastN == 0 {
+		return history
+	}
+
+	visible := make([]Message, 0, len(history))
+	for _, msg := range history {
+		if msg.AgentID == "system" {
+			continue
+		}
+		if len(vis.From) > 0 && msg.AgentID != speaker.ID && msg.AgentID != InterjectionAgentID &&
+			!containsString(vis.From, msg.AgentID) {
+			continue
+		}
+		visible = append(visible, msg)
+	}
+	if vis.LastN > 0 && len(visible) > vis.LastN {
+		visible = visible[len(visible)-vis.LastN:]
+	}
+	return visible
+}
+
+// summaryHistoryLocked returns the summaries of the messages in history as
+// pseudo-messages, oldest first; callers must hold m.mu
+func (m *Manager) summaryHistoryLocked(history []Message) []Message {
+	covered := make(map[string]bool, len(history))
+	for _, msg := range history {
+		covered[msg.ID] = true
+	}
+	var result []Message
+	for _, s := range m.summaries {
+		if !covered[s.UpTo] {
+			continue
+		}
+		result = append(result, Message{
+			ID:        s.ID,
+			AgentID:   summaryContextID,
+			AgentName: s.AgentName,
+			Content:   s.Content,
+			Timestamp: s.Timestamp,
+		})
+	}
+	return result
+}
+
+// anonymousNames labels the other speakers of an anonymized context
+// "1", "2", ... in order of first appearance
+type anonymousNames map[string]string
+
+func (n anonymousNames) label(agentID string) string {
+	if label, ok := n[agentID]; ok {
+		return label
+	}
+	label := strconv.Itoa(len(n) + 1)
+	n[agentID] = label
+	return label
+}
@@ -0,0 +1,106 @@
+package debate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// testHistory is a transcript with one message of each kind
+func testHistory() []Message {
+	return []Message{
+		{ID: "msg_1", AgentID: "a1", AgentName: "Alpha", Content: "ý của Alpha"},
+		{ID: "msg_2", AgentID: "a2", AgentName: "Beta", Content: "ý của Beta"},
+		{ID: "msg_3", AgentID: InterjectionAgentID, AgentName: "Người điều phối", Content: "câu hỏi của người điều phối"},
+		{ID: "msg_4", AgentID: "system", AgentName: "Hệ thống", Content: "--- Gamma tham gia ---"},
+		{ID: "msg_5", AgentID: "a3", AgentName: "Gamma", Content: "ý của Gamma"},
+	}
+}
+
+func messageIDs(messages []Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		ids = append(ids, msg.ID)
+	}
+	return ids
+}
+
+func TestVisibleHistory(t *testing.T) {
+	tests := []struct {
+		name string
+		vis  agent.Visibility
+		want []string
+	}{
+		{"full", agent.Visibility{}, []string{"msg_1", "msg_2", "msg_3", "msg_4", "msg_5"}},
+		{"blind", agent.Visibility{Mode: agent.VisibilityBlind}, []string{}},
+		{"last n", agent.Visibility{LastN: 2}, []string{"msg_3", "msg_5"}},
+		{"last n above length", agent.Visibility{LastN: 10}, []string{"msg_1", "msg_2", "msg_3", "msg_5"}},
+		// Own messages and the moderator's are kept whatever From says
+		{"from", agent.Visibility{From: []string{"a3"}}, []string{"msg_1", "msg_3", "msg_5"}},
+		{"from and last n", agent.Visibility{From: []string{"a2"}, LastN: 2}, []string{"msg_2", "msg_3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t)
+			speaker := m.agents[0]
+			speaker.Visibility = tt.vis
+
+			m.mu.RLock()
+			got := messageIDs(m.visibleHistoryLocked(speaker, testHistory()))
+			m.mu.RUnlock()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("visible = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibleHistorySummaries(t *testing.T) {
+	m, _ := newTestManager(t)
+	speaker := m.agents[0]
+	speaker.Visibility = agent.Visibility{Mode: agent.VisibilitySummaries}
+	m.summaries = []Summary{
+		{ID: "summary_1", UpTo: "msg_2", AgentName: "Thư ký", Content: "tóm tắt đến msg_2"},
+		{ID: "summary_2", UpTo: "msg_9", AgentName: "Thư ký", Content: "tóm tắt một nhánh khác"},
+	}
+
+	m.mu.RLock()
+	got := m.visibleHistoryLocked(speaker, testHistory())
+	m.mu.RUnlock()
+	if len(got) != 1 || got[0].ID != "summary_1" || got[0].AgentID != summaryContextID {
+		t.Fatalf("visible = %+v, want only summary_1 as a summary message", got)
+	}
+}
+
+func TestAnonymousContextHidesNames(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Thuế carbon")
+	m.messages = testHistory()
+	speaker := m.agents[0]
+	speaker.Visibility = agent.Visibility{Anonymous: true}
+
+	m.mu.RLock()
+	messages := m.buildContextLocked(speaker, m.messages)
+	m.mu.RUnlock()
+
+	var context strings.Builder
+	for _, msg := range messages {
+		context.WriteString(msg.Content + "\n")
+	}
+	for _, name := range []string{"Beta", "Gamma"} {
+		if strings.Contains(context.String(), "**"+name+"**") || strings.Contains(context.String(), "- "+name+":") {
+			t.Errorf("anonymous context names %s:\n%s", name, context.String())
+		}
+	}
+	for _, label := range []string{"Thành viên 1", "Thành viên 2"} {
+		if !strings.Contains(context.String(), label) {
+			t.Errorf("anonymous context lacks %q:\n%s", label, context.String())
+		}
+	}
+	if !strings.Contains(context.String(), "ý của Gamma") {
+		t.Errorf("anonymous context dropped a message:\n%s", context.String())
+	}
+}
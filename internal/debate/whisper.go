@@ -0,0 +1,101 @@
+package debate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/talk/internal/provider"
+)
+
+// canSee reports whether an agent may see a message: public messages are seen
+// by everyone, private ones only by their author and audience
+func canSee(msg Message, agentID string) bool {
+	return len(msg.Audience) == 0 || msg.AgentID == agentID || containsString(msg.Audience, agentID)
+}
+
+// visibleTo drops the private messages agentID is not part of
+func visibleTo(messages []Message, agentID string) []Message {
+	result := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		if canSee(msg, agentID) {
+			result = append(result, msg)
+		}
+	}
+	return result
+}
+
+// publicMessages drops every private message, for helper roles (judge,
+// summarizer, moderator) whose output all agents read
+func publicMessages(messages []Message) []Message {
+	return visibleTo(messages, "")
+}
+
+// audienceLocked checks that every ID in audience is on the panel and returns
+// it deduplicated, with the include IDs added first; nil stays nil (public).
+// Callers must hold m.mu.
+func (m *Manager) audienceLocked(audience []string, include ...string) ([]string, error) {
+	if len(audience) == 0 {
+		return nil, nil
+	}
+	var result []string
+	for _, id := range append(append([]string(nil), include...), audience...) {
+		id = strings.TrimSpace(id)
+		if id == "" || containsString(result, id) {
+			continue
+		}
+		if m.findAgentLocked(id) == nil {
+			return nil, fmt.Errorf("agent not found: %s", id)
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
+// CheckAudience reports whether audience is valid for a private turn of
+// speakerID: panel agents only, and someone besides the speaker
+func (m *Manager) CheckAudience(speakerID string, audience []string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, err := m.whisperAudienceLocked(speakerID, audience)
+	return err
+}
+
+// whisperAudienceLocked validates a private turn's audience and adds the
+// speaker to it; callers must hold m.mu
+func (m *Manager) whisperAudienceLocked(speakerID string, audience []string) ([]string, error) {
+	audience, err := m.audienceLocked(audience, speakerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(audience) == 1 {
+		return nil, fmt.Errorf("a private turn needs another agent in its audience")
+	}
+	return audience, nil
+}
+
+// audienceNamesLocked lists an audience by agent name, leaving out exclude;
+// callers must hold m.mu
+func (m *Manager) audienceNamesLocked(audience []string, exclude string) string {
+	names := make([]string, 0, len(audience))
+	for _, id := range audience {
+		if id != exclude {
+			names = append(names, m.agentNameLocked(id))
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// whisperLocked makes tc a private turn for audience, telling the agent who
+// will read it; callers must hold m.mu
+func (m *Manager) whisperLocked(tc *TurnContext, audience []string) {
+	if len(audience) == 0 {
+		return
+	}
+	tc.Audience = audience
+	data := m.promptDataLocked(m.promptSet, tc.Agent, tc.History)
+	data.Audience = m.audienceNamesLocked(audience, tc.Agent.ID)
+	tc.Messages = append(tc.Messages, provider.Message{
+		Role:    "user",
+		Content: renderPrompt(m.promptSet, "whisper", data),
+	})
+}
@@ -0,0 +1,149 @@
+package debate
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/talk/internal/agent"
+)
+
+// privateHistory has a whisper from a1 to a2 and a moderator hint to a3
+func privateHistory() []Message {
+	return []Message{
+		{ID: "msg_1", AgentID: "a1", AgentName: "Alpha", Content: "ý công khai"},
+		{ID: "msg_2", AgentID: "a1", AgentName: "Alpha", Content: "bí mật của đội", Audience: []string{"a1", "a2"}},
+		{ID: "msg_3", AgentID: InterjectionAgentID, AgentName: "Người điều phối", Content: "gợi ý riêng", Audience: []string{"a3"}},
+		{ID: "msg_4", AgentID: "a2", AgentName: "Beta", Content: "trả lời công khai"},
+	}
+}
+
+func TestCanSee(t *testing.T) {
+	whisper := Message{AgentID: "a1", Audience: []string{"a2"}}
+	tests := []struct {
+		name    string
+		msg     Message
+		agentID string
+		want    bool
+	}{
+		{"public", Message{AgentID: "a1"}, "a3", true},
+		{"author", whisper, "a1", true},
+		{"audience", whisper, "a2", true},
+		{"outsider", whisper, "a3", false},
+		{"helper role", whisper, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canSee(tt.msg, tt.agentID); got != tt.want {
+				t.Errorf("canSee = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibleHistoryPrivate(t *testing.T) {
+	tests := []struct {
+		name    string
+		speaker int // Index into the panel a1, a2, a3
+		vis     agent.Visibility
+		want    []string
+	}{
+		{"author", 0, agent.Visibility{}, []string{"msg_1", "msg_2", "msg_4"}},
+		{"whisper audience", 1, agent.Visibility{}, []string{"msg_1", "msg_2", "msg_4"}},
+		{"hint audience", 2, agent.Visibility{}, []string{"msg_1", "msg_3", "msg_4"}},
+		// Hidden messages don't count toward last_n, and from never reveals them
+		{"outsider last n", 2, agent.Visibility{LastN: 2}, []string{"msg_3", "msg_4"}},
+		{"outsider from author", 2, agent.Visibility{From: []string{"a1"}}, []string{"msg_1", "msg_3"}},
+		{"author anonymous", 0, agent.Visibility{Anonymous: true}, []string{"msg_1", "msg_2", "msg_4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t)
+			speaker := m.agents[tt.speaker]
+			speaker.Visibility = tt.vis
+
+			m.mu.RLock()
+			got := messageIDs(m.visibleHistoryLocked(speaker, privateHistory()))
+			m.mu.RUnlock()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("visible = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrivateMessagesStayOutOfContext(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Thuế carbon")
+	m.messages = privateHistory()
+
+	contextOf := func(id string) string {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		var sb strings.Builder
+		for _, msg := range m.buildContextLocked(m.findAgentLocked(id), m.messages) {
+			sb.WriteString(msg.Content + "\n")
+		}
+		return sb.String()
+	}
+
+	tests := []struct {
+		agentID      string
+		sees, hidden []string
+	}{
+		{"a1", []string{"bí mật của đội"}, []string{"gợi ý riêng"}},
+		{"a2", []string{"bí mật của đội"}, []string{"gợi ý riêng"}},
+		{"a3", []string{"gợi ý riêng"}, []string{"bí mật của đội"}},
+	}
+	for _, tt := range tests {
+		context := contextOf(tt.agentID)
+		for _, s := range tt.sees {
+			if !strings.Contains(context, s) {
+				t.Errorf("%s's context lacks %q:\n%s", tt.agentID, s, context)
+			}
+		}
+		for _, s := range tt.hidden {
+			if strings.Contains(context, s) {
+				t.Errorf("%s's context leaks %q:\n%s", tt.agentID, s, context)
+			}
+		}
+	}
+
+	transcript := recentTranscript(m.messages, 0)
+	for _, s := range []string{"bí mật của đội", "gợi ý riêng"} {
+		if strings.Contains(transcript, s) {
+			t.Errorf("helper transcript leaks %q:\n%s", s, transcript)
+		}
+	}
+	if !strings.Contains(transcript, "trả lời công khai") {
+		t.Errorf("helper transcript dropped a public message:\n%s", transcript)
+	}
+}
+
+func TestPrivateTurnAudience(t *testing.T) {
+	m, _ := newTestManager(t)
+	start(t, m, "Thuế carbon")
+
+	if err := m.CheckAudience("a1", []string{"a1"}); err == nil {
+		t.Error("a private turn with only the speaker was accepted")
+	}
+	if err := m.CheckAudience("a1", []string{"zz"}); err == nil {
+		t.Error("a private turn for an unknown agent was accepted")
+	}
+
+	play(t, m, func(ch chan<- StreamMessage) error {
+		return m.TurnByAgent("a1", []string{"a2"}, ch)
+	})
+	msgs := m.GetMessages()
+	if len(msgs) != 1 || !reflect.DeepEqual(msgs[0].Audience, []string{"a1", "a2"}) {
+		t.Fatalf("messages = %+v, want one whisper to a1 and a2", msgs)
+	}
+
+	hint, err := m.Interject("gợi ý", "a3", []string{"a2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(hint.Audience, []string{"a3", "a2"}) {
+		t.Errorf("hint audience = %v, want the addressee added", hint.Audience)
+	}
+}
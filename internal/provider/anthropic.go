@@ -57,14 +57,25 @@ type anthropicStreamEvent struct {
 		Type string `json:"type"`
 	} `json:"content_block"`
 	Delta struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
+		Type       string `json:"type"`
+		Text       string `json:"text"`
+		StopReason string `json:"stop_reason"` // On message_delta
 	} `json:"delta"`
+	Message struct {
+		Usage anthropicUsage `json:"usage"`
+	} `json:"message"` // On message_start
+	Usage anthropicUsage `json:"usage"` // On message_delta
 }
 
-func (a *Anthropic) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
-	ch := make(chan StreamChunk, 100)
+// anthropicUsage counts input tokens on message_start and output tokens on
+// message_delta
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
 
+// request builds the API request body for messages
+func (a *Anthropic) request(messages []Message, opts Options) anthropicRequest {
 	// Extract system message and convert others
 	var systemMsg string
 	var anthMessages []anthropicMessage
@@ -109,13 +120,31 @@ func (a *Anthropic) Chat(ctx context.Context, messages []Message, opts Options)
 		}
 	}
 
-	reqBody := anthropicRequest{
+	return anthropicRequest{
 		Model:     model,
 		MaxTokens: maxTokens,
 		System:    systemMsg,
 		Messages:  anthMessages,
 		Stream:    true,
 	}
+}
+
+// Preview returns the request Chat would send, without the API key
+func (a *Anthropic) Preview(messages []Message, opts Options) RequestPreview {
+	body := a.request(messages, opts)
+	return RequestPreview{
+		Provider:  a.Name(),
+		Endpoint:  a.baseURL + "/v1/messages",
+		Model:     body.Model,
+		MaxTokens: body.MaxTokens,
+		Body:      body,
+	}
+}
+
+func (a *Anthropic) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk, 100)
+
+	reqBody := a.request(messages, opts)
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
@@ -152,12 +181,14 @@ func (a *Anthropic) Chat(ctx context.Context, messages []Message, opts Options)
 		// Track if we're inside a thinking block (should not output)
 		isThinkingBlock := false
 		currentBlockIndex := -1
+		var stopReason string
+		var usage Usage
 
 		for {
 			line, err := reader.ReadString('\n')
 			if err != nil {
 				if err == io.EOF {
-					ch <- StreamChunk{Done: true}
+					ch <- StreamChunk{Done: true, FinishReason: stopReason, Usage: &usage}
 					return
 				}
 				ch <- StreamChunk{Error: fmt.Errorf("read error: %w", err)}
@@ -198,8 +229,13 @@ func (a *Anthropic) Chat(ctx context.Context, messages []Message, opts Options)
 				if !isThinkingBlock && event.Delta.Text != "" {
 					ch <- StreamChunk{Content: event.Delta.Text}
 				}
+			case "message_start":
+				usage.InputTokens = event.Message.Usage.InputTokens
+			case "message_delta":
+				stopReason = event.Delta.StopReason
+				usage.OutputTokens = event.Usage.OutputTokens
 			case "message_stop":
-				ch <- StreamChunk{Done: true}
+				ch <- StreamChunk{Done: true, FinishReason: stopReason, Usage: &usage}
 				return
 			}
 		}
@@ -66,11 +66,14 @@ type geminiStreamResponse struct {
 		} `json:"content"`
 		FinishReason string `json:"finishReason"`
 	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
 }
 
-func (g *Gemini) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
-	ch := make(chan StreamChunk, 100)
-
+// request builds the API request body for messages and the model it targets
+func (g *Gemini) request(messages []Message, opts Options) (geminiRequest, string) {
 	// Convert messages
 	var contents []geminiContent
 	var systemInstruction *geminiContent
@@ -122,6 +125,25 @@ func (g *Gemini) Chat(ctx context.Context, messages []Message, opts Options) (<-
 			Temperature:     opts.Temperature,
 		},
 	}
+	return reqBody, model
+}
+
+// Preview returns the request Chat would send, without the API key
+func (g *Gemini) Preview(messages []Message, opts Options) RequestPreview {
+	body, model := g.request(messages, opts)
+	return RequestPreview{
+		Provider:  g.Name(),
+		Endpoint:  fmt.Sprintf("%s/v1beta/models/%s:streamGenerateContent?alt=sse", g.baseURL, model),
+		Model:     model,
+		MaxTokens: body.GenerationConfig.MaxOutputTokens,
+		Body:      body,
+	}
+}
+
+func (g *Gemini) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk, 100)
+
+	reqBody, model := g.request(messages, opts)
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
@@ -155,11 +177,13 @@ func (g *Gemini) Chat(ctx context.Context, messages []Message, opts Options) (<-
 		}
 
 		reader := bufio.NewReader(resp.Body)
+		var finishReason string
+		var usage *Usage
 		for {
 			line, err := reader.ReadString('\n')
 			if err != nil {
 				if err == io.EOF {
-					ch <- StreamChunk{Done: true}
+					ch <- StreamChunk{Done: true, FinishReason: finishReason, Usage: usage}
 					return
 				}
 				ch <- StreamChunk{Error: fmt.Errorf("read error: %w", err)}
@@ -182,6 +206,10 @@ func (g *Gemini) Chat(ctx context.Context, messages []Message, opts Options) (<-
 				continue
 			}
 
+			if u := streamResp.UsageMetadata; u != nil {
+				usage = &Usage{InputTokens: u.PromptTokenCount, OutputTokens: u.CandidatesTokenCount}
+			}
+
 			if len(streamResp.Candidates) > 0 {
 				candidate := streamResp.Candidates[0]
 				for _, part := range candidate.Content.Parts {
@@ -189,8 +217,11 @@ func (g *Gemini) Chat(ctx context.Context, messages []Message, opts Options) (<-
 						ch <- StreamChunk{Content: part.Text}
 					}
 				}
-				if candidate.FinishReason == "STOP" {
-					ch <- StreamChunk{Done: true}
+				if candidate.FinishReason != "" {
+					finishReason = candidate.FinishReason
+				}
+				if finishReason == "STOP" {
+					ch <- StreamChunk{Done: true, FinishReason: finishReason, Usage: usage}
 					return
 				}
 			}
@@ -2,13 +2,12 @@ package provider
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"strings"
+	"sync/atomic"
 )
 
 // Grok provider implementation (xAI)
@@ -17,6 +16,8 @@ type Grok struct {
 	apiKey  string
 	model   string
 	baseURL string
+	// noStreamOptions is set once the server rejected stream_options
+	noStreamOptions atomic.Bool
 }
 
 // NewGrok creates a new Grok provider
@@ -44,6 +45,8 @@ type grokRequest struct {
 	Stream      bool          `json:"stream"`
 	MaxTokens   int           `json:"max_tokens,omitempty"`
 	Temperature float64       `json:"temperature,omitempty"`
+	// StreamOptions asks for a final usage chunk after the finish_reason one
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
 }
 
 type grokMessage struct {
@@ -58,11 +61,11 @@ type grokStreamResponse struct {
 		} `json:"delta"`
 		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
 }
 
-func (g *Grok) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
-	ch := make(chan StreamChunk, 100)
-
+// request builds the API request body for messages
+func (g *Grok) request(messages []Message, opts Options) grokRequest {
 	// Convert messages
 	grokMessages := make([]grokMessage, len(messages))
 	for i, m := range messages {
@@ -82,50 +85,55 @@ func (g *Grok) Chat(ctx context.Context, messages []Message, opts Options) (<-ch
 		maxTokens = 4096
 	}
 
-	reqBody := grokRequest{
-		Model:       model,
-		Messages:    grokMessages,
-		Stream:      true,
-		MaxTokens:   maxTokens,
-		Temperature: opts.Temperature,
-	}
-
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	return grokRequest{
+		Model:         model,
+		Messages:      grokMessages,
+		Stream:        true,
+		MaxTokens:     maxTokens,
+		Temperature:   opts.Temperature,
+		StreamOptions: streamOptions(&g.noStreamOptions),
 	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", g.baseURL+"/chat/completions", bytes.NewReader(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// Preview returns the request Chat would send, without the API key
+func (g *Grok) Preview(messages []Message, opts Options) RequestPreview {
+	body := g.request(messages, opts)
+	return RequestPreview{
+		Provider:  g.Name(),
+		Endpoint:  g.baseURL + "/chat/completions",
+		Model:     body.Model,
+		MaxTokens: body.MaxTokens,
+		Body:      body,
 	}
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+g.apiKey)
+func (g *Grok) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk, 100)
 
 	go func() {
 		defer close(ch)
 
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := postChatStream(ctx, g.baseURL+"/chat/completions", g.apiKey, &g.noStreamOptions, func(usage bool) interface{} {
+			body := g.request(messages, opts)
+			if !usage {
+				body.StreamOptions = nil
+			}
+			return body
+		})
 		if err != nil {
-			ch <- StreamChunk{Error: fmt.Errorf("request failed: %w", err)}
+			ch <- StreamChunk{Error: err}
 			return
 		}
 		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			ch <- StreamChunk{Error: fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))}
-			return
-		}
-
 		reader := bufio.NewReader(resp.Body)
+		var finishReason string
+		var usage *Usage
 		for {
 			line, err := reader.ReadString('\n')
 			if err != nil {
 				if err == io.EOF {
-					ch <- StreamChunk{Done: true}
+					ch <- StreamChunk{Done: true, FinishReason: finishReason, Usage: usage}
 					return
 				}
 				ch <- StreamChunk{Error: fmt.Errorf("read error: %w", err)}
@@ -143,7 +151,7 @@ func (g *Grok) Chat(ctx context.Context, messages []Message, opts Options) (<-ch
 
 			data := strings.TrimPrefix(line, "data: ")
 			if data == "[DONE]" {
-				ch <- StreamChunk{Done: true}
+				ch <- StreamChunk{Done: true, FinishReason: finishReason, Usage: usage}
 				return
 			}
 
@@ -152,14 +160,18 @@ func (g *Grok) Chat(ctx context.Context, messages []Message, opts Options) (<-ch
 				continue
 			}
 
+			if streamResp.Usage != nil {
+				usage = streamResp.Usage.usage()
+			}
+
 			if len(streamResp.Choices) > 0 {
 				content := streamResp.Choices[0].Delta.Content
 				if content != "" {
 					ch <- StreamChunk{Content: content}
 				}
-				if streamResp.Choices[0].FinishReason == "stop" {
-					ch <- StreamChunk{Done: true}
-					return
+				// Keep reading: the usage chunk follows the finish_reason one
+				if reason := streamResp.Choices[0].FinishReason; reason != "" {
+					finishReason = reason
 				}
 			}
 		}
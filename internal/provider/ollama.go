@@ -56,12 +56,14 @@ type ollamaStreamResponse struct {
 		Role    string `json:"role"`
 		Content string `json:"content"`
 	} `json:"message"`
-	Done bool `json:"done"`
+	Done            bool   `json:"done"`
+	DoneReason      string `json:"done_reason"`       // On the final line
+	PromptEvalCount int    `json:"prompt_eval_count"` // On the final line
+	EvalCount       int    `json:"eval_count"`        // On the final line
 }
 
-func (o *Ollama) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
-	ch := make(chan StreamChunk, 100)
-
+// request builds the API request body for messages
+func (o *Ollama) request(messages []Message, opts Options) ollamaRequest {
 	// Convert messages
 	ollamaMessages := make([]ollamaMessage, len(messages))
 	for i, m := range messages {
@@ -88,6 +90,28 @@ func (o *Ollama) Chat(ctx context.Context, messages []Message, opts Options) (<-
 			Temperature: opts.Temperature,
 		}
 	}
+	return reqBody
+}
+
+// Preview returns the request Chat would send, without the API key
+func (o *Ollama) Preview(messages []Message, opts Options) RequestPreview {
+	body := o.request(messages, opts)
+	preview := RequestPreview{
+		Provider: o.Name(),
+		Endpoint: o.baseURL + "/api/chat",
+		Model:    body.Model,
+		Body:     body,
+	}
+	if body.Options != nil {
+		preview.MaxTokens = body.Options.NumPredict
+	}
+	return preview
+}
+
+func (o *Ollama) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk, 100)
+
+	reqBody := o.request(messages, opts)
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
@@ -140,7 +164,11 @@ func (o *Ollama) Chat(ctx context.Context, messages []Message, opts Options) (<-
 			}
 
 			if streamResp.Done {
-				ch <- StreamChunk{Done: true}
+				ch <- StreamChunk{
+					Done:         true,
+					FinishReason: streamResp.DoneReason,
+					Usage:        &Usage{InputTokens: streamResp.PromptEvalCount, OutputTokens: streamResp.EvalCount},
+				}
 				return
 			}
 		}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 )
 
 // OpenAI provider implementation
@@ -16,6 +17,8 @@ type OpenAI struct {
 	apiKey  string
 	model   string
 	baseURL string
+	// noStreamOptions is set once the server rejected stream_options
+	noStreamOptions atomic.Bool
 }
 
 // NewOpenAI creates a new OpenAI provider
@@ -43,6 +46,60 @@ type openAIRequest struct {
 	Stream      bool            `json:"stream"`
 	MaxTokens   int             `json:"max_tokens,omitempty"`
 	Temperature float64         `json:"temperature,omitempty"`
+	// StreamOptions asks for a final usage chunk after the finish_reason one
+	StreamOptions *openAIStreamOptions `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// streamOptions asks for usage while streaming unless the server rejected it
+func streamOptions(unsupported *atomic.Bool) *openAIStreamOptions {
+	if unsupported.Load() {
+		return nil
+	}
+	return &openAIStreamOptions{IncludeUsage: true}
+}
+
+// postChatStream sends an OpenAI-compatible streaming chat request built by
+// build and returns the response once the server accepted it. Some
+// compatible servers answer 400 to stream_options: the request is then sent
+// once more without it, and if that works later requests skip it and go
+// without usage.
+func postChatStream(ctx context.Context, url, apiKey string, unsupported *atomic.Bool, build func(usage bool) interface{}) (*http.Response, error) {
+	usage := !unsupported.Load()
+	for {
+		jsonBody, err := json.Marshal(build(usage))
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			if !usage {
+				unsupported.Store(true)
+			}
+			return resp, nil
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusBadRequest && usage && !unsupported.Load() {
+			usage = false
+			continue
+		}
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
 }
 
 type openAIMessage struct {
@@ -50,6 +107,17 @@ type openAIMessage struct {
 	Content string `json:"content"`
 }
 
+// openAIUsage is the usage block of OpenAI-compatible APIs, sent on the last
+// chunk by servers that report it while streaming
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+func (u *openAIUsage) usage() *Usage {
+	return &Usage{InputTokens: u.PromptTokens, OutputTokens: u.CompletionTokens}
+}
+
 type openAIStreamResponse struct {
 	Choices []struct {
 		Delta struct {
@@ -57,11 +125,11 @@ type openAIStreamResponse struct {
 		} `json:"delta"`
 		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
 }
 
-func (o *OpenAI) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
-	ch := make(chan StreamChunk, 100)
-
+// request builds the API request body for messages
+func (o *OpenAI) request(messages []Message, opts Options) openAIRequest {
 	// Convert messages
 	oaiMessages := make([]openAIMessage, len(messages))
 	for i, m := range messages {
@@ -90,50 +158,55 @@ func (o *OpenAI) Chat(ctx context.Context, messages []Message, opts Options) (<-
 		}
 	}
 
-	reqBody := openAIRequest{
-		Model:       model,
-		Messages:    oaiMessages,
-		Stream:      true,
-		MaxTokens:   maxTokens,
-		Temperature: opts.Temperature,
-	}
-
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	return openAIRequest{
+		Model:         model,
+		Messages:      oaiMessages,
+		Stream:        true,
+		MaxTokens:     maxTokens,
+		Temperature:   opts.Temperature,
+		StreamOptions: streamOptions(&o.noStreamOptions),
 	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", o.baseURL+"/chat/completions", bytes.NewReader(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// Preview returns the request Chat would send, without the API key
+func (o *OpenAI) Preview(messages []Message, opts Options) RequestPreview {
+	body := o.request(messages, opts)
+	return RequestPreview{
+		Provider:  o.Name(),
+		Endpoint:  o.baseURL + "/chat/completions",
+		Model:     body.Model,
+		MaxTokens: body.MaxTokens,
+		Body:      body,
 	}
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+o.apiKey)
+func (o *OpenAI) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk, 100)
 
 	go func() {
 		defer close(ch)
 
-		client := &http.Client{}
-		resp, err := client.Do(req)
+		resp, err := postChatStream(ctx, o.baseURL+"/chat/completions", o.apiKey, &o.noStreamOptions, func(usage bool) interface{} {
+			body := o.request(messages, opts)
+			if !usage {
+				body.StreamOptions = nil
+			}
+			return body
+		})
 		if err != nil {
-			ch <- StreamChunk{Error: fmt.Errorf("request failed: %w", err)}
+			ch <- StreamChunk{Error: err}
 			return
 		}
 		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			ch <- StreamChunk{Error: fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))}
-			return
-		}
-
 		reader := bufio.NewReader(resp.Body)
+		var finishReason string
+		var usage *Usage
 		for {
 			line, err := reader.ReadString('\n')
 			if err != nil {
 				if err == io.EOF {
-					ch <- StreamChunk{Done: true}
+					ch <- StreamChunk{Done: true, FinishReason: finishReason, Usage: usage}
 					return
 				}
 				ch <- StreamChunk{Error: fmt.Errorf("read error: %w", err)}
@@ -151,7 +224,7 @@ func (o *OpenAI) Chat(ctx context.Context, messages []Message, opts Options) (<-
 
 			data := strings.TrimPrefix(line, "data: ")
 			if data == "[DONE]" {
-				ch <- StreamChunk{Done: true}
+				ch <- StreamChunk{Done: true, FinishReason: finishReason, Usage: usage}
 				return
 			}
 
@@ -160,6 +233,10 @@ func (o *OpenAI) Chat(ctx context.Context, messages []Message, opts Options) (<-
 				continue
 			}
 
+			if streamResp.Usage != nil {
+				usage = streamResp.Usage.usage()
+			}
+
 			if len(streamResp.Choices) > 0 {
 				content := streamResp.Choices[0].Delta.Content
 				if content != "" {
@@ -173,9 +250,9 @@ func (o *OpenAI) Chat(ctx context.Context, messages []Message, opts Options) (<-
 					}
 					ch <- StreamChunk{Content: content}
 				}
-				if streamResp.Choices[0].FinishReason == "stop" {
-					ch <- StreamChunk{Done: true}
-					return
+				// Keep reading: the usage chunk follows the finish_reason one
+				if reason := streamResp.Choices[0].FinishReason; reason != "" {
+					finishReason = reason
 				}
 			}
 		}
@@ -0,0 +1,101 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// strictServer streams one reply and, unless it accepts stream_options,
+// answers 400 to requests that carry them
+type strictServer struct {
+	mu               sync.Mutex
+	acceptsUsage     bool
+	withOptions      int
+	withoutOptions   int
+	alwaysBadRequest bool
+}
+
+func (s *strictServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	_, hasOptions := body["stream_options"]
+
+	s.mu.Lock()
+	if hasOptions {
+		s.withOptions++
+	} else {
+		s.withoutOptions++
+	}
+	s.mu.Unlock()
+
+	if s.alwaysBadRequest || (hasOptions && !s.acceptsUsage) {
+		http.Error(w, `{"error":"unknown field stream_options"}`, http.StatusBadRequest)
+		return
+	}
+	fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"xin chào\"},\"finish_reason\":\"stop\"}]}\n\n")
+	if hasOptions {
+		fmt.Fprint(w, "data: {\"choices\":[],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":2}}\n\n")
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+func collect(t *testing.T, p Provider) (string, StreamChunk) {
+	t.Helper()
+	ch, err := p.Chat(context.Background(), []Message{{Role: "user", Content: "hi"}}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	var last StreamChunk
+	for chunk := range ch {
+		sb.WriteString(chunk.Content)
+		last = chunk
+	}
+	return sb.String(), last
+}
+
+func TestStreamOptionsDroppedWhenRejected(t *testing.T) {
+	srv := &strictServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	for _, p := range []Provider{NewOpenAI("k", "m", ts.URL), NewDeepSeek("k", "m", ts.URL), NewGrok("k", "m", ts.URL)} {
+		*srv = strictServer{}
+		for i := 0; i < 2; i++ {
+			content, last := collect(t, p)
+			if last.Error != nil || !last.Done || content != "xin chào" {
+				t.Fatalf("%s call %d: %q, last chunk %+v", p.Name(), i, content, last)
+			}
+			if last.Usage != nil {
+				t.Errorf("%s call %d: usage %+v from a server without it", p.Name(), i, last.Usage)
+			}
+		}
+		if srv.withOptions != 1 || srv.withoutOptions != 2 {
+			t.Errorf("%s sent stream_options %d times and went without %d times, want 1 and 2", p.Name(), srv.withOptions, srv.withoutOptions)
+		}
+	}
+}
+
+func TestStreamOptionsKeptOnOtherErrors(t *testing.T) {
+	srv := &strictServer{acceptsUsage: true, alwaysBadRequest: true}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	p := NewOpenAI("k", "m", ts.URL)
+	if _, last := collect(t, p); last.Error == nil || !strings.Contains(last.Error.Error(), "API error 400") {
+		t.Fatalf("last chunk = %+v, want the API error", last)
+	}
+
+	// The 400 wasn't about stream_options, so usage is still asked for
+	srv.alwaysBadRequest = false
+	content, last := collect(t, p)
+	if content != "xin chào" || last.Usage == nil || last.Usage.InputTokens != 7 {
+		t.Errorf("after the error: %q with usage %+v, want the usage chunk", content, last.Usage)
+	}
+}
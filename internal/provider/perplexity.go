@@ -58,11 +58,47 @@ type perplexityStreamResponse struct {
 		} `json:"delta"`
 		FinishReason string `json:"finish_reason"`
 	} `json:"choices"`
+	Usage         *openAIUsage             `json:"usage"`
+	Citations     []string                 `json:"citations"`
+	SearchResults []perplexitySearchResult `json:"search_results"`
 }
 
-func (p *Perplexity) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
-	ch := make(chan StreamChunk, 100)
+type perplexitySearchResult struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+	Date  string `json:"date"`
+}
 
+// parsePerplexityCitations merges the citations URL list with search results metadata.
+// Citations define the [n] numbering; search results only enrich titles and dates.
+func parsePerplexityCitations(resp perplexityStreamResponse) []Citation {
+	results := make(map[string]perplexitySearchResult, len(resp.SearchResults))
+	for _, r := range resp.SearchResults {
+		results[r.URL] = r
+	}
+
+	var citations []Citation
+	if len(resp.Citations) > 0 {
+		for i, url := range resp.Citations {
+			c := Citation{Index: i + 1, URL: url}
+			if r, ok := results[url]; ok {
+				c.Title = r.Title
+				c.Date = r.Date
+			}
+			citations = append(citations, c)
+		}
+		return citations
+	}
+
+	// Newer responses may only include search_results
+	for i, r := range resp.SearchResults {
+		citations = append(citations, Citation{Index: i + 1, URL: r.URL, Title: r.Title, Date: r.Date})
+	}
+	return citations
+}
+
+// request builds the API request body for messages
+func (p *Perplexity) request(messages []Message, opts Options) perplexityRequest {
 	// Convert messages
 	pplxMessages := make([]perplexityMessage, len(messages))
 	for i, m := range messages {
@@ -82,13 +118,31 @@ func (p *Perplexity) Chat(ctx context.Context, messages []Message, opts Options)
 		maxTokens = 4096
 	}
 
-	reqBody := perplexityRequest{
+	return perplexityRequest{
 		Model:       model,
 		Messages:    pplxMessages,
 		Stream:      true,
 		MaxTokens:   maxTokens,
 		Temperature: opts.Temperature,
 	}
+}
+
+// Preview returns the request Chat would send, without the API key
+func (p *Perplexity) Preview(messages []Message, opts Options) RequestPreview {
+	body := p.request(messages, opts)
+	return RequestPreview{
+		Provider:  p.Name(),
+		Endpoint:  p.baseURL + "/chat/completions",
+		Model:     body.Model,
+		MaxTokens: body.MaxTokens,
+		Body:      body,
+	}
+}
+
+func (p *Perplexity) Chat(ctx context.Context, messages []Message, opts Options) (<-chan StreamChunk, error) {
+	ch := make(chan StreamChunk, 100)
+
+	reqBody := p.request(messages, opts)
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
@@ -121,11 +175,15 @@ func (p *Perplexity) Chat(ctx context.Context, messages []Message, opts Options)
 		}
 
 		reader := bufio.NewReader(resp.Body)
+		// Citations are repeated on every chunk; keep the latest and send them with Done
+		var citations []Citation
+		var finishReason string
+		var usage *Usage
 		for {
 			line, err := reader.ReadString('\n')
 			if err != nil {
 				if err == io.EOF {
-					ch <- StreamChunk{Done: true}
+					ch <- StreamChunk{Done: true, Citations: citations, FinishReason: finishReason, Usage: usage}
 					return
 				}
 				ch <- StreamChunk{Error: fmt.Errorf("read error: %w", err)}
@@ -143,7 +201,7 @@ func (p *Perplexity) Chat(ctx context.Context, messages []Message, opts Options)
 
 			data := strings.TrimPrefix(line, "data: ")
 			if data == "[DONE]" {
-				ch <- StreamChunk{Done: true}
+				ch <- StreamChunk{Done: true, Citations: citations, FinishReason: finishReason, Usage: usage}
 				return
 			}
 
@@ -152,13 +210,24 @@ func (p *Perplexity) Chat(ctx context.Context, messages []Message, opts Options)
 				continue
 			}
 
+			if parsed := parsePerplexityCitations(streamResp); len(parsed) > 0 {
+				citations = parsed
+			}
+
+			if streamResp.Usage != nil {
+				usage = streamResp.Usage.usage()
+			}
+
 			if len(streamResp.Choices) > 0 {
 				content := streamResp.Choices[0].Delta.Content
 				if content != "" {
 					ch <- StreamChunk{Content: content}
 				}
-				if streamResp.Choices[0].FinishReason == "stop" {
-					ch <- StreamChunk{Done: true}
+				if reason := streamResp.Choices[0].FinishReason; reason != "" {
+					finishReason = reason
+				}
+				if finishReason == "stop" {
+					ch <- StreamChunk{Done: true, Citations: citations, FinishReason: finishReason, Usage: usage}
 					return
 				}
 			}
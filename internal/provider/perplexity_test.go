@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPerplexityCitationsSentWithDone(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Citations repeat on every chunk and may grow; the last list wins
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Theo [1]\"}}],\"citations\":[\"https://a.vn\"]}\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\" và [2].\"},\"finish_reason\":\"stop\"}],"+
+			"\"citations\":[\"https://a.vn\",\"https://b.vn\"],"+
+			"\"search_results\":[{\"title\":\"Bài B\",\"url\":\"https://b.vn\",\"date\":\"2024-05-01\"}]}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer ts.Close()
+
+	content, last := collect(t, NewPerplexity("k", "m", ts.URL))
+	if content != "Theo [1] và [2]." || !last.Done {
+		t.Fatalf("content %q, last chunk %+v", content, last)
+	}
+	want := []Citation{{Index: 1, URL: "https://a.vn"}, {Index: 2, URL: "https://b.vn", Title: "Bài B", Date: "2024-05-01"}}
+	if !reflect.DeepEqual(last.Citations, want) {
+		t.Errorf("citations = %+v, want %+v", last.Citations, want)
+	}
+}
+
+func TestPerplexityCitationsFromSearchResultsOnly(t *testing.T) {
+	got := parsePerplexityCitations(perplexityStreamResponse{
+		SearchResults: []perplexitySearchResult{{Title: "A", URL: "https://a.vn"}, {Title: "B", URL: "https://b.vn"}},
+	})
+	want := []Citation{{Index: 1, URL: "https://a.vn", Title: "A"}, {Index: 2, URL: "https://b.vn", Title: "B"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("citations = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// RequestPreview is the request a provider would send for a chat, built
+// without calling the API. Credentials are left out.
+type RequestPreview struct {
+	Provider  string      `json:"provider"`
+	Endpoint  string      `json:"endpoint"`
+	Model     string      `json:"model"`
+	MaxTokens int         `json:"max_tokens"` // Output limit sent, 0 if the provider's own default applies
+	Body      interface{} `json:"body"`       // The JSON body, in the provider's message format
+}
+
+// Previewer is implemented by providers that can show the request Chat
+// would send
+type Previewer interface {
+	Preview(messages []Message, opts Options) RequestPreview
+}
+
+// Estimate is a rough token count and cost for a chat request
+type Estimate struct {
+	InputTokens     int      `json:"input_tokens"`
+	MaxOutputTokens int      `json:"max_output_tokens"`
+	InputCost       *float64 `json:"input_cost,omitempty"` // USD; nil when the model's price is unknown
+	MaxCost         *float64 `json:"max_cost,omitempty"`   // Input plus a reply of MaxOutputTokens
+}
+
+// Price is a model's cost in USD per million tokens
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// prices holds list prices by model name prefix; the longest match wins
+var prices = map[string]Price{
+	"gpt-4o":            {Input: 2.5, Output: 10},
+	"gpt-4o-mini":       {Input: 0.15, Output: 0.6},
+	"gpt-4.1":           {Input: 2, Output: 8},
+	"gpt-4.1-mini":      {Input: 0.4, Output: 1.6},
+	"gpt-4-turbo":       {Input: 10, Output: 30},
+	"gpt-4":             {Input: 30, Output: 60},
+	"gpt-3.5-turbo":     {Input: 0.5, Output: 1.5},
+	"claude-3-5-sonnet": {Input: 3, Output: 15},
+	"claude-3-5-haiku":  {Input: 0.8, Output: 4},
+	"claude-3-opus":     {Input: 15, Output: 75},
+	"claude-3-haiku":    {Input: 0.25, Output: 1.25},
+	"gemini-1.5-pro":    {Input: 1.25, Output: 5},
+	"gemini-1.5-flash":  {Input: 0.075, Output: 0.3},
+	"gemini-2.0-flash":  {Input: 0.1, Output: 0.4},
+	"grok-beta":         {Input: 5, Output: 15},
+	"deepseek-chat":     {Input: 0.27, Output: 1.1},
+	"deepseek-reasoner": {Input: 0.55, Output: 2.19},
+	"sonar":             {Input: 1, Output: 1},
+	"sonar-pro":         {Input: 3, Output: 15},
+}
+
+// LookupPrice returns the list price of a model. Local Ollama models are free.
+func LookupPrice(providerName, model string) (Price, bool) {
+	if providerName == "ollama" {
+		return Price{}, true
+	}
+	model = strings.ToLower(model)
+	keys := make([]string, 0, len(prices))
+	for k := range prices {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return len(keys[i]) > len(keys[j]) })
+	for _, k := range keys {
+		if strings.HasPrefix(model, k) {
+			return prices[k], true
+		}
+	}
+	return Price{}, false
+}
+
+// EstimateTokens roughly counts the tokens of messages: about four
+// characters per token plus a few tokens of framing per message. Real
+// tokenizers differ by model, so treat it as an order of magnitude.
+func EstimateTokens(messages []Message) int {
+	tokens := 0
+	for _, m := range messages {
+		tokens += (utf8.RuneCountInString(m.Content)+3)/4 + 4
+	}
+	return tokens
+}
+
+// EstimateRequest estimates the input tokens and cost of a chat request
+func EstimateRequest(messages []Message, preview RequestPreview) Estimate {
+	est := Estimate{
+		InputTokens:     EstimateTokens(messages),
+		MaxOutputTokens: preview.MaxTokens,
+	}
+	if price, ok := LookupPrice(preview.Provider, preview.Model); ok {
+		input := float64(est.InputTokens) * price.Input / 1e6
+		maxCost := input + float64(est.MaxOutputTokens)*price.Output/1e6
+		est.InputCost, est.MaxCost = &input, &maxCost
+	}
+	return est
+}
@@ -11,22 +11,40 @@ type Message struct {
 	Content string `json:"content"`
 }
 
+// Citation represents a source referenced by a response (e.g. Perplexity online models)
+type Citation struct {
+	Index int    `json:"index"` // 1-based, matches [n] markers in the response text
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+	Date  string `json:"date,omitempty"`
+}
+
+// Usage is the token count a provider reported for a response
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
 // StreamChunk represents a chunk of streamed response
 type StreamChunk struct {
-	Content string
-	Done    bool
-	Error   error
+	Content   string
+	Done      bool
+	Error     error
+	Citations []Citation // Sources backing the response, usually sent with Done
+	// Sent with Done when the provider reports them
+	FinishReason string
+	Usage        *Usage
 }
 
 // Options contains configuration for a chat request
 type Options struct {
-	Model            string
-	MaxTokens        int
-	Temperature      float64
-	TopP             float64
-	TopK             int
-	FrequencyPenalty float64
-	PresencePenalty  float64
+	Model            string  `json:"model"`
+	MaxTokens        int     `json:"max_tokens"`
+	Temperature      float64 `json:"temperature"`
+	TopP             float64 `json:"top_p"`
+	TopK             int     `json:"top_k"`
+	FrequencyPenalty float64 `json:"frequency_penalty"`
+	PresencePenalty  float64 `json:"presence_penalty"`
 }
 
 // Provider interface for AI providers
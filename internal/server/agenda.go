@@ -0,0 +1,108 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/talk/internal/debate"
+)
+
+type agendaRequest struct {
+	Items []debate.AgendaItem `json:"items"`
+}
+
+func (s *Server) handleGetAgenda(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"agenda": s.session(r).Manager.GetAgenda(),
+	})
+}
+
+func (s *Server) handleSetAgenda(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req agendaRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := sess.Manager.SetAgenda(req.Items); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.broadcastAgenda(w, sess)
+}
+
+func (s *Server) handleClearAgenda(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	sess.Manager.ClearAgenda()
+	s.broadcastAgenda(w, sess)
+}
+
+func (s *Server) handleUpdateAgendaItem(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req debate.AgendaItemUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := sess.Manager.UpdateAgendaItem(chi.URLParam(r, "itemID"), req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.broadcastAgenda(w, sess)
+}
+
+func (s *Server) handleRemoveAgendaItem(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if err := sess.Manager.RemoveAgendaItem(chi.URLParam(r, "itemID")); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	s.broadcastAgenda(w, sess)
+}
+
+// handleNextAgendaItem closes the open item and opens the next one, streaming
+// the item's summary over WebSocket
+func (s *Server) handleNextAgendaItem(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if !sess.Manager.IsRunning() {
+		respondError(w, http.StatusBadRequest, "Debate is not running")
+		return
+	}
+	if sess.Manager.GetAgenda() == nil {
+		respondError(w, http.StatusBadRequest, "No agenda set")
+		return
+	}
+
+	streamCh := make(chan debate.StreamMessage, 100)
+
+	go func() {
+		defer close(streamCh)
+		if err := sess.Manager.NextAgendaItem(streamCh); err != nil {
+			log.Printf("Error in NextAgendaItem: %v", err)
+		}
+	}()
+
+	go func() {
+		for msg := range streamCh {
+			s.hub.BroadcastSession(sess.ID, msg)
+		}
+	}()
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "processing"})
+}
+
+// broadcastAgenda sends the session's agenda to its clients and the response
+func (s *Server) broadcastAgenda(w http.ResponseWriter, sess *debate.Session) {
+	agenda := sess.Manager.GetAgenda()
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":   "agenda_changed",
+		"agenda": agenda,
+	})
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"agenda": agenda,
+	})
+}
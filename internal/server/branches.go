@@ -0,0 +1,124 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/talk/internal/debate"
+)
+
+type forkRequest struct {
+	MessageID string `json:"message_id"`
+	Name      string `json:"name"`
+	Switch    bool   `json:"switch"`  // Make the new branch active
+	Session   bool   `json:"session"` // Fork into a new session instead of a branch
+}
+
+func (s *Server) handleListBranches(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.session(r).Manager.ListBranches())
+}
+
+func (s *Server) handleFork(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req forkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.MessageID == "" {
+		respondError(w, http.StatusBadRequest, "Message ID is required")
+		return
+	}
+
+	if req.Session {
+		s.forkSession(w, sess, req)
+		return
+	}
+
+	branch, err := sess.Manager.Fork(req.MessageID, req.Name, req.Switch)
+	if err != nil {
+		respondError(w, conflictOrNotFound(err), err.Error())
+		return
+	}
+
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":   "branch_created",
+		"branch": branch,
+	})
+	if branch.Active {
+		s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+			"type":      "branch_switched",
+			"branch_id": branch.ID,
+		})
+	}
+
+	respondJSON(w, http.StatusCreated, branch)
+}
+
+// forkSession continues the transcript up to the fork point in a new session
+// with the same agents, panel and mode
+func (s *Server) forkSession(w http.ResponseWriter, sess *debate.Session, req forkRequest) {
+	snap, err := sess.Manager.ForkSnapshot(req.MessageID)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	forked, err := s.sessions.Fork(sess.ID, req.Name)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := forked.Manager.Restore(snap); err != nil {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	s.hub.Broadcast(map[string]interface{}{
+		"type":       "session_created",
+		"session_id": forked.ID,
+	})
+
+	respondJSON(w, http.StatusCreated, forked.Info())
+}
+
+func (s *Server) handleSwitchBranch(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	branchID := chi.URLParam(r, "branchID")
+
+	if err := sess.Manager.SwitchBranch(branchID); err != nil {
+		respondError(w, conflictOrNotFound(err), err.Error())
+		return
+	}
+
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":      "branch_switched",
+		"branch_id": branchID,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status": "switched",
+		"branch": branchID,
+	})
+}
+
+func (s *Server) handleGetBranchMessages(w http.ResponseWriter, r *http.Request) {
+	messages, err := s.session(r).Manager.GetBranchMessages(chi.URLParam(r, "branchID"))
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, messages)
+}
+
+// conflictOrNotFound maps errors from edits to a debate's transcript or
+// branches: a running turn is a conflict, anything else a missing ID
+func conflictOrNotFound(err error) int {
+	if errors.Is(err, debate.ErrTurnInProgress) {
+		return http.StatusConflict
+	}
+	return http.StatusNotFound
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/user/talk/internal/agent"
+	"github.com/user/talk/internal/debate"
+)
+
+type castRequest struct {
+	roleAgentRequest        // Caster; the default provider when empty
+	Topic            string `json:"topic"` // Defaults to the session topic
+	Count            int    `json:"count"`
+}
+
+type castAcceptRequest struct {
+	Agents []debate.CastProposal `json:"agents"`
+	Save   bool                  `json:"save"` // Write the agents to config.yaml instead of a temporary panel
+}
+
+// availableProviders lists providers with a configured key, the default provider first
+func (s *Server) availableProviders() []string {
+	defaultProvider := s.storage.GetDefaultProvider()
+	var result []string
+	for name, ok := range s.storage.GetKeyStatus() {
+		if ok && name != defaultProvider {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	if s.storage.GetKeyStatus()[defaultProvider] {
+		result = append([]string{defaultProvider}, result...)
+	}
+	return result
+}
+
+// takenAgentIDs returns the IDs a new panel must not reuse
+func (s *Server) takenAgentIDs(sess *debate.Session) []string {
+	var ids []string
+	if s.agentFuncs != nil && s.agentFuncs.GetConfigs != nil {
+		for _, c := range s.agentFuncs.GetConfigs() {
+			ids = append(ids, c.ID)
+		}
+	}
+	for _, a := range sess.Manager.GetAgents() {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+// handleCast asks a model to propose a panel of agents for the topic
+func (s *Server) handleCast(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req castRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Topic == "" {
+		req.Topic = sess.Manager.GetTopic()
+	}
+	if req.AgentID == "" && req.Provider == "" {
+		req.Provider = s.storage.GetDefaultProvider()
+	}
+
+	caster, err := s.resolveRoleAgent(req.roleAgentRequest, "caster")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	available := s.availableProviders()
+	proposals, err := debate.CastPanel(r.Context(), caster, sess.Manager.GetPromptSet(), req.Topic, req.Count, available, s.takenAgentIDs(sess))
+	if err != nil {
+		respondError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"topic":     req.Topic,
+		"caster":    caster.Name,
+		"available": available,
+		"agents":    proposals,
+	})
+}
+
+// handleAcceptCast uses a proposed panel for the session, either as temporary
+// agents or saved to config.yaml and selected as the session's subset
+func (s *Server) handleAcceptCast(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req castAcceptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := debate.ValidateCast(req.Agents, s.availableProviders()); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if s.agentFuncs == nil || s.agentFuncs.Build == nil || (req.Save && s.agentFuncs.Add == nil) {
+		respondError(w, http.StatusNotImplemented, "Agent config management not available")
+		return
+	}
+
+	configs := make([]AgentYAMLConfig, len(req.Agents))
+	ids := make([]string, len(req.Agents))
+	for i, p := range req.Agents {
+		configs[i] = AgentYAMLConfig{
+			ID:           p.ID,
+			Name:         p.Name,
+			Role:         p.Role,
+			SystemPrompt: p.SystemPrompt,
+			Provider:     p.Provider,
+			Color:        p.Color,
+		}
+		ids[i] = p.ID
+	}
+
+	if req.Save {
+		s.saveCast(w, sess, configs, ids)
+		return
+	}
+
+	panel := make([]*agent.Agent, len(configs))
+	for i, cfg := range configs {
+		a, err := s.agentFuncs.Build(cfg)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		panel[i] = a
+	}
+	if _, err := s.sessions.SetPanel(sess.ID, panel); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, sess.Info())
+}
+
+// saveCast adds the panel to config.yaml and restricts the session to it
+func (s *Server) saveCast(w http.ResponseWriter, sess *debate.Session, configs []AgentYAMLConfig, ids []string) {
+	// Check every ID up front so a clash doesn't leave a half-saved panel
+	if s.agentFuncs.GetConfigs != nil {
+		for _, c := range s.agentFuncs.GetConfigs() {
+			for _, id := range ids {
+				if c.ID == id {
+					respondError(w, http.StatusConflict, "agent with ID "+id+" already exists")
+					return
+				}
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		if err := s.agentFuncs.Add(cfg); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if s.agentFuncs.Reload != nil {
+		s.agentFuncs.Reload()
+	}
+
+	if _, err := s.sessions.Update(sess.ID, "", ids); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.hub.Broadcast(map[string]interface{}{
+		"type": "agents_updated",
+	})
+	respondJSON(w, http.StatusCreated, sess.Info())
+}
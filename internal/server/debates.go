@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/talk/internal/debate"
+)
+
+// requireDebates reports whether debate history is available, responding otherwise
+func (s *Server) requireDebates(w http.ResponseWriter) bool {
+	if s.debates == nil {
+		respondError(w, http.StatusNotImplemented, "Debate history not available")
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleListDebates(w http.ResponseWriter, r *http.Request) {
+	if !s.requireDebates(w) {
+		return
+	}
+	summaries, err := s.debates.ListDebates()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) handleGetDebate(w http.ResponseWriter, r *http.Request) {
+	if !s.requireDebates(w) {
+		return
+	}
+	snap, err := s.debates.GetDebate(chi.URLParam(r, "debateID"))
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, snap)
+}
+
+func (s *Server) handleDeleteDebate(w http.ResponseWriter, r *http.Request) {
+	if !s.requireDebates(w) {
+		return
+	}
+	debateID := chi.URLParam(r, "debateID")
+
+	// Don't delete a debate out from under a session that is still using it
+	for _, info := range s.sessions.List() {
+		sess, _ := s.sessions.Get(info.ID)
+		if sess != nil && sess.Manager.GetDebateID() == debateID && sess.Manager.IsRunning() {
+			respondError(w, http.StatusConflict, "Debate is running in session "+info.ID)
+			return
+		}
+	}
+
+	if err := s.debates.DeleteDebate(debateID); err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted", "id": debateID})
+}
+
+type resumeRequest struct {
+	SessionID string `json:"session_id"` // Defaults to the default session
+}
+
+func (s *Server) handleResumeDebate(w http.ResponseWriter, r *http.Request) {
+	if !s.requireDebates(w) {
+		return
+	}
+
+	var req resumeRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.SessionID == "" {
+		req.SessionID = debate.DefaultSessionID
+	}
+
+	sess, ok := s.sessions.Get(req.SessionID)
+	if !ok {
+		respondError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	snap, err := s.debates.GetDebate(chi.URLParam(r, "debateID"))
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	// Two managers on one debate would both write its snapshot and event log
+	if open, ok := s.sessions.ByDebate(snap.ID); ok && open.ID != sess.ID {
+		respondError(w, http.StatusConflict, "debate is already open in session "+open.ID)
+		return
+	}
+
+	if err := sess.Manager.Restore(snap); err != nil {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":      "debate_resumed",
+		"debate_id": snap.ID,
+		"topic":     snap.Topic,
+		"mode":      snap.Mode,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     "resumed",
+		"debate_id":  snap.ID,
+		"session_id": sess.ID,
+		"topic":      snap.Topic,
+	})
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/talk/internal/debate"
+)
+
+// recordEvent adds an event broadcast to a session to its debate's event log
+func (s *Server) recordEvent(sessionID string, data []byte) {
+	if sess, ok := s.sessions.Get(sessionID); ok {
+		sess.Manager.RecordEvent(data)
+	}
+}
+
+func (s *Server) handleGetDebateEvents(w http.ResponseWriter, r *http.Request) {
+	if !s.requireDebates(w) {
+		return
+	}
+	events, err := s.debates.LoadEvents(chi.URLParam(r, "debateID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, events)
+}
+
+// handleRebuildDebate replays a debate's event log and resumes the rebuilt
+// debate in a session, like handleResumeDebate does from the snapshot
+func (s *Server) handleRebuildDebate(w http.ResponseWriter, r *http.Request) {
+	if !s.requireDebates(w) {
+		return
+	}
+
+	var req resumeRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.SessionID == "" {
+		req.SessionID = debate.DefaultSessionID
+	}
+
+	sess, ok := s.sessions.Get(req.SessionID)
+	if !ok {
+		respondError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+
+	events, err := s.debates.LoadEvents(chi.URLParam(r, "debateID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	snap, err := debate.ReplayEvents(events)
+	if err != nil {
+		respondError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	// Two managers on one debate would both write its snapshot and event log
+	if open, ok := s.sessions.ByDebate(snap.ID); ok && open.ID != sess.ID {
+		respondError(w, http.StatusConflict, "debate is already open in session "+open.ID)
+		return
+	}
+
+	if err := sess.Manager.Restore(snap); err != nil {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":      "debate_resumed",
+		"debate_id": snap.ID,
+		"topic":     snap.Topic,
+		"mode":      snap.Mode,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"status":     "rebuilt",
+		"debate_id":  snap.ID,
+		"session_id": sess.ID,
+		"topic":      snap.Topic,
+		"events":     len(events),
+		"messages":   len(snap.Messages),
+	})
+}
+
+type playbackRequest struct {
+	DebateID string  `json:"debate_id"` // Defaults to the session's debate
+	Speed    float64 `json:"speed"`     // Defaults to 1
+}
+
+func (s *Server) handleGetPlayback(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"playback": s.session(r).Manager.GetPlayback(),
+	})
+}
+
+func (s *Server) handleStartPlayback(w http.ResponseWriter, r *http.Request) {
+	if !s.requireDebates(w) {
+		return
+	}
+	sess := s.session(r)
+
+	var req playbackRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if req.DebateID == "" {
+		req.DebateID = sess.Manager.GetDebateID()
+	}
+	if req.DebateID == "" {
+		respondError(w, http.StatusBadRequest, "Debate ID is required")
+		return
+	}
+	if req.Speed == 0 {
+		req.Speed = 1
+	}
+
+	events, err := s.debates.LoadEvents(req.DebateID)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := sess.Manager.StartPlayback(req.DebateID, events, req.Speed); err != nil {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"playback": sess.Manager.GetPlayback(),
+	})
+}
+
+func (s *Server) handleSetPlaybackSpeed(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req playbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := sess.Manager.SetPlaybackSpeed(req.Speed); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"playback": sess.Manager.GetPlayback(),
+	})
+}
+
+func (s *Server) handleStopPlayback(w http.ResponseWriter, r *http.Request) {
+	s.session(r).Manager.StopPlayback()
+	respondJSON(w, http.StatusOK, map[string]string{"status": "stopped"})
+}
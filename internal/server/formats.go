@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/user/talk/internal/debate"
+)
+
+type formatRequest struct {
+	Format string                 `json:"format"`
+	Sides  map[string]debate.Side `json:"sides"`
+}
+
+func (s *Server) handleGetFormats(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, debate.Formats(s.session(r).Manager.GetPromptSet()))
+}
+
+func (s *Server) handleGetFormat(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"format": s.session(r).Manager.GetFormatStatus(),
+	})
+}
+
+func (s *Server) handleSetFormat(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req formatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Format == "" {
+		respondError(w, http.StatusBadRequest, "Format is required")
+		return
+	}
+
+	s.applyFormat(w, sess, req.Format, req.Sides)
+}
+
+func (s *Server) handleClearFormat(w http.ResponseWriter, r *http.Request) {
+	s.applyFormat(w, s.session(r), "", nil)
+}
+
+// applyFormat switches the session's format and broadcasts the new status
+func (s *Server) applyFormat(w http.ResponseWriter, sess *debate.Session, format string, sides map[string]debate.Side) {
+	if err := sess.Manager.SetFormat(format, sides); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, debate.ErrTurnInProgress) {
+			status = http.StatusConflict
+		}
+		respondError(w, status, err.Error())
+		return
+	}
+
+	formatStatus := sess.Manager.GetFormatStatus()
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":   "format_changed",
+		"format": formatStatus,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"format": formatStatus,
+	})
+}
@@ -0,0 +1,14 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/user/talk/internal/debate"
+)
+
+func (s *Server) handleGetHooks(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"active":    debate.DefaultPipeline().Names(),
+		"available": debate.HookNames(),
+	})
+}
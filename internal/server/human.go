@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/user/talk/internal/debate"
+)
+
+type humanRequest struct {
+	AgentID string `json:"agent_id"` // Optional, any waiting human when empty
+	Content string `json:"content"`
+}
+
+type humanTimeoutRequest struct {
+	TimeoutSeconds int `json:"timeout_seconds"` // 0 restores the default
+}
+
+// clientMessage is a message sent by a WebSocket client
+type clientMessage struct {
+	Type string `json:"type"` // "human_reply" or "human_skip"
+	humanRequest
+}
+
+func (s *Server) handleGetHumanTurn(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"turn":            sess.Manager.GetHumanTurn(),
+		"timeout_seconds": int(sess.Manager.GetHumanTimeout().Seconds()),
+	})
+}
+
+func (s *Server) handleHumanReply(w http.ResponseWriter, r *http.Request) {
+	var req humanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := s.session(r).Manager.SubmitHuman(req.AgentID, req.Content); err != nil {
+		respondError(w, humanErrorStatus(err), err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "submitted"})
+}
+
+func (s *Server) handleHumanSkip(w http.ResponseWriter, r *http.Request) {
+	var req humanRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if err := s.session(r).Manager.SkipHuman(req.AgentID); err != nil {
+		respondError(w, humanErrorStatus(err), err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "skipped"})
+}
+
+func (s *Server) handleSetHumanTimeout(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req humanTimeoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := sess.Manager.SetHumanTimeout(time.Duration(req.TimeoutSeconds) * time.Second); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]int{
+		"timeout_seconds": int(sess.Manager.GetHumanTimeout().Seconds()),
+	})
+}
+
+// handleClientMessage lets a human seat answer or skip over the WebSocket
+func (s *Server) handleClientMessage(sessionID string, data []byte) {
+	var msg clientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Printf("Ignoring invalid client message: %v", err)
+		return
+	}
+	sess, ok := s.sessions.Get(sessionID)
+	if !ok {
+		return
+	}
+
+	var err error
+	switch msg.Type {
+	case "human_reply":
+		err = sess.Manager.SubmitHuman(msg.AgentID, msg.Content)
+	case "human_skip":
+		err = sess.Manager.SkipHuman(msg.AgentID)
+	default:
+		return
+	}
+	if err != nil {
+		log.Printf("Client %s failed: %v", msg.Type, err)
+	}
+}
+
+// humanErrorStatus maps human turn errors to HTTP status codes
+func humanErrorStatus(err error) int {
+	if errors.Is(err, debate.ErrNoHumanTurn) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/talk/internal/debate"
+)
+
+type interjectRequest struct {
+	Content  string   `json:"content"`
+	To       string   `json:"to"`       // Agent asked directly, who answers next (optional)
+	Audience []string `json:"audience"` // Agents a private hint is shown to (optional)
+}
+
+type editMessageRequest struct {
+	Content string `json:"content"`
+}
+
+type regenerateRequest struct {
+	AgentID string `json:"agent_id"` // Empty keeps the original author
+}
+
+func (s *Server) handleInterject(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req interjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		respondError(w, http.StatusBadRequest, "Content is required")
+		return
+	}
+
+	// The manager broadcasts the interjection to clients
+	msg, err := sess.Manager.Interject(req.Content, req.To, req.Audience)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, msg)
+}
+
+func (s *Server) handleEditMessage(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req editMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Content) == "" {
+		respondError(w, http.StatusBadRequest, "Content is required")
+		return
+	}
+
+	msg, err := sess.Manager.EditMessage(chi.URLParam(r, "messageID"), req.Content)
+	if err != nil {
+		respondError(w, conflictOrNotFound(err), err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, msg)
+}
+
+func (s *Server) handleDeleteMessage(w http.ResponseWriter, r *http.Request) {
+	messageID := chi.URLParam(r, "messageID")
+	if err := s.session(r).Manager.DeleteMessage(messageID); err != nil {
+		respondError(w, conflictOrNotFound(err), err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted", "message_id": messageID})
+}
+
+func (s *Server) handleRegenerateMessage(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	messageID := chi.URLParam(r, "messageID")
+
+	var req regenerateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	if !sess.Manager.IsRunning() {
+		respondError(w, http.StatusBadRequest, "Debate is not running")
+		return
+	}
+	if !hasMessage(sess.Manager.GetMessages(), messageID) {
+		respondError(w, http.StatusNotFound, "Message not found")
+		return
+	}
+	if req.AgentID != "" {
+		if _, ok := s.sessions.Agent(req.AgentID); !ok {
+			respondError(w, http.StatusNotFound, "Agent not found")
+			return
+		}
+	}
+
+	// Create channel for streaming
+	streamCh := make(chan debate.StreamMessage, 100)
+
+	go func() {
+		defer close(streamCh)
+		if _, err := sess.Manager.RegenerateMessage(messageID, req.AgentID, streamCh); err != nil {
+			log.Printf("Error regenerating %s: %v", messageID, err)
+		}
+	}()
+
+	// Stream to WebSocket clients
+	go func() {
+		for msg := range streamCh {
+			s.hub.BroadcastSession(sess.ID, msg)
+		}
+	}()
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "processing", "message_id": messageID})
+}
+
+// hasMessage reports whether messages contains id
+func hasMessage(messages []debate.Message, id string) bool {
+	for _, msg := range messages {
+		if msg.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/talk/internal/debate"
+)
+
+type promptRequest struct {
+	Set string `json:"set"` // Empty = the server default
+}
+
+func (s *Server) handleGetPromptSets(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"sets":    debate.PromptSets(),
+		"default": debate.GetDefaultPromptSet(),
+	})
+}
+
+func (s *Server) handleGetPromptSet(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"set": s.session(r).Manager.GetPromptSet(),
+	})
+}
+
+func (s *Server) handleSetPromptSet(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req promptRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := sess.Manager.SetPromptSet(req.Set); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	set := sess.Manager.GetPromptSet()
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type": "prompt_set_changed",
+		"set":  set,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"set": set,
+	})
+}
+
+// handlePreviewPrompt returns the messages an agent would be sent next,
+// optionally rendered with another prompt set (?set=)
+func (s *Server) handlePreviewPrompt(w http.ResponseWriter, r *http.Request) {
+	agentID := r.URL.Query().Get("agent")
+	if agentID == "" {
+		respondError(w, http.StatusBadRequest, "Agent is required")
+		return
+	}
+
+	messages, err := s.session(r).Manager.PreviewPrompt(agentID, r.URL.Query().Get("set"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"agent_id": agentID,
+		"messages": messages,
+	})
+}
+
+// handlePreviewTurn returns exactly what an agent's next turn would send to
+// its provider, with a token and cost estimate, without calling the model
+func (s *Server) handlePreviewTurn(w http.ResponseWriter, r *http.Request) {
+	preview, err := s.session(r).Manager.PreviewTurn(r.Context(), chi.URLParam(r, "agentID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, preview)
+}
@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/user/talk/internal/agent"
+	"github.com/user/talk/internal/debate"
+)
+
+// roleAgentRequest designates the agent behind a helper role (moderator, judge, summarizer):
+// either an existing agent by ID or a standalone provider/model.
+type roleAgentRequest struct {
+	AgentID  string `json:"agent_id"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	BaseURL  string `json:"base_url"`
+}
+
+// resolveRoleAgent returns the agent for a helper role; role names the standalone agent
+func (s *Server) resolveRoleAgent(req roleAgentRequest, role string) (*agent.Agent, error) {
+	if req.AgentID != "" {
+		a, ok := s.sessions.Agent(req.AgentID)
+		if !ok {
+			return nil, fmt.Errorf("agent not found: %s", req.AgentID)
+		}
+		return a, nil
+	}
+
+	if req.Provider == "" {
+		return nil, fmt.Errorf("agent_id or provider is required")
+	}
+	if s.agentFuncs == nil || s.agentFuncs.Build == nil {
+		return nil, fmt.Errorf("agent creation not available")
+	}
+	return s.agentFuncs.Build(AgentYAMLConfig{
+		ID:       role,
+		Name:     role,
+		Provider: req.Provider,
+		Model:    req.Model,
+		BaseURL:  req.BaseURL,
+	})
+}
+
+func (s *Server) handleGetModerator(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"moderator": s.session(r).Manager.GetModerator(),
+	})
+}
+
+func (s *Server) handleSetModerator(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req roleAgentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	moderator, err := s.resolveRoleAgent(req, "moderator")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sess.Manager.SetModerator(moderator)
+
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":      "moderator_changed",
+		"moderator": sess.Manager.GetModerator(),
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"moderator": sess.Manager.GetModerator(),
+	})
+}
+
+func (s *Server) handleClearModerator(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	sess.Manager.SetModerator(nil)
+
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":      "moderator_changed",
+		"moderator": nil,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "cleared"})
+}
+
+type judgeRequest struct {
+	roleAgentRequest
+	Rubric []debate.Criterion `json:"rubric"`
+}
+
+func (s *Server) handleGetVerdict(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"verdict": s.session(r).Manager.GetVerdict(),
+	})
+}
+
+func (s *Server) handleJudge(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req judgeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if err := debate.ValidateRubric(req.Rubric); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	judge, err := s.resolveRoleAgent(req.roleAgentRequest, "judge")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":  "judging_started",
+		"judge": judge.Name,
+	})
+
+	verdict, err := sess.Manager.Judge(r.Context(), judge, req.Rubric)
+	if err != nil {
+		s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+			"type":  "judging_failed",
+			"error": err.Error(),
+		})
+		status := http.StatusBadGateway
+		if errors.Is(err, debate.ErrNothingToJudge) {
+			status = http.StatusBadRequest
+		}
+		respondError(w, status, err.Error())
+		return
+	}
+
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
+		"type":    "verdict",
+		"verdict": verdict,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"verdict": verdict,
+	})
+}
+
+type summarizerRequest struct {
+	roleAgentRequest
+	RollingEvery *int `json:"rolling_every"`
+}
+
+func (s *Server) handleGetSummarizer(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"summarizer":    sess.Manager.GetSummarizer(),
+		"rolling_every": sess.Manager.GetRollingSummaryEvery(),
+	})
+}
+
+func (s *Server) handleSetSummarizer(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	var req summarizerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.RollingEvery != nil {
+		if err := sess.Manager.SetRollingSummaryEvery(*req.RollingEvery); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// Without an agent or provider the summarizer is left as is
+	if req.AgentID != "" || req.Provider != "" {
+		summarizer, err := s.resolveRoleAgent(req.roleAgentRequest, "summarizer")
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		sess.Manager.SetSummarizer(summarizer)
+	}
+
+	s.handleGetSummarizer(w, r)
+}
+
+func (s *Server) handleClearSummarizer(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	sess.Manager.SetSummarizer(nil)
+	s.handleGetSummarizer(w, r)
+}
+
+func (s *Server) handleGetSummaries(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.session(r).Manager.GetSummaries())
+}
+
+func (s *Server) handleSummarize(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+
+	kind := debate.SummaryFinal
+	if k := r.URL.Query().Get("kind"); k != "" {
+		kind = k
+	}
+	if kind != debate.SummaryFinal && kind != debate.SummaryRolling {
+		respondError(w, http.StatusBadRequest, "Invalid summary kind")
+		return
+	}
+	if sess.Manager.GetSummarizer() == nil {
+		respondError(w, http.StatusBadRequest, debate.ErrNoSummarizer.Error())
+		return
+	}
+	spoken := false
+	for _, msg := range sess.Manager.GetMessages() {
+		spoken = spoken || msg.AgentID != "system"
+	}
+	if !spoken {
+		respondError(w, http.StatusBadRequest, debate.ErrNothingToSummarize.Error())
+		return
+	}
+
+	streamCh := make(chan debate.StreamMessage, 100)
+
+	go func() {
+		defer close(streamCh)
+		// Summaries outlive the request, and may be written after the debate stopped
+		if _, err := sess.Manager.Summarize(context.Background(), kind, streamCh); err != nil {
+			log.Printf("Error in Summarize: %v", err)
+		}
+	}()
+
+	go func() {
+		for msg := range streamCh {
+			s.hub.BroadcastSession(sess.ID, msg)
+		}
+	}()
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "processing"})
+}
@@ -6,9 +6,11 @@ import (
 	"io/fs"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/user/talk/internal/agent"
 	"github.com/user/talk/internal/debate"
 	"github.com/user/talk/internal/proxy"
 	"github.com/user/talk/internal/storage"
@@ -22,51 +24,62 @@ type AgentConfigFuncs struct {
 	Delete     func(string) error
 	Reorder    func([]string) error
 	Reload     func() error
+	// Build creates a standalone agent (e.g. a moderator model) without saving it
+	Build func(AgentYAMLConfig) (*agent.Agent, error)
 }
 
 // AgentYAMLConfig represents agent configuration from YAML
 type AgentYAMLConfig struct {
-	ID               string  `json:"id" yaml:"id"`
-	Name             string  `json:"name" yaml:"name"`
-	Role             string  `json:"role" yaml:"role"`
-	SystemPrompt     string  `json:"system_prompt" yaml:"system_prompt"`
-	Provider         string  `json:"provider" yaml:"provider"`
-	Model            string  `json:"model" yaml:"model"`
-	Color            string  `json:"color" yaml:"color"`
-	APIKey           string  `json:"api_key,omitempty" yaml:"api_key,omitempty"`
-	BaseURL          string  `json:"base_url,omitempty" yaml:"base_url,omitempty"`
-	Temperature      float64 `json:"temperature,omitempty" yaml:"temperature,omitempty"`
-	MaxTokens        int     `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty"`
-	TopP             float64 `json:"top_p,omitempty" yaml:"top_p,omitempty"`
-	TopK             int     `json:"top_k,omitempty" yaml:"top_k,omitempty"`
-	FrequencyPenalty float64 `json:"frequency_penalty,omitempty" yaml:"frequency_penalty,omitempty"`
-	PresencePenalty  float64 `json:"presence_penalty,omitempty" yaml:"presence_penalty,omitempty"`
+	ID               string           `json:"id" yaml:"id"`
+	Name             string           `json:"name" yaml:"name"`
+	Role             string           `json:"role" yaml:"role"`
+	Kind             string           `json:"kind,omitempty" yaml:"kind,omitempty"`
+	SystemPrompt     string           `json:"system_prompt" yaml:"system_prompt"`
+	Provider         string           `json:"provider" yaml:"provider"`
+	Model            string           `json:"model" yaml:"model"`
+	Color            string           `json:"color" yaml:"color"`
+	APIKey           string           `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+	BaseURL          string           `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+	Temperature      float64          `json:"temperature,omitempty" yaml:"temperature,omitempty"`
+	MaxTokens        int              `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty"`
+	TopP             float64          `json:"top_p,omitempty" yaml:"top_p,omitempty"`
+	TopK             int              `json:"top_k,omitempty" yaml:"top_k,omitempty"`
+	FrequencyPenalty float64          `json:"frequency_penalty,omitempty" yaml:"frequency_penalty,omitempty"`
+	PresencePenalty  float64          `json:"presence_penalty,omitempty" yaml:"presence_penalty,omitempty"`
+	Visibility       agent.Visibility `json:"visibility" yaml:"visibility,omitempty"`
 }
 
 // Server represents the HTTP server
 type Server struct {
-	router  *chi.Mux
-	manager *debate.Manager
-	hub     *Hub
-	storage *storage.Storage
-	proxy   *proxy.Proxy
+	router   *chi.Mux
+	sessions *debate.Sessions
+	hub      *Hub
+	storage  *storage.Storage
+	proxy    *proxy.Proxy
 	// Callback to reload agents when API keys change
 	reloadAgents func() error
 	// Agent config management functions
 	agentFuncs *AgentConfigFuncs
+	// Saved debate history, nil if persistence is disabled
+	debates *storage.DebateStore
 }
 
 // NewServer creates a new server
-func NewServer(manager *debate.Manager, staticFS embed.FS, store *storage.Storage, reloadFn func() error) *Server {
+func NewServer(sessions *debate.Sessions, staticFS embed.FS, store *storage.Storage, reloadFn func() error) *Server {
 	s := &Server{
 		router:       chi.NewRouter(),
-		manager:      manager,
+		sessions:     sessions,
 		hub:          NewHub(),
 		storage:      store,
 		proxy:        proxy.NewProxy(store),
 		reloadAgents: reloadFn,
 	}
 
+	// Manager-originated events (autonomous runs) go straight to the hub
+	sessions.SetEventFunc(s.hub.BroadcastSession)
+	s.hub.SetHandler(s.handleClientMessage)
+	s.hub.SetObserver(s.recordEvent)
+
 	s.setupRoutes(staticFS)
 	return s
 }
@@ -76,6 +89,11 @@ func (s *Server) SetAgentFuncs(funcs *AgentConfigFuncs) {
 	s.agentFuncs = funcs
 }
 
+// SetDebateStore sets the store used by the debate history API
+func (s *Server) SetDebateStore(store *storage.DebateStore) {
+	s.debates = store
+}
+
 func (s *Server) setupRoutes(staticFS embed.FS) {
 	s.router.Use(middleware.Logger)
 	s.router.Use(middleware.Recoverer)
@@ -89,15 +107,31 @@ func (s *Server) setupRoutes(staticFS embed.FS) {
 		r.Put("/agents/{agentID}", s.handleUpdateAgent)
 		r.Delete("/agents/{agentID}", s.handleDeleteAgent)
 		r.Post("/agents/reorder", s.handleReorderAgents)
-		r.Get("/debate/status", s.handleGetStatus)
-		r.Post("/debate/start", s.handleStartDebate)
-		r.Post("/debate/continue", s.handleContinueDebate)
-		r.Post("/debate/stop", s.handleStopDebate)
-		r.Post("/debate/next", s.handleNextTurn)
-		r.Post("/debate/agent/{agentID}", s.handleAgentTurn)
-		r.Get("/debate/messages", s.handleGetMessages)
-		r.Post("/debate/mode", s.handleSetMode)
-		r.Post("/debate/reset", s.handleReset)
+
+		// Legacy debate routes operate on the default session
+		r.Route("/debate", s.debateRoutes)
+
+		// Turn pipeline hooks (configured in config.yaml)
+		r.Get("/hooks", s.handleGetHooks)
+
+		// Debate sessions
+		r.Get("/sessions", s.handleListSessions)
+		r.Post("/sessions", s.handleCreateSession)
+		r.Route("/sessions/{sessionID}", func(r chi.Router) {
+			r.Use(s.sessionCtx)
+			r.Get("/", s.handleGetSession)
+			r.Put("/", s.handleUpdateSession)
+			r.Delete("/", s.handleDeleteSession)
+			r.Route("/debate", s.debateRoutes)
+		})
+
+		// Saved debate history
+		r.Get("/debates", s.handleListDebates)
+		r.Get("/debates/{debateID}", s.handleGetDebate)
+		r.Delete("/debates/{debateID}", s.handleDeleteDebate)
+		r.Post("/debates/{debateID}/resume", s.handleResumeDebate)
+		r.Get("/debates/{debateID}/events", s.handleGetDebateEvents)
+		r.Post("/debates/{debateID}/rebuild", s.handleRebuildDebate)
 
 		// API Keys management (legacy)
 		r.Get("/settings/keys", s.handleGetAPIKeys)
@@ -136,10 +170,101 @@ func (s *Server) setupRoutes(staticFS embed.FS) {
 	s.router.Handle("/*", fileServer)
 }
 
+// debateRoutes registers the per-session debate API
+func (s *Server) debateRoutes(r chi.Router) {
+	r.Get("/agents", s.handleGetAgents)
+	r.Post("/participants", s.handleJoin)
+	r.Delete("/participants/{agentID}", s.handleLeave)
+	r.Get("/status", s.handleGetStatus)
+	r.Post("/start", s.handleStartDebate)
+	r.Post("/continue", s.handleContinueDebate)
+	r.Post("/interject", s.handleInterject)
+	r.Post("/stop", s.handleStopDebate)
+	r.Post("/next", s.handleNextTurn)
+	r.Post("/agent/{agentID}", s.handleAgentTurn)
+	r.Post("/parallel", s.handleParallelRound)
+	r.Get("/messages", s.handleGetMessages)
+	r.Patch("/messages/{messageID}", s.handleEditMessage)
+	r.Delete("/messages/{messageID}", s.handleDeleteMessage)
+	r.Post("/messages/{messageID}/regenerate", s.handleRegenerateMessage)
+	r.Get("/export", s.handleExport)
+	r.Get("/modes", s.handleGetModes)
+	r.Post("/mode", s.handleSetMode)
+	r.Post("/reset", s.handleReset)
+	r.Get("/termination", s.handleGetTermination)
+	r.Post("/termination", s.handleSetTermination)
+
+	// Structured formats (Oxford, Lincoln-Douglas, panel)
+	r.Get("/formats", s.handleGetFormats)
+	r.Get("/format", s.handleGetFormat)
+	r.Post("/format", s.handleSetFormat)
+	r.Delete("/format", s.handleClearFormat)
+
+	// Agenda of sub-topics with turn or time budgets
+	r.Get("/agenda", s.handleGetAgenda)
+	r.Put("/agenda", s.handleSetAgenda)
+	r.Delete("/agenda", s.handleClearAgenda)
+	r.Patch("/agenda/items/{itemID}", s.handleUpdateAgendaItem)
+	r.Delete("/agenda/items/{itemID}", s.handleRemoveAgendaItem)
+	r.Post("/agenda/next", s.handleNextAgendaItem)
+
+	// Auto-cast a panel of agents for the topic
+	r.Post("/cast", s.handleCast)
+	r.Post("/cast/accept", s.handleAcceptCast)
+
+	// Prompt templates and language
+	r.Get("/prompts", s.handleGetPromptSets)
+	r.Get("/prompt", s.handleGetPromptSet)
+	r.Post("/prompt", s.handleSetPromptSet)
+	r.Get("/prompt/preview", s.handlePreviewPrompt)
+	r.Get("/preview/{agentID}", s.handlePreviewTurn)
+
+	// Human seats
+	r.Get("/human", s.handleGetHumanTurn)
+	r.Post("/human/reply", s.handleHumanReply)
+	r.Post("/human/skip", s.handleHumanSkip)
+	r.Post("/human/timeout", s.handleSetHumanTimeout)
+
+	// Branches forked from earlier messages
+	r.Get("/branches", s.handleListBranches)
+	r.Post("/branches", s.handleFork)
+	r.Post("/branches/{branchID}/switch", s.handleSwitchBranch)
+	r.Get("/branches/{branchID}/messages", s.handleGetBranchMessages)
+
+	// LLM moderator for free-form mode
+	r.Get("/moderator", s.handleGetModerator)
+	r.Post("/moderator", s.handleSetModerator)
+	r.Delete("/moderator", s.handleClearModerator)
+
+	// Judge scoring on a rubric
+	r.Get("/verdict", s.handleGetVerdict)
+	r.Post("/judge", s.handleJudge)
+
+	// Final and rolling summaries
+	r.Get("/summarizer", s.handleGetSummarizer)
+	r.Post("/summarizer", s.handleSetSummarizer)
+	r.Delete("/summarizer", s.handleClearSummarizer)
+	r.Get("/summaries", s.handleGetSummaries)
+	r.Post("/summary", s.handleSummarize)
+
+	// Playback of a debate's event log
+	r.Get("/playback", s.handleGetPlayback)
+	r.Post("/playback", s.handleStartPlayback)
+	r.Post("/playback/speed", s.handleSetPlaybackSpeed)
+	r.Post("/playback/stop", s.handleStopPlayback)
+
+	// Server-side autonomous runs
+	r.Get("/run", s.handleGetRunStatus)
+	r.Post("/run/start", s.handleStartRun)
+	r.Post("/run/pause", s.handlePauseRun)
+	r.Post("/run/resume", s.handleResumeRun)
+	r.Post("/run/stop", s.handleStopRun)
+}
+
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
 		if r.Method == "OPTIONS" {
@@ -163,15 +288,23 @@ func (s *Server) Run(addr string) error {
 // API Handlers
 
 func (s *Server) handleGetAgents(w http.ResponseWriter, r *http.Request) {
-	agents := s.manager.GetAgents()
+	sess := s.session(r)
+	agents := sess.Manager.GetAgents()
 	respondJSON(w, http.StatusOK, agents)
 }
 
 func (s *Server) handleGetStatus(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
 	status := map[string]interface{}{
-		"is_running": s.manager.IsRunning(),
-		"topic":      s.manager.GetTopic(),
-		"mode":       s.manager.GetMode(),
+		"is_running": sess.Manager.IsRunning(),
+		"topic":      sess.Manager.GetTopic(),
+		"mode":       sess.Manager.GetMode(),
+		"run":        sess.Manager.GetRunStatus(),
+		"format":     sess.Manager.GetFormatStatus(),
+		"agenda":     sess.Manager.GetAgenda(),
+		"ended":      sess.Manager.GetEnded(),
+		"branch":     sess.Manager.GetBranch(),
+		"prompt_set": sess.Manager.GetPromptSet(),
 	}
 	respondJSON(w, http.StatusOK, status)
 }
@@ -181,6 +314,7 @@ type startRequest struct {
 }
 
 func (s *Server) handleStartDebate(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
 	var req startRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "Invalid request body")
@@ -192,13 +326,13 @@ func (s *Server) handleStartDebate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.manager.Start(req.Topic); err != nil {
+	if err := sess.Manager.Start(req.Topic); err != nil {
 		respondError(w, http.StatusConflict, err.Error())
 		return
 	}
 
 	// Broadcast to all clients
-	s.hub.Broadcast(map[string]interface{}{
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
 		"type":  "debate_started",
 		"topic": req.Topic,
 	})
@@ -207,6 +341,7 @@ func (s *Server) handleStartDebate(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleContinueDebate(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
 	var req startRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "Invalid request body")
@@ -218,13 +353,13 @@ func (s *Server) handleContinueDebate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.manager.Continue(req.Topic); err != nil {
+	if err := sess.Manager.Continue(req.Topic); err != nil {
 		respondError(w, http.StatusConflict, err.Error())
 		return
 	}
 
 	// Broadcast topic change to all clients
-	s.hub.Broadcast(map[string]interface{}{
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
 		"type":      "topic_changed",
 		"topic":     req.Topic,
 		"continued": true,
@@ -234,10 +369,11 @@ func (s *Server) handleContinueDebate(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleStopDebate(w http.ResponseWriter, r *http.Request) {
-	s.manager.Stop()
+	sess := s.session(r)
+	sess.Manager.Stop()
 
 	// Broadcast to all clients
-	s.hub.Broadcast(map[string]interface{}{
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
 		"type": "debate_stopped",
 	})
 
@@ -245,7 +381,8 @@ func (s *Server) handleStopDebate(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) handleNextTurn(w http.ResponseWriter, r *http.Request) {
-	if !s.manager.IsRunning() {
+	sess := s.session(r)
+	if !sess.Manager.IsRunning() {
 		respondError(w, http.StatusBadRequest, "Debate is not running")
 		return
 	}
@@ -256,7 +393,7 @@ func (s *Server) handleNextTurn(w http.ResponseWriter, r *http.Request) {
 	// Start turn in goroutine
 	go func() {
 		defer close(streamCh)
-		if err := s.manager.NextTurn(streamCh); err != nil {
+		if err := sess.Manager.NextTurn(streamCh); err != nil {
 			log.Printf("Error in NextTurn: %v", err)
 		}
 	}()
@@ -264,32 +401,49 @@ func (s *Server) handleNextTurn(w http.ResponseWriter, r *http.Request) {
 	// Stream to WebSocket clients
 	go func() {
 		for msg := range streamCh {
-			s.hub.Broadcast(msg)
+			s.hub.BroadcastSession(sess.ID, msg)
 		}
 	}()
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "processing"})
 }
 
+type agentTurnRequest struct {
+	Audience []string `json:"audience"` // Agents a private turn is shown to (optional)
+}
+
 func (s *Server) handleAgentTurn(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
 	agentID := chi.URLParam(r, "agentID")
 	if agentID == "" {
 		respondError(w, http.StatusBadRequest, "Agent ID is required")
 		return
 	}
 
-	if !s.manager.IsRunning() {
+	if !sess.Manager.IsRunning() {
 		respondError(w, http.StatusBadRequest, "Debate is not running")
 		return
 	}
 
+	var req agentTurnRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+	if err := sess.Manager.CheckAudience(agentID, req.Audience); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	// Create channel for streaming
 	streamCh := make(chan debate.StreamMessage, 100)
 
 	// Start turn in goroutine
 	go func() {
 		defer close(streamCh)
-		if err := s.manager.TurnByAgent(agentID, streamCh); err != nil {
+		if err := sess.Manager.TurnByAgent(agentID, req.Audience, streamCh); err != nil {
 			log.Printf("Error in TurnByAgent: %v", err)
 		}
 	}()
@@ -297,23 +451,80 @@ func (s *Server) handleAgentTurn(w http.ResponseWriter, r *http.Request) {
 	// Stream to WebSocket clients
 	go func() {
 		for msg := range streamCh {
-			s.hub.Broadcast(msg)
+			s.hub.BroadcastSession(sess.ID, msg)
 		}
 	}()
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "processing", "agent_id": agentID})
 }
 
+type parallelRequest struct {
+	Critique bool `json:"critique"`
+}
+
+func (s *Server) handleParallelRound(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if !sess.Manager.IsRunning() {
+		respondError(w, http.StatusBadRequest, "Debate is not running")
+		return
+	}
+
+	var req parallelRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	streamCh := make(chan debate.StreamMessage, 100)
+
+	go func() {
+		defer close(streamCh)
+		if err := sess.Manager.ParallelRound(req.Critique, streamCh); err != nil {
+			log.Printf("Error in ParallelRound: %v", err)
+		}
+	}()
+
+	go func() {
+		for msg := range streamCh {
+			s.hub.BroadcastSession(sess.ID, msg)
+		}
+	}()
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "processing"})
+}
+
 func (s *Server) handleGetMessages(w http.ResponseWriter, r *http.Request) {
-	messages := s.manager.GetMessages()
+	sess := s.session(r)
+	messages := sess.Manager.GetMessages()
 	respondJSON(w, http.StatusOK, messages)
 }
 
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	content, err := sess.Manager.ExportBranchMarkdown(r.URL.Query().Get("branch"))
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=debate.md")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(content))
+}
+
 type modeRequest struct {
 	Mode string `json:"mode"`
+	debate.SelectorOptions
+}
+
+func (s *Server) handleGetModes(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, debate.Modes())
 }
 
 func (s *Server) handleSetMode(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
 	var req modeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "Invalid request body")
@@ -321,15 +532,18 @@ func (s *Server) handleSetMode(w http.ResponseWriter, r *http.Request) {
 	}
 
 	mode := debate.Mode(req.Mode)
-	if mode != debate.ModeRoundRobin && mode != debate.ModeFreeForm {
+	if !debate.IsValidMode(mode) {
 		respondError(w, http.StatusBadRequest, "Invalid mode")
 		return
 	}
 
-	s.manager.SetMode(mode)
+	if err := sess.Manager.SetMode(mode, req.SelectorOptions); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Broadcast mode change
-	s.hub.Broadcast(map[string]interface{}{
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
 		"type": "mode_changed",
 		"mode": mode,
 	})
@@ -337,19 +551,124 @@ func (s *Server) handleSetMode(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{"mode": string(mode)})
 }
 
+func (s *Server) handleGetTermination(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.session(r).Manager.GetTermination())
+}
+
+func (s *Server) handleSetTermination(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	var opts debate.TerminationOptions
+	if err := json.NewDecoder(r.Body).Decode(&opts); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := sess.Manager.SetTermination(opts); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sess.Manager.GetTermination())
+}
+
 func (s *Server) handleReset(w http.ResponseWriter, r *http.Request) {
-	s.manager.Reset()
+	sess := s.session(r)
+	sess.Manager.Reset()
 
 	// Broadcast reset
-	s.hub.Broadcast(map[string]interface{}{
+	s.hub.BroadcastSession(sess.ID, map[string]interface{}{
 		"type": "debate_reset",
 	})
 
 	respondJSON(w, http.StatusOK, map[string]string{"status": "reset"})
 }
 
+// runRequest overrides the saved run config; zero values fall back to it
+type runRequest struct {
+	Rounds         int  `json:"rounds"`
+	StopAfterTurns int  `json:"stop_after_turns"`
+	DelayMs        *int `json:"delay_ms"`
+	Parallel       bool `json:"parallel"`
+	Critique       bool `json:"critique"`
+}
+
+func (s *Server) handleStartRun(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	var req runRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	}
+
+	runCfg := s.storage.GetRunConfig()
+	opts := debate.RunOptions{
+		Rounds:         req.Rounds,
+		StopAfterTurns: req.StopAfterTurns,
+		Delay:          time.Duration(runCfg.TurnDelayMs) * time.Millisecond,
+		Parallel:       req.Parallel,
+		Critique:       req.Critique,
+	}
+	// Structured formats run through their phases unless the request sets limits
+	if opts.Rounds == 0 && opts.StopAfterTurns == 0 && sess.Manager.GetFormatStatus() == nil {
+		opts.Rounds = runCfg.Rounds
+		opts.StopAfterTurns = runCfg.StopAfterTurns
+	}
+	if req.DelayMs != nil {
+		opts.Delay = time.Duration(*req.DelayMs) * time.Millisecond
+	}
+
+	if err := sess.Manager.StartRun(opts); err != nil {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sess.Manager.GetRunStatus())
+}
+
+func (s *Server) handlePauseRun(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if err := sess.Manager.PauseRun(); err != nil {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, sess.Manager.GetRunStatus())
+}
+
+func (s *Server) handleResumeRun(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if err := sess.Manager.ResumeRun(); err != nil {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, sess.Manager.GetRunStatus())
+}
+
+func (s *Server) handleStopRun(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	if err := sess.Manager.StopRun(); err != nil {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"status": "stopping"})
+}
+
+func (s *Server) handleGetRunStatus(w http.ResponseWriter, r *http.Request) {
+	sess := s.session(r)
+	respondJSON(w, http.StatusOK, sess.Manager.GetRunStatus())
+}
+
 func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
-	ServeWs(s.hub, w, r)
+	sessionID := r.URL.Query().Get("session")
+	if sessionID == "" {
+		sessionID = debate.DefaultSessionID
+	}
+	if _, ok := s.sessions.Get(sessionID); !ok {
+		respondError(w, http.StatusNotFound, "Session not found")
+		return
+	}
+	ServeWs(s.hub, w, r, sessionID)
 }
 
 // Helper functions
@@ -476,6 +795,10 @@ func (s *Server) handleAddAgent(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "Agent ID and name are required")
 		return
 	}
+	if err := agentCfg.Visibility.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	if err := s.agentFuncs.Add(agentCfg); err != nil {
 		respondError(w, http.StatusBadRequest, err.Error())
@@ -512,6 +835,10 @@ func (s *Server) handleUpdateAgent(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
+	if err := agentCfg.Visibility.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// If API key is masked, get the original from config
 	if s.agentFuncs.GetConfigs != nil {
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/user/talk/internal/debate"
+)
+
+type sessionKey struct{}
+
+// sessionCtx resolves the {sessionID} URL param and stores the session in the request context
+func (s *Server) sessionCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID := chi.URLParam(r, "sessionID")
+		sess, ok := s.sessions.Get(sessionID)
+		if !ok {
+			respondError(w, http.StatusNotFound, "Session not found")
+			return
+		}
+		ctx := context.WithValue(r.Context(), sessionKey{}, sess)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// session returns the session for a request, falling back to the default
+// session for the legacy /api/debate routes
+func (s *Server) session(r *http.Request) *debate.Session {
+	if sess, ok := r.Context().Value(sessionKey{}).(*debate.Session); ok {
+		return sess
+	}
+	return s.sessions.Default()
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.sessions.List())
+}
+
+type sessionRequest struct {
+	Name     string   `json:"name"`
+	AgentIDs []string `json:"agent_ids"`
+	Mode     string   `json:"mode"`
+	Topic    string   `json:"topic"` // Optional, starts the debate right away
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var req sessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	sess, err := s.sessions.Create(req.Name, req.AgentIDs, debate.Mode(req.Mode))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Topic != "" {
+		if err := sess.Manager.Start(req.Topic); err != nil {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+	}
+
+	// Session list changes are global
+	s.hub.Broadcast(map[string]interface{}{
+		"type":       "session_created",
+		"session_id": sess.ID,
+	})
+
+	respondJSON(w, http.StatusCreated, sess.Info())
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, s.session(r).Info())
+}
+
+func (s *Server) handleUpdateSession(w http.ResponseWriter, r *http.Request) {
+	var req sessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	sess, err := s.sessions.Update(s.session(r).ID, req.Name, req.AgentIDs)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The manager broadcasts agents_updated with the diff
+	respondJSON(w, http.StatusOK, sess.Info())
+}
+
+type participantRequest struct {
+	AgentID string `json:"agent_id"`
+}
+
+// handleJoin adds a pool agent to the session's panel, even mid-debate
+func (s *Server) handleJoin(w http.ResponseWriter, r *http.Request) {
+	var req participantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	sess, err := s.sessions.Join(s.session(r).ID, req.AgentID)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, sess.Info())
+}
+
+// handleLeave removes an agent from the session's panel, even mid-debate
+func (s *Server) handleLeave(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.sessions.Leave(s.session(r).ID, chi.URLParam(r, "agentID"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, sess.Info())
+}
+
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := s.session(r).ID
+	if err := s.sessions.Delete(sessionID); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.hub.Broadcast(map[string]interface{}{
+		"type":       "session_deleted",
+		"session_id": sessionID,
+	})
+
+	respondJSON(w, http.StatusOK, map[string]string{"status": "deleted", "id": sessionID})
+}
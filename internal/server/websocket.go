@@ -20,8 +20,8 @@ const (
 	// Send pings to peer with this period (must be less than pongWait)
 	pingPeriod = (pongWait * 9) / 10
 
-	// Maximum message size allowed from peer
-	maxMessageSize = 512
+	// Maximum message size allowed from peer (large enough for a human seat's reply)
+	maxMessageSize = 64 * 1024
 )
 
 var upgrader = websocket.Upgrader{
@@ -34,25 +34,37 @@ var upgrader = websocket.Upgrader{
 
 // Client represents a WebSocket client
 type Client struct {
-	hub  *Hub
-	conn *websocket.Conn
-	send chan []byte
+	hub       *Hub
+	conn      *websocket.Conn
+	send      chan []byte
+	sessionID string // Debate session this client is subscribed to
+}
+
+// hubMessage is a serialized event, optionally scoped to one session
+type hubMessage struct {
+	sessionID string // Empty for global events (e.g. agents_updated)
+	data      []byte
 }
 
 // Hub maintains the set of active clients and broadcasts messages
 type Hub struct {
 	clients    map[*Client]bool
-	broadcast  chan []byte
+	broadcast  chan hubMessage
 	register   chan *Client
 	unregister chan *Client
+	handler    MessageHandler
+	observer   MessageHandler // Sees every session event as it is broadcast
 	mu         sync.RWMutex
 }
 
+// MessageHandler receives a message a client sent, with the client's session ID
+type MessageHandler func(sessionID string, data []byte)
+
 // NewHub creates a new Hub
 func NewHub() *Hub {
 	return &Hub{
 		clients:    make(map[*Client]bool),
-		broadcast:  make(chan []byte, 256),
+		broadcast:  make(chan hubMessage, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 	}
@@ -80,8 +92,11 @@ func (h *Hub) Run() {
 		case message := <-h.broadcast:
 			h.mu.RLock()
 			for client := range h.clients {
+				if message.sessionID != "" && client.sessionID != message.sessionID {
+					continue
+				}
 				select {
-				case client.send <- message:
+				case client.send <- message.data:
 				default:
 					h.mu.RUnlock()
 					h.mu.Lock()
@@ -96,14 +111,33 @@ func (h *Hub) Run() {
 	}
 }
 
+// SetHandler sets the handler for messages sent by clients; call before Run
+func (h *Hub) SetHandler(fn MessageHandler) {
+	h.handler = fn
+}
+
+// SetObserver sets a function called with every session-scoped event before
+// it is sent, in broadcast order; call before Run
+func (h *Hub) SetObserver(fn MessageHandler) {
+	h.observer = fn
+}
+
 // Broadcast sends a message to all connected clients
 func (h *Hub) Broadcast(data interface{}) {
+	h.BroadcastSession("", data)
+}
+
+// BroadcastSession sends a message to clients subscribed to the given session
+func (h *Hub) BroadcastSession(sessionID string, data interface{}) {
 	message, err := json.Marshal(data)
 	if err != nil {
 		log.Printf("Error marshaling broadcast message: %v", err)
 		return
 	}
-	h.broadcast <- message
+	if sessionID != "" && h.observer != nil {
+		h.observer(sessionID, message)
+	}
+	h.broadcast <- hubMessage{sessionID: sessionID, data: message}
 }
 
 // readPump pumps messages from the websocket connection to the hub
@@ -121,14 +155,16 @@ func (c *Client) readPump() {
 	})
 
 	for {
-		_, _, err := c.conn.ReadMessage()
+		_, message, err := c.conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("WebSocket error: %v", err)
 			}
 			break
 		}
-		// We don't process incoming messages from clients for now
+		if c.hub.handler != nil {
+			c.hub.handler(c.sessionID, message)
+		}
 	}
 }
 
@@ -165,8 +201,9 @@ func (c *Client) writePump() {
 	}
 }
 
-// ServeWs handles websocket requests from the peer
-func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request) {
+// ServeWs handles websocket requests from the peer.
+// Clients subscribe to a debate session with ?session=<id> (default session otherwise).
+func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request, sessionID string) {
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade error: %v", err)
@@ -174,9 +211,10 @@ func ServeWs(hub *Hub, w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := &Client{
-		hub:  hub,
-		conn: conn,
-		send: make(chan []byte, 256),
+		hub:       hub,
+		conn:      conn,
+		send:      make(chan []byte, 256),
+		sessionID: sessionID,
 	}
 
 	client.hub.register <- client
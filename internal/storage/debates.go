@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/talk/internal/debate"
+)
+
+// validDebateID guards file names against path traversal
+var validDebateID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// DebateSummary is a lightweight view of a saved debate (for listing)
+type DebateSummary struct {
+	ID           string    `json:"id"`
+	Topic        string    `json:"topic"`
+	Mode         string    `json:"mode"`
+	Agents       []string  `json:"agents"`
+	MessageCount int       `json:"message_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DebateStore persists debate snapshots as JSON files, one file per debate
+type DebateStore struct {
+	dir  string
+	mu   sync.RWMutex
+	logs map[string]*os.File // Open event logs, by debate ID
+}
+
+// NewDebateStore creates a store in dir, creating the directory if needed
+func NewDebateStore(dir string) (*DebateStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &DebateStore{dir: dir, logs: make(map[string]*os.File)}, nil
+}
+
+func (s *DebateStore) path(id string) (string, error) {
+	if !validDebateID.MatchString(id) {
+		return "", fmt.Errorf("invalid debate ID: %s", id)
+	}
+	return filepath.Join(s.dir, id+".json"), nil
+}
+
+// eventsPath is the debate's event log, one JSON event per line
+func (s *DebateStore) eventsPath(id string) (string, error) {
+	if !validDebateID.MatchString(id) {
+		return "", fmt.Errorf("invalid debate ID: %s", id)
+	}
+	return filepath.Join(s.dir, id+".events.jsonl"), nil
+}
+
+// SaveDebate writes a snapshot, replacing any previous version atomically
+func (s *DebateStore) SaveDebate(snap debate.Snapshot) error {
+	path, err := s.path(snap.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// GetDebate loads a saved debate by ID
+func (s *DebateStore) GetDebate(id string) (debate.Snapshot, error) {
+	var snap debate.Snapshot
+	path, err := s.path(id)
+	if err != nil {
+		return snap, err
+	}
+
+	s.mu.RLock()
+	data, err := os.ReadFile(path)
+	s.mu.RUnlock()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snap, fmt.Errorf("debate not found: %s", id)
+		}
+		return snap, err
+	}
+
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return snap, err
+	}
+	return snap, nil
+}
+
+// ListDebates returns summaries of all saved debates, most recently updated first
+func (s *DebateStore) ListDebates() ([]DebateSummary, error) {
+	s.mu.RLock()
+	entries, err := os.ReadDir(s.dir)
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]DebateSummary, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		snap, err := s.GetDebate(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue // Skip unreadable files rather than failing the whole list
+		}
+
+		names := make([]string, len(snap.Agents))
+		for i, a := range snap.Agents {
+			names[i] = a.Name
+		}
+		summaries = append(summaries, DebateSummary{
+			ID:           snap.ID,
+			Topic:        snap.Topic,
+			Mode:         string(snap.Mode),
+			Agents:       names,
+			MessageCount: len(snap.Messages),
+			CreatedAt:    snap.CreatedAt,
+			UpdatedAt:    snap.UpdatedAt,
+		})
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].UpdatedAt.After(summaries[j].UpdatedAt)
+	})
+	return summaries, nil
+}
+
+// DeleteDebate removes a saved debate
+func (s *DebateStore) DeleteDebate(id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("debate not found: %s", id)
+		}
+		return err
+	}
+	if events, err := s.eventsPath(id); err == nil {
+		s.closeLogLocked(id)
+		if err := os.Remove(events); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendEvent adds an event to the end of a debate's event log
+func (s *DebateStore) AppendEvent(id string, ev debate.Event) error {
+	path, err := s.eventsPath(id)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Events arrive once per streamed chunk, so the log stays open until
+	// CloseEvents
+	f := s.logs[id]
+	if f == nil {
+		if f, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600); err != nil {
+			return err
+		}
+		s.logs[id] = f
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		s.closeLogLocked(id)
+		return err
+	}
+	return nil
+}
+
+// CloseEvents closes a debate's event log if it is open; the next
+// AppendEvent reopens it
+func (s *DebateStore) CloseEvents(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeLogLocked(id)
+}
+
+// closeLogLocked closes an open event log; callers must hold s.mu
+func (s *DebateStore) closeLogLocked(id string) error {
+	f := s.logs[id]
+	if f == nil {
+		return nil
+	}
+	delete(s.logs, id)
+	return f.Close()
+}
+
+// LoadEvents reads a debate's event log; a debate without one has no events
+func (s *DebateStore) LoadEvents(id string) ([]debate.Event, error) {
+	path, err := s.eventsPath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []debate.Event{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	events := make([]debate.Event, 0)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024) // Restored snapshots can be large
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ev debate.Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, fmt.Errorf("event log %s line %d: %w", id, len(events)+1, err)
+		}
+		events = append(events, ev)
+	}
+	return events, scanner.Err()
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/user/talk/internal/agent"
+	"github.com/user/talk/internal/debate"
+	"github.com/user/talk/internal/provider"
+)
+
+func testEvent(seq int64, data string) debate.Event {
+	var head struct {
+		Type string `json:"type"`
+	}
+	json.Unmarshal([]byte(data), &head)
+	return debate.Event{Seq: seq, Time: time.Unix(seq, 0).UTC(), Type: head.Type, Data: json.RawMessage(data)}
+}
+
+func TestEventLogRoundTrip(t *testing.T) {
+	store, err := NewDebateStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []debate.Event{
+		testEvent(1, `{"type":"debate_created","debate_id":"d1","topic":"Thuế carbon"}`),
+		testEvent(2, `{"type":"start","agent_id":"a1","message_id":"msg_1"}`),
+	}
+	for _, ev := range want {
+		if err := store.AppendEvent("d1", ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A closed log is reopened and appended to
+	if err := store.CloseEvents("d1"); err != nil {
+		t.Fatal(err)
+	}
+	want = append(want, testEvent(3, `{"type":"end","agent_id":"a1","message_id":"msg_1","content":"xin chào"}`))
+	if err := store.AppendEvent("d1", want[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.LoadEvents("d1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded events differ\n got: %+v\nwant: %+v", got, want)
+	}
+
+	if events, err := store.LoadEvents("d2"); err != nil || len(events) != 0 {
+		t.Errorf("debate without a log: %v, %v; want no events", events, err)
+	}
+	if err := store.AppendEvent("../d1", want[0]); err == nil {
+		t.Error("AppendEvent accepted a path outside the store")
+	}
+}
+
+func TestDeleteDebateRemovesEventLog(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDebateStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveDebate(debate.Snapshot{ID: "d1", Topic: "t"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AppendEvent("d1", testEvent(1, `{"type":"debate_created","debate_id":"d1"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DeleteDebate("d1"); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := store.eventsPath("d1")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("event log still exists after delete: %v", err)
+	}
+	if events, _ := store.LoadEvents("d1"); len(events) != 0 {
+		t.Errorf("deleted debate still has %d events", len(events))
+	}
+}
+
+func TestSavedEventLogReplays(t *testing.T) {
+	store, err := NewDebateStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := []debate.Event{
+		testEvent(1, `{"type":"debate_created","debate_id":"d1","topic":"Thuế carbon","mode":"round_robin"}`),
+		testEvent(2, `{"type":"start","agent_id":"a1","agent_name":"Alpha","message_id":"msg_1"}`),
+		testEvent(3, `{"type":"chunk","agent_id":"a1","message_id":"msg_1","content":"xin "}`),
+		testEvent(4, `{"type":"end","agent_id":"a1","message_id":"msg_1","content":"xin chào"}`),
+		testEvent(5, `{"type":"topic_changed","topic":"Năng lượng"}`),
+	}
+	for _, ev := range events {
+		if err := store.AppendEvent("d1", ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	loaded, err := store.LoadEvents("d1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap, err := debate.ReplayEvents(loaded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.ID != "d1" || snap.Topic != "Năng lượng" || len(snap.Messages) != 2 || snap.Messages[0].Content != "xin chào" {
+		t.Errorf("replayed %s %q with messages %+v", snap.ID, snap.Topic, snap.Messages)
+	}
+}
+
+// countProvider numbers its replies
+type countProvider struct{ n *int }
+
+func (p countProvider) Name() string { return "count" }
+
+func (p countProvider) Chat(context.Context, []provider.Message, provider.Options) (<-chan provider.StreamChunk, error) {
+	*p.n++
+	ch := make(chan provider.StreamChunk, 2)
+	ch <- provider.StreamChunk{Content: fmt.Sprintf("lượt %d", *p.n)}
+	ch <- provider.StreamChunk{Done: true, FinishReason: "stop"}
+	close(ch)
+	return ch, nil
+}
+
+func TestSavedDebateResumes(t *testing.T) {
+	store, err := NewDebateStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	agents := func() []*agent.Agent {
+		p := countProvider{n: &n}
+		return []*agent.Agent{{ID: "a1", Name: "Alpha", Provider: p}, {ID: "a2", Name: "Beta", Provider: p}, {ID: "a3", Name: "Gamma", Provider: p}}
+	}
+	turn := func(m *debate.Manager) {
+		t.Helper()
+		if err := m.NextTurn(make(chan debate.StreamMessage, 10)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := debate.NewManager(agents())
+	m.SetRecorder(store)
+	if err := m.Start("Thuế carbon"); err != nil {
+		t.Fatal(err)
+	}
+	turn(m)
+	turn(m)
+	id := m.GetDebateID()
+
+	list, err := store.ListDebates()
+	if err != nil || len(list) != 1 || list[0].ID != id || list[0].MessageCount != 2 || list[0].Topic != "Thuế carbon" {
+		t.Fatalf("list = %+v (%v), want the debate with 2 messages", list, err)
+	}
+
+	// A new manager, as after a restart, picks up where the debate stopped
+	snap, err := store.GetDebate(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed := debate.NewManager(agents())
+	resumed.SetRecorder(store)
+	if err := resumed.Restore(snap); err != nil {
+		t.Fatal(err)
+	}
+	turn(resumed)
+	msgs := resumed.GetMessages()
+	if len(msgs) != 3 || msgs[2].ID != "msg_3" || msgs[2].AgentID != "a3" || msgs[2].ParentID != "msg_2" {
+		t.Fatalf("messages = %+v, want a3 continuing with msg_3", msgs)
+	}
+	if saved, _ := store.GetDebate(id); len(saved.Messages) != 3 {
+		t.Errorf("saved %d messages after resuming, want 3", len(saved.Messages))
+	}
+
+	if _, err := store.GetDebate("missing"); err == nil {
+		t.Error("loaded a debate that was never saved")
+	}
+	if err := store.SaveDebate(debate.Snapshot{ID: "../d1"}); err == nil {
+		t.Error("SaveDebate accepted a path outside the store")
+	}
+}
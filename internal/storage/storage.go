@@ -18,6 +18,7 @@ type RunConfig struct {
 	Temperature    float64 `json:"temperature"`
 	Rounds         int     `json:"rounds"`
 	StopAfterTurns int     `json:"stop_after_turns"`
+	TurnDelayMs    int     `json:"turn_delay_ms"` // Pause between turns in server-side runs
 }
 
 // Config holds all configuration
@@ -76,6 +77,7 @@ func DefaultConfig() Config {
 			Temperature:    0.7,
 			Rounds:         3,
 			StopAfterTurns: 0,
+			TurnDelayMs:    2000,
 		},
 	}
 }
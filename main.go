@@ -24,42 +24,50 @@ type Config struct {
 	Server struct {
 		Port string `yaml:"port"`
 	} `yaml:"server"`
-	Agents []AgentYAMLConfig `yaml:"agents"`
+	Language   string              `yaml:"language,omitempty"`    // Prompt set for debates ("vi", "en" or a custom set)
+	PromptsDir string              `yaml:"prompts_dir,omitempty"` // Directory of custom *.tmpl prompt sets
+	Hooks      []debate.HookConfig `yaml:"hooks,omitempty"`       // Turn pipeline hooks, in order; unset = clean_content only
+	Agents     []AgentYAMLConfig   `yaml:"agents"`
 }
 
 // AgentYAMLConfig represents agent configuration from YAML
 type AgentYAMLConfig struct {
-	ID               string  `yaml:"id"`
-	Name             string  `yaml:"name"`
-	Role             string  `yaml:"role"`
-	SystemPrompt     string  `yaml:"system_prompt"`
-	Provider         string  `yaml:"provider"`
-	Model            string  `yaml:"model"`
-	Color            string  `yaml:"color"`
-	APIKey           string  `yaml:"api_key"`
-	BaseURL          string  `yaml:"base_url"`
-	Temperature      float64 `yaml:"temperature,omitempty"`
-	MaxTokens        int     `yaml:"max_tokens,omitempty"`
-	TopP             float64 `yaml:"top_p,omitempty"`
-	TopK             int     `yaml:"top_k,omitempty"`
-	FrequencyPenalty float64 `yaml:"frequency_penalty,omitempty"`
-	PresencePenalty  float64 `yaml:"presence_penalty,omitempty"`
+	ID               string           `yaml:"id"`
+	Name             string           `yaml:"name"`
+	Role             string           `yaml:"role"`
+	Kind             string           `yaml:"kind,omitempty"` // "human" for a person's seat, model otherwise
+	SystemPrompt     string           `yaml:"system_prompt"`
+	Provider         string           `yaml:"provider"`
+	Model            string           `yaml:"model"`
+	Color            string           `yaml:"color"`
+	APIKey           string           `yaml:"api_key"`
+	BaseURL          string           `yaml:"base_url"`
+	Temperature      float64          `yaml:"temperature,omitempty"`
+	MaxTokens        int              `yaml:"max_tokens,omitempty"`
+	TopP             float64          `yaml:"top_p,omitempty"`
+	TopK             int              `yaml:"top_k,omitempty"`
+	FrequencyPenalty float64          `yaml:"frequency_penalty,omitempty"`
+	PresencePenalty  float64          `yaml:"presence_penalty,omitempty"`
+	Visibility       agent.Visibility `yaml:"visibility,omitempty"` // What of the transcript the agent sees
 }
 
 // Global state for reloading
 var (
-	globalStore   *storage.Storage
-	globalManager *debate.Manager
-	configPath    string
-	managerMu     sync.RWMutex
-	globalConfig  *Config
-	configMu      sync.RWMutex
+	globalStore    *storage.Storage
+	globalSessions *debate.Sessions
+	configPath     string
+	managerMu      sync.RWMutex
+	globalConfig   *Config
+	configMu       sync.RWMutex
+	language       string // Prompt language from -lang, overrides the config
 )
 
 func main() {
 	configPathFlag := flag.String("config", "config.yaml", "Path to config file")
 	port := flag.String("port", "8080", "Server port")
 	keysFile := flag.String("keys", "api_keys.json", "Path to API keys file")
+	dataDir := flag.String("data", "debates", "Directory for saved debate history")
+	flag.StringVar(&language, "lang", "", "Prompt language for debates (vi, en or a custom prompt set)")
 	flag.Parse()
 
 	configPath = *configPathFlag
@@ -80,11 +88,26 @@ func main() {
 		}
 	}
 
-	// Create debate manager
-	globalManager = debate.NewManager(agents)
+	setupPrompts()
+	setupHooks()
+
+	// Create debate sessions (the default session backs the legacy routes)
+	globalSessions = debate.NewSessions(agents)
+
+	// Persist debates so they survive restarts
+	debateStore, err := storage.NewDebateStore(*dataDir)
+	if err != nil {
+		log.Printf("Warning: Debate history disabled: %v", err)
+	} else {
+		log.Printf("Debate history directory: %s", *dataDir)
+		globalSessions.SetRecorder(debateStore)
+	}
 
 	// Create and start server with reload callback
-	srv := server.NewServer(globalManager, staticFS, globalStore, reloadAgents)
+	srv := server.NewServer(globalSessions, staticFS, globalStore, reloadAgents)
+	if debateStore != nil {
+		srv.SetDebateStore(debateStore)
+	}
 
 	// Set agent config management functions
 	srv.SetAgentFuncs(&server.AgentConfigFuncs{
@@ -96,6 +119,7 @@ func main() {
 					ID:               c.ID,
 					Name:             c.Name,
 					Role:             c.Role,
+					Kind:             c.Kind,
 					SystemPrompt:     c.SystemPrompt,
 					Provider:         c.Provider,
 					Model:            c.Model,
@@ -108,6 +132,7 @@ func main() {
 					TopK:             c.TopK,
 					FrequencyPenalty: c.FrequencyPenalty,
 					PresencePenalty:  c.PresencePenalty,
+					Visibility:       c.Visibility,
 				}
 			}
 			return result
@@ -117,6 +142,7 @@ func main() {
 				ID:               cfg.ID,
 				Name:             cfg.Name,
 				Role:             cfg.Role,
+				Kind:             cfg.Kind,
 				SystemPrompt:     cfg.SystemPrompt,
 				Provider:         cfg.Provider,
 				Model:            cfg.Model,
@@ -129,6 +155,7 @@ func main() {
 				TopK:             cfg.TopK,
 				FrequencyPenalty: cfg.FrequencyPenalty,
 				PresencePenalty:  cfg.PresencePenalty,
+				Visibility:       cfg.Visibility,
 			})
 		},
 		Update: func(id string, cfg server.AgentYAMLConfig) error {
@@ -136,6 +163,7 @@ func main() {
 				ID:               cfg.ID,
 				Name:             cfg.Name,
 				Role:             cfg.Role,
+				Kind:             cfg.Kind,
 				SystemPrompt:     cfg.SystemPrompt,
 				Provider:         cfg.Provider,
 				Model:            cfg.Model,
@@ -148,6 +176,7 @@ func main() {
 				TopK:             cfg.TopK,
 				FrequencyPenalty: cfg.FrequencyPenalty,
 				PresencePenalty:  cfg.PresencePenalty,
+				Visibility:       cfg.Visibility,
 			})
 		},
 		Delete: func(id string) error {
@@ -156,6 +185,27 @@ func main() {
 		Reorder: func(ids []string) error {
 			return ReorderAgents(ids)
 		},
+		Build: func(cfg server.AgentYAMLConfig) (*agent.Agent, error) {
+			return buildAgent(AgentYAMLConfig{
+				ID:               cfg.ID,
+				Name:             cfg.Name,
+				Role:             cfg.Role,
+				Kind:             cfg.Kind,
+				SystemPrompt:     cfg.SystemPrompt,
+				Provider:         cfg.Provider,
+				Model:            cfg.Model,
+				Color:            cfg.Color,
+				APIKey:           cfg.APIKey,
+				BaseURL:          cfg.BaseURL,
+				Temperature:      cfg.Temperature,
+				MaxTokens:        cfg.MaxTokens,
+				TopP:             cfg.TopP,
+				TopK:             cfg.TopK,
+				FrequencyPenalty: cfg.FrequencyPenalty,
+				PresencePenalty:  cfg.PresencePenalty,
+				Visibility:       cfg.Visibility,
+			})
+		},
 		Reload: reloadAgents,
 	})
 
@@ -183,8 +233,9 @@ func reloadAgents() error {
 		}
 	}
 
-	// Update the manager with new agents
-	globalManager.UpdateAgents(agents)
+	// Update every session with the new agents
+	globalSessions.UpdateAgents(agents)
+	setupHooks()
 	return nil
 }
 
@@ -224,58 +275,117 @@ func loadAgentsFromConfig(path string) ([]*agent.Agent, error) {
 
 	var agents []*agent.Agent
 	for _, ac := range config.Agents {
-		// Get API key from config, then storage, then environment
-		apiKey := ac.APIKey
-		if apiKey == "" {
-			apiKey = getAPIKey(ac.Provider)
+		a, err := buildAgent(ac)
+		if err != nil {
+			log.Printf("Warning: Failed to create agent %s: %v", ac.ID, err)
+			continue
 		}
+		agents = append(agents, a)
+	}
 
-		baseURL := ac.BaseURL
-		if ac.Provider == "ollama" && baseURL == "" {
-			baseURL = globalStore.GetOllamaURL()
-		}
+	return agents, nil
+}
+
+// buildAgent creates an agent from YAML config, resolving API key and base URL
+func buildAgent(ac AgentYAMLConfig) (*agent.Agent, error) {
+	// Get API key from config, then storage, then environment
+	apiKey := ac.APIKey
+	if apiKey == "" {
+		apiKey = getAPIKey(ac.Provider)
+	}
+
+	baseURL := ac.BaseURL
+	if ac.Provider == "ollama" && baseURL == "" {
+		baseURL = globalStore.GetOllamaURL()
+	}
 
-		cfg := agent.AgentConfig{
-			ID:               ac.ID,
-			Name:             ac.Name,
-			Role:             ac.Role,
-			SystemPrompt:     ac.SystemPrompt,
-			ProviderType:     ac.Provider,
+	cfg := agent.AgentConfig{
+		ID:               ac.ID,
+		Name:             ac.Name,
+		Role:             ac.Role,
+		Kind:             ac.Kind,
+		SystemPrompt:     ac.SystemPrompt,
+		ProviderType:     ac.Provider,
+		Model:            ac.Model,
+		Color:            ac.Color,
+		Temperature:      ac.Temperature,
+		MaxTokens:        ac.MaxTokens,
+		TopP:             ac.TopP,
+		TopK:             ac.TopK,
+		FrequencyPenalty: ac.FrequencyPenalty,
+		PresencePenalty:  ac.PresencePenalty,
+		Visibility:       ac.Visibility,
+		ProviderConfig: provider.Config{
+			Type:             ac.Provider,
+			APIKey:           apiKey,
 			Model:            ac.Model,
-			Color:            ac.Color,
+			BaseURL:          baseURL,
 			Temperature:      ac.Temperature,
 			MaxTokens:        ac.MaxTokens,
 			TopP:             ac.TopP,
 			TopK:             ac.TopK,
 			FrequencyPenalty: ac.FrequencyPenalty,
 			PresencePenalty:  ac.PresencePenalty,
-			ProviderConfig: provider.Config{
-				Type:             ac.Provider,
-				APIKey:           apiKey,
-				Model:            ac.Model,
-				BaseURL:          baseURL,
-				Temperature:      ac.Temperature,
-				MaxTokens:        ac.MaxTokens,
-				TopP:             ac.TopP,
-				TopK:             ac.TopK,
-				FrequencyPenalty: ac.FrequencyPenalty,
-				PresencePenalty:  ac.PresencePenalty,
-			},
-		}
+		},
+	}
 
-		a, err := agent.NewAgent(cfg)
-		if err != nil {
-			log.Printf("Warning: Failed to create agent %s: %v", ac.ID, err)
-			continue
+	return agent.NewAgent(cfg)
+}
+
+// setupPrompts loads custom prompt sets and picks the debate language
+func setupPrompts() {
+	configMu.RLock()
+	cfg := globalConfig
+	configMu.RUnlock()
+
+	if cfg != nil && cfg.PromptsDir != "" {
+		if err := debate.LoadPromptDir(cfg.PromptsDir); err != nil {
+			log.Printf("Warning: Failed to load prompts from %s: %v", cfg.PromptsDir, err)
 		}
-		agents = append(agents, a)
 	}
+	lang := promptLanguage()
+	if err := debate.SetDefaultPromptSet(lang); err != nil {
+		log.Printf("Warning: %v. Using %s.", err, debate.DefaultPromptSet)
+		return
+	}
+	log.Printf("Prompt language: %s", lang)
+}
 
-	return agents, nil
+// setupHooks builds the turn pipeline from the config's hooks list
+func setupHooks() {
+	configMu.RLock()
+	cfg := globalConfig
+	configMu.RUnlock()
+
+	hooks := []debate.HookConfig{{Name: "clean_content"}}
+	if cfg != nil && cfg.Hooks != nil {
+		hooks = cfg.Hooks
+	}
+	pipeline, err := debate.BuildPipeline(hooks)
+	if err != nil {
+		// At startup that is the built-in pipeline, on reload the last valid one
+		log.Printf("Warning: Invalid hooks config: %v. Keeping turn hooks: %v", err, debate.DefaultPipeline().Names())
+		return
+	}
+	debate.SetDefaultPipeline(pipeline)
+	log.Printf("Turn hooks: %v", pipeline.Names())
+}
+
+// promptLanguage returns the -lang flag, then the config's language, then the default
+func promptLanguage() string {
+	if language != "" {
+		return language
+	}
+	configMu.RLock()
+	defer configMu.RUnlock()
+	if globalConfig != nil && globalConfig.Language != "" {
+		return globalConfig.Language
+	}
+	return debate.DefaultPromptSet
 }
 
 func loadDefaultAgents() []*agent.Agent {
-	defaults := agent.DefaultAgents()
+	defaults := agent.DefaultAgentsFor(promptLanguage())
 	var agents []*agent.Agent
 
 	for _, cfg := range defaults {
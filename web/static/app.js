@@ -11,8 +11,14 @@ let selectedAgents = new Set(); // Agents selected for auto mode
 let autoAgentIndex = 0; // Current index for round-robin in auto mode
 let hiddenAgents = new Set(); // Agents hidden from the panel
 let editingAgentId = null; // Currently editing agent ID (null for new)
+let castProposals = []; // Panel proposed by the auto-cast endpoint
 let pendingTurn = false; // Flag to prevent double triggering
 let currentStreamingContent = ''; // Raw content being streamed for markdown
+let regeneratingMessage = null; // Message element being rewritten in place
+
+// Debate session (?session=<id> in the page URL, default session otherwise)
+const sessionId = new URLSearchParams(window.location.search).get('session');
+const debateApi = sessionId ? `/api/sessions/${encodeURIComponent(sessionId)}/debate` : '/api/debate';
 
 // DOM Elements
 const topicInput = document.getElementById('topicInput');
@@ -22,9 +28,30 @@ const stopBtn = document.getElementById('stopBtn');
 const autoBtn = document.getElementById('autoBtn');
 const nextAgentBtn = document.getElementById('nextAgentBtn');
 const exportMdBtn = document.getElementById('exportMdBtn');
+const judgeBtn = document.getElementById('judgeBtn');
+const summaryBtn = document.getElementById('summaryBtn');
+const parallelBtn = document.getElementById('parallelBtn');
+const critiqueCheckbox = document.getElementById('critiqueCheckbox');
 const importMdBtn = document.getElementById('importMdBtn');
 const importMdInput = document.getElementById('importMdInput');
 const modeSelect = document.getElementById('modeSelect');
+const formatSelect = document.getElementById('formatSelect');
+const interjectInput = document.getElementById('interjectInput');
+const interjectTo = document.getElementById('interjectTo');
+const interjectBtn = document.getElementById('interjectBtn');
+const audienceList = document.getElementById('audienceList');
+const showPrivateCheckbox = document.getElementById('showPrivateCheckbox');
+const branchSelect = document.getElementById('branchSelect');
+const promptSetSelect = document.getElementById('promptSetSelect');
+const playbackBtn = document.getElementById('playbackBtn');
+const playbackSpeed = document.getElementById('playbackSpeed');
+const agendaList = document.getElementById('agendaList');
+const agendaInput = document.getElementById('agendaInput');
+const agendaSummaryCheckbox = document.getElementById('agendaSummaryCheckbox');
+const agendaSaveBtn = document.getElementById('agendaSaveBtn');
+const agendaNextBtn = document.getElementById('agendaNextBtn');
+const joinAgentSelect = document.getElementById('joinAgentSelect');
+const joinAgentBtn = document.getElementById('joinAgentBtn');
 const messagesContainer = document.getElementById('messages');
 const agentsList = document.getElementById('agentsList');
 const statusIndicator = document.querySelector('.status-indicator');
@@ -36,7 +63,11 @@ let hasMessages = false;
 document.addEventListener('DOMContentLoaded', () => {
     connectWebSocket();
     loadAgents();
+    loadAgentConfigs();
     loadHiddenAgents();
+    loadBranches();
+    loadPromptSets();
+    loadAgenda();
     setupEventListeners();
     setupSidebarResize();
     setupAgentManager();
@@ -54,7 +85,7 @@ document.addEventListener('DOMContentLoaded', () => {
 // WebSocket Connection
 function connectWebSocket() {
     const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
-    const wsUrl = `${protocol}//${window.location.host}/ws`;
+    const wsUrl = `${protocol}//${window.location.host}/ws` + (sessionId ? `?session=${encodeURIComponent(sessionId)}` : '');
 
     ws = new WebSocket(wsUrl);
 
@@ -97,11 +128,19 @@ function connectWebSocket() {
 function handleWebSocketMessage(data) {
     console.log('WS Message:', data);
 
+    // Parallel rounds interleave several streams, tracked by message ID
+    if (data.parallel) {
+        handleParallelStream(data);
+        return;
+    }
+
     switch (data.type) {
         case 'debate_started':
             isDebateRunning = true;
             updateControls();
             clearWelcomeMessage();
+            loadBranches();
+            loadAgenda();
             break;
 
         case 'debate_stopped':
@@ -110,6 +149,19 @@ function handleWebSocketMessage(data) {
             updateControls();
             break;
 
+        case 'debate_ended':
+            isDebateRunning = false;
+            stopAutoMode();
+            updateControls();
+            addSystemMessage(`Thảo luận kết thúc: ${escapeHtml(data.detail)}`);
+            break;
+
+        case 'consensus_check': {
+            const agreed = data.votes.filter(v => !v.error && v.agree).map(v => escapeHtml(v.agent_name));
+            addSystemMessage(`Kiểm tra đồng thuận: ${data.agreed}/${data.valid} đồng ý${agreed.length ? ` (${agreed.join(', ')})` : ''}`);
+            break;
+        }
+
         case 'debate_reset':
             isDebateRunning = false;
             hasMessages = false;
@@ -121,24 +173,98 @@ function handleWebSocketMessage(data) {
                 </div>
             `;
             updateControls();
+            loadBranches();
+            loadAgenda();
+            break;
+
+        case 'debate_resumed':
+            isDebateRunning = true;
+            topicInput.value = data.topic;
+            modeSelect.value = data.mode;
+            loadMessages();
+            loadPromptSets();
+            loadAgenda();
             break;
 
         case 'mode_changed':
             modeSelect.value = data.mode;
             break;
 
+        case 'format_changed':
+            formatSelect.value = data.format ? data.format.format : '';
+            if (data.format) {
+                addSystemMessage(`Thể thức ${escapeHtml(data.format.name)}: ${escapeHtml(data.format.phase_name || '')}`);
+            }
+            break;
+
+        case 'prompt_set_changed':
+            promptSetSelect.value = data.set;
+            break;
+
+        case 'agenda_changed':
+            if (data.agenda && data.agenda.complete && !agendaComplete) {
+                addSystemMessage('Đã thảo luận hết các mục trong chương trình');
+                stopAutoMode();
+            }
+            renderAgenda(data.agenda);
+            break;
+
+        case 'branch_created':
+            loadBranches();
+            break;
+
+        case 'branch_switched':
+            loadMessages();
+            break;
+
+        case 'judging_started':
+            addSystemMessage(`Giám khảo ${escapeHtml(data.judge)} đang chấm điểm...`);
+            break;
+
+        case 'judging_failed':
+            addSystemMessage(`Chấm điểm thất bại: ${escapeHtml(data.error)}`);
+            break;
+
+        case 'verdict':
+            addSystemMessage(renderVerdict(data.verdict));
+            break;
+
+        case 'phase_changed':
+            if (data.format.complete) {
+                addSystemMessage(`Kết thúc thể thức ${escapeHtml(data.format.name)}`);
+            } else {
+                addSystemMessage(`Giai đoạn ${data.format.phase_index + 1}/${data.format.total_phases}: ${escapeHtml(data.format.phase_name)}`);
+            }
+            break;
+
         case 'topic_changed':
             isDebateRunning = true;
             updateControls();
             // Add system message about topic change
-            addSystemMessage(`Chuyển sang chủ đề mới: ${data.topic}`);
+            if (data.agenda_item) {
+                addSystemMessage(`Chuyển sang mục tiếp theo của chương trình: ${escapeHtml(data.topic)}`);
+            } else {
+                addSystemMessage(`Chuyển sang chủ đề mới: ${data.topic}`);
+            }
             break;
 
-        case 'start':
-            // Start of a new message
-            currentStreamingMessage = createMessage(data);
+        case 'start': {
+            // A start for a message already on screen is a regeneration: stream into it in place
+            const existing = messagesContainer.querySelector(`.message[data-message-id="${CSS.escape(data.message_id || '')}"]`);
+            if (existing) {
+                regeneratingMessage = existing;
+                currentStreamingMessage = existing;
+                currentStreamingContent = '';
+                existing.querySelector('.sources')?.remove();
+                const textEl = existing.querySelector('.text');
+                textEl.innerHTML = '';
+                textEl.classList.add('streaming');
+            } else {
+                currentStreamingMessage = createMessage(data);
+            }
             updateStatus('processing', `${data.agent_name} đang suy nghĩ...`);
             break;
+        }
 
         case 'chunk':
             // Streaming content
@@ -151,15 +277,27 @@ function handleWebSocketMessage(data) {
             // End of message
             if (currentStreamingMessage) {
                 finalizeMessage(currentStreamingMessage);
+                if (data.citations && data.citations.length > 0) {
+                    renderSources(currentStreamingMessage, data.citations);
+                }
+                if (data.meta) {
+                    renderMeta(currentStreamingMessage, data.meta);
+                }
                 currentStreamingMessage = null;
             }
             updateStatus('online', 'Sẵn sàng');
 
+            // A regeneration is not a new turn, so it doesn't drive auto mode
+            if (regeneratingMessage) {
+                regeneratingMessage = null;
+                break;
+            }
+
             // Check if we should stop after this message
             if (window.stopAfterCurrentMessage) {
                 window.stopAfterCurrentMessage = false;
                 // Now actually stop the debate
-                fetch('/api/debate/stop', { method: 'POST' }).then(() => {
+                fetch(`${debateApi}/stop`, { method: 'POST' }).then(() => {
                     isDebateRunning = false;
                     updateControls();
                 });
@@ -173,7 +311,99 @@ function handleWebSocketMessage(data) {
             }
             break;
 
+        case 'parallel_started':
+            updateStatus('processing', data.round === 'critique' ? 'Các agent đang phê bình chéo...' : 'Các agent đang cùng trả lời...');
+            break;
+
+        case 'parallel_finished':
+            updateStatus('online', 'Sẵn sàng');
+            break;
+
+        case 'summary_start':
+            summaryMessages[data.message_id] = { el: createSummaryMessage(data), content: '' };
+            break;
+
+        case 'summary_chunk': {
+            const summary = summaryMessages[data.message_id];
+            if (summary) {
+                summary.content += data.content;
+                summary.el.querySelector('.text').innerHTML = renderMarkdown(summary.content);
+                scrollToBottom();
+            }
+            break;
+        }
+
+        case 'summary_end':
+            if (summaryMessages[data.message_id]) {
+                summaryMessages[data.message_id].el.querySelector('.text').classList.remove('streaming');
+                delete summaryMessages[data.message_id];
+            }
+            break;
+
+        case 'summary_error':
+            addSystemMessage(`⚠️ Tóm tắt thất bại: ${escapeHtml(data.error)}`);
+            if (summaryMessages[data.message_id]) {
+                summaryMessages[data.message_id].el.remove();
+                delete summaryMessages[data.message_id];
+            }
+            break;
+
+        case 'human_turn':
+            showHumanTurn(data.turn);
+            break;
+
+        case 'human_turn_ended':
+            document.getElementById(`human-turn-${data.message_id}`)?.remove();
+            if (data.outcome !== 'answered') {
+                addSystemMessage(`${escapeHtml(agentName(data.agent_id))} ${data.outcome === 'timeout' ? 'hết thời gian, bỏ qua lượt' : 'bỏ lượt'}`);
+            }
+            break;
+
+        case 'interjection':
+            clearWelcomeMessage();
+            renderStoredMessage(data.message);
+            scrollToBottom();
+            break;
+
+        case 'retry':
+            // A hook rejected the reply and the agent is rewriting it
+            if (currentStreamingMessage) {
+                currentStreamingContent = '';
+                currentStreamingMessage.querySelector('.text').innerHTML = '';
+            }
+            addSystemMessage(`🔁 ${escapeHtml(agentName(data.agent_id))} viết lại câu trả lời: ${escapeHtml(data.content)}`);
+            break;
+
+        case 'turn_vetoed':
+            addSystemMessage(`⏭️ Bỏ lượt ${escapeHtml(data.agent_name)}: ${escapeHtml(data.reason)}`);
+            break;
+
+        case 'turn_rejected': {
+            const rejected = messagesContainer.querySelector(`.message[data-message-id="${CSS.escape(data.message_id)}"]`);
+            if (data.message) {
+                updateStoredMessage(data.message);
+            } else {
+                rejected?.remove();
+            }
+            addSystemMessage(`🚫 Câu trả lời của ${escapeHtml(data.agent_name)} bị từ chối: ${escapeHtml(data.reason)}`);
+            break;
+        }
+
+        case 'message_updated':
+            if (data.action === 'deleted') {
+                messagesContainer.querySelector(`.message[data-message-id="${CSS.escape(data.message_id)}"]`)?.remove();
+            } else if (data.message) {
+                updateStoredMessage(data.message);
+            }
+            break;
+
         case 'error':
+            if (regeneratingMessage) {
+                // Put the previous version back
+                regeneratingMessage = null;
+                currentStreamingMessage = null;
+                loadMessages();
+            }
             console.error('Debate error:', data.error);
             // Parse and display user-friendly error message
             let errorMsg = data.error || 'Lỗi không xác định';
@@ -192,7 +422,67 @@ function handleWebSocketMessage(data) {
             }
             break;
 
+        case 'moderator_choice':
+            if (data.fallback) {
+                console.warn('Moderator fallback:', data.error);
+            } else {
+                addSystemMessage(`Điều phối: mời ${escapeHtml(data.agent_name)} — ${escapeHtml(data.reason)}`);
+            }
+            break;
+
+        case 'bids':
+            if (!data.fallback) {
+                const bid = data.bids.find(b => b.agent_id === data.agent_id);
+                addSystemMessage(`Đấu giá lượt nói: ${escapeHtml(data.agent_name)} (${bid ? bid.score : '?'}/10)`);
+            }
+            break;
+
+        case 'run_started':
+            addSystemMessage(`Bắt đầu chạy tự động trên server (${data.total_turns} lượt)`);
+            break;
+
+        case 'run_progress':
+            updateStatus('online', `Tự động: lượt ${data.status.turn}/${data.status.total_turns}`);
+            break;
+
+        case 'run_paused':
+            updateStatus('online', 'Tạm dừng chạy tự động');
+            break;
+
+        case 'run_finished':
+            addSystemMessage(`Kết thúc chạy tự động (${data.reason}): ${data.status.turn}/${data.status.total_turns} lượt`);
+            break;
+
+        case 'playback_started':
+            isPlayingBack = true;
+            playbackBtn.textContent = 'Dừng phát';
+            messagesContainer.innerHTML = '';
+            updateStatus('processing', 'Đang phát lại...');
+            break;
+
+        case 'playback':
+            handlePlaybackEvent(data.event);
+            updateStatus('processing', `Đang phát lại: sự kiện ${data.seq}`);
+            break;
+
+        case 'playback_speed':
+            playbackSpeed.value = String(data.playback.speed);
+            break;
+
+        case 'playback_finished':
+            isPlayingBack = false;
+            playbackBtn.textContent = 'Phát lại';
+            updateStatus('online', 'Sẵn sàng');
+            addSystemMessage(data.reason === 'stopped' ? 'Đã dừng phát lại' : 'Phát lại xong');
+            break;
+
         case 'agents_updated':
+            // Join and leave notes from a running debate
+            if (data.messages && data.messages.length > 0) {
+                clearWelcomeMessage();
+                data.messages.forEach(renderStoredMessage);
+                scrollToBottom();
+            }
             // Reload agents when they are updated from another client or the modal
             loadAgents();
             loadAgentConfigs();
@@ -203,9 +493,13 @@ function handleWebSocketMessage(data) {
 // Load Agents
 async function loadAgents() {
     try {
-        const response = await fetch('/api/agents');
+        const response = await fetch(`${debateApi}/agents`);
         agents = await response.json();
         renderAgents();
+        interjectTo.innerHTML = '<option value="">Tất cả</option>' + agents.map(a =>
+            `<option value="${escapeHtml(a.id)}">${escapeHtml(a.name)}</option>`
+        ).join('');
+        renderAudienceOptions();
     } catch (error) {
         console.error('Failed to load agents:', error);
     }
@@ -249,9 +543,15 @@ function renderAgents() {
                     <p>${agent.role}</p>
                 </div>
             </button>
+            <button class="agent-leave-btn" data-agent-id="${agent.id}" title="Rời cuộc thảo luận">&times;</button>
         </div>
     `}).join('');
 
+    document.querySelectorAll('.agent-leave-btn').forEach(btn => {
+        btn.addEventListener('click', () => leaveAgent(btn.dataset.agentId));
+    });
+    renderJoinOptions();
+
     // Add click handlers to agent buttons
     document.querySelectorAll('.agent-btn').forEach(btn => {
         btn.addEventListener('click', () => {
@@ -278,6 +578,43 @@ function renderAgents() {
     setupDragDrop();
 }
 
+// Offer config agents that are not on the panel yet
+function renderJoinOptions() {
+    const onPanel = new Set(agents.map(a => a.id));
+    const candidates = (Array.isArray(agentConfigs) ? agentConfigs : []).filter(c => !onPanel.has(c.id));
+    joinAgentSelect.innerHTML = candidates.map(c =>
+        `<option value="${escapeHtml(c.id)}">${escapeHtml(c.name)}</option>`
+    ).join('');
+    joinAgentBtn.disabled = candidates.length === 0;
+}
+
+async function joinAgent() {
+    if (!joinAgentSelect.value) return;
+    await updateParticipants(`${debateApi}/participants`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ agent_id: joinAgentSelect.value })
+    });
+}
+
+async function leaveAgent(agentId) {
+    await updateParticipants(`${debateApi}/participants/${encodeURIComponent(agentId)}`, { method: 'DELETE' });
+}
+
+// The server broadcasts agents_updated, which reloads the panel
+async function updateParticipants(url, options) {
+    try {
+        const response = await fetch(url, options);
+        if (!response.ok) {
+            const data = await response.json();
+            alert('Lỗi: ' + (data.error || 'Không thể đổi thành viên'));
+        }
+    } catch (error) {
+        console.error('Failed to update participants:', error);
+        alert('Lỗi kết nối server');
+    }
+}
+
 // Drag and Drop functionality
 let draggedItem = null;
 let draggedIndex = null;
@@ -350,9 +687,30 @@ function setupEventListeners() {
     autoBtn.addEventListener('click', toggleAutoMode);
     nextAgentBtn.addEventListener('click', manualNextTurn);
     exportMdBtn.addEventListener('click', exportToMd);
+    judgeBtn.addEventListener('click', requestJudging);
+    summaryBtn.addEventListener('click', requestSummary);
+    parallelBtn.addEventListener('click', triggerParallelRound);
+    interjectBtn.addEventListener('click', interject);
+    showPrivateCheckbox.addEventListener('change', () => {
+        messagesContainer.classList.toggle('hide-private', !showPrivateCheckbox.checked);
+    });
+    joinAgentBtn.addEventListener('click', joinAgent);
+    interjectInput.addEventListener('keydown', (e) => {
+        if (e.key === 'Enter' && (e.ctrlKey || e.metaKey)) {
+            e.preventDefault();
+            interject();
+        }
+    });
     importMdBtn.addEventListener('click', () => importMdInput.click());
     importMdInput.addEventListener('change', importFromMd);
     modeSelect.addEventListener('change', changeMode);
+    formatSelect.addEventListener('change', changeFormat);
+    branchSelect.addEventListener('change', () => switchBranch(branchSelect.value));
+    promptSetSelect.addEventListener('change', changePromptSet);
+    playbackBtn.addEventListener('click', togglePlayback);
+    playbackSpeed.addEventListener('change', changePlaybackSpeed);
+    agendaSaveBtn.addEventListener('click', saveAgenda);
+    agendaNextBtn.addEventListener('click', nextAgendaItem);
 
     topicInput.addEventListener('keydown', (e) => {
         // Ctrl+Enter or Cmd+Enter to start/continue debate
@@ -376,7 +734,7 @@ async function startDebate() {
     }
 
     try {
-        const response = await fetch('/api/debate/start', {
+        const response = await fetch(`${debateApi}/start`, {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ topic })
@@ -404,7 +762,7 @@ async function continueDebate() {
     }
 
     try {
-        const response = await fetch('/api/debate/continue', {
+        const response = await fetch(`${debateApi}/continue`, {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ topic })
@@ -436,7 +794,7 @@ async function stopDebate() {
             window.stopAfterCurrentMessage = true;
         } else {
             // No active message, stop immediately
-            await fetch('/api/debate/stop', { method: 'POST' });
+            await fetch(`${debateApi}/stop`, { method: 'POST' });
             isDebateRunning = false;
             updateControls();
         }
@@ -465,35 +823,71 @@ async function triggerNextTurn() {
         autoAgentIndex++;
 
         try {
-            await fetch(`/api/debate/agent/${nextAgent.id}`, { method: 'POST' });
+            await fetch(`${debateApi}/agent/${nextAgent.id}`, { method: 'POST' });
         } catch (error) {
             console.error('Failed to trigger agent turn:', error);
         }
     } else {
         try {
-            await fetch('/api/debate/next', { method: 'POST' });
+            await fetch(`${debateApi}/next`, { method: 'POST' });
         } catch (error) {
             console.error('Failed to trigger next turn:', error);
         }
     }
 }
 
+// A turn triggered by hand is private when agents are picked under "Kênh riêng"
 async function triggerAgentTurn(agentId) {
     if (!isDebateRunning) return;
 
+    const audience = selectedAudience();
     try {
-        await fetch(`/api/debate/agent/${agentId}`, { method: 'POST' });
+        const response = await fetch(`${debateApi}/agent/${agentId}`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ audience })
+        });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể bắt đầu lượt nói');
+        }
     } catch (error) {
         console.error('Failed to trigger agent turn:', error);
     }
 }
 
+// Agent checkboxes for private interjections and turns, keeping the current picks
+function renderAudienceOptions() {
+    const picked = new Set(selectedAudience());
+    audienceList.innerHTML = agents.map(a => `
+        <label class="audience-option" style="color: ${a.color}">
+            <input type="checkbox" value="${escapeHtml(a.id)}" ${picked.has(a.id) ? 'checked' : ''}> ${escapeHtml(a.name)}
+        </label>
+    `).join('');
+}
+
+function selectedAudience() {
+    return Array.from(audienceList.querySelectorAll('input:checked')).map(input => input.value);
+}
+
+// Marks a private message with its audience ("🔒 Riêng: A, B")
+function renderAudience(messageEl, audience) {
+    messageEl.querySelector('.private-badge')?.remove();
+    const isPrivate = Array.isArray(audience) && audience.length > 0;
+    messageEl.classList.toggle('private', isPrivate);
+    if (!isPrivate) return;
+    const badge = document.createElement('span');
+    badge.className = 'private-badge';
+    badge.textContent = `🔒 Riêng: ${audience.map(agentName).join(', ')}`;
+    messageEl.querySelector('.name').after(badge);
+}
+
 // Manual trigger for next agent turn (non-auto mode)
 async function manualNextTurn() {
     if (!isDebateRunning) return;
 
     try {
-        await fetch('/api/debate/next', { method: 'POST' });
+        await fetch(`${debateApi}/next`, { method: 'POST' });
     } catch (error) {
         console.error('Failed to trigger next turn:', error);
     }
@@ -501,7 +895,7 @@ async function manualNextTurn() {
 
 async function changeMode() {
     try {
-        await fetch('/api/debate/mode', {
+        await fetch(`${debateApi}/mode`, {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
             body: JSON.stringify({ mode: modeSelect.value })
@@ -511,6 +905,360 @@ async function changeMode() {
     }
 }
 
+async function changeFormat() {
+    try {
+        const response = formatSelect.value
+            ? await fetch(`${debateApi}/format`, {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ format: formatSelect.value })
+            })
+            : await fetch(`${debateApi}/format`, { method: 'DELETE' });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể đổi thể thức');
+        }
+    } catch (error) {
+        console.error('Failed to change format:', error);
+    }
+}
+
+// Playback re-renders a logged debate without touching the debate controls
+let isPlayingBack = false;
+
+// Logged events that only change what is on screen, shown as they were live
+const playbackDisplayEvents = new Set([
+    'interjection', 'message_updated', 'turn_vetoed', 'turn_rejected', 'phase_changed',
+    'verdict', 'consensus_check', 'moderator_choice', 'bids', 'human_turn_ended',
+    'summary_start', 'summary_chunk', 'summary_end', 'summary_error'
+]);
+
+function handlePlaybackEvent(ev) {
+    switch (ev.type) {
+        case 'debate_reset':
+            addSystemMessage('Phiên thảo luận đã được đặt lại');
+            break;
+
+        case 'debate_created':
+        case 'debate_started':
+            messagesContainer.innerHTML = '';
+            if (ev.topic) {
+                addSystemMessage(`Chủ đề: ${escapeHtml(ev.topic)}`);
+            }
+            break;
+
+        case 'debate_restored':
+            messagesContainer.innerHTML = '';
+            (ev.snapshot.messages || []).forEach(renderStoredMessage);
+            scrollToBottom();
+            break;
+
+        case 'topic_changed':
+            addSystemMessage(`Chuyển sang chủ đề mới: ${escapeHtml(ev.topic)}`);
+            break;
+
+        case 'start': {
+            // A start for a message already on screen is a regeneration
+            const existing = messagesContainer.querySelector(`.message[data-message-id="${CSS.escape(ev.message_id || '')}"]`);
+            if (existing) {
+                existing.querySelector('.sources')?.remove();
+                existing.querySelector('.text').innerHTML = '';
+            }
+            parallelStreams[ev.message_id] = { el: existing || createMessage(ev), content: '' };
+            break;
+        }
+
+        case 'retry':
+            if (parallelStreams[ev.message_id]) {
+                parallelStreams[ev.message_id].content = '';
+                parallelStreams[ev.message_id].el.querySelector('.text').innerHTML = '';
+            }
+            addSystemMessage(`🔁 ${escapeHtml(agentName(ev.agent_id))} viết lại câu trả lời: ${escapeHtml(ev.content)}`);
+            break;
+
+        case 'chunk':
+        case 'end':
+        case 'error':
+            handleParallelStream(ev);
+            break;
+
+        default:
+            if (playbackDisplayEvents.has(ev.type)) {
+                handleWebSocketMessage(ev);
+            }
+    }
+}
+
+async function togglePlayback() {
+    try {
+        const response = isPlayingBack
+            ? await fetch(`${debateApi}/playback/stop`, { method: 'POST' })
+            : await fetch(`${debateApi}/playback`, {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ speed: parseFloat(playbackSpeed.value) })
+            });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể phát lại');
+        }
+    } catch (error) {
+        console.error('Failed to toggle playback:', error);
+    }
+}
+
+async function changePlaybackSpeed() {
+    if (!isPlayingBack) return;
+    try {
+        await fetch(`${debateApi}/playback/speed`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ speed: parseFloat(playbackSpeed.value) })
+        });
+    } catch (error) {
+        console.error('Failed to change playback speed:', error);
+    }
+}
+
+const promptSetNames = { vi: 'Tiếng Việt', en: 'English' };
+
+async function loadPromptSets() {
+    try {
+        const { sets } = await (await fetch(`${debateApi}/prompts`)).json();
+        const { set } = await (await fetch(`${debateApi}/prompt`)).json();
+        promptSetSelect.innerHTML = sets.map(s =>
+            `<option value="${escapeHtml(s.id)}">${escapeHtml(promptSetNames[s.id] || s.id)}</option>`
+        ).join('');
+        promptSetSelect.value = set;
+    } catch (error) {
+        console.error('Failed to load prompt sets:', error);
+    }
+}
+
+async function changePromptSet() {
+    try {
+        const response = await fetch(`${debateApi}/prompt`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ set: promptSetSelect.value })
+        });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể đổi ngôn ngữ prompt');
+        }
+    } catch (error) {
+        console.error('Failed to change prompt set:', error);
+    }
+}
+
+function agentName(agentId) {
+    const agent = agents.find(a => a.id === agentId);
+    return agent ? agent.name : agentId;
+}
+
+// A human seat's turn: the debate waits for this reply (sent over the WebSocket)
+function showHumanTurn(turn) {
+    clearWelcomeMessage();
+    const el = document.createElement('div');
+    el.className = 'message system-message human-turn';
+    el.id = `human-turn-${turn.message_id}`;
+    el.innerHTML = `
+        <div class="avatar" style="background: #888888">?</div>
+        <div class="content">
+            <div class="header">
+                <span class="name">Đến lượt ${escapeHtml(turn.agent_name)}</span>
+                <span class="time">hạn ${new Date(turn.deadline).toLocaleTimeString('vi-VN')}</span>
+            </div>
+            <textarea class="edit-input" rows="4" placeholder="Nhập câu trả lời của bạn..."></textarea>
+            <div class="edit-actions">
+                <button class="btn btn-primary btn-sm send-human">Gửi</button>
+                <button class="btn btn-outline btn-sm skip-human">Bỏ lượt</button>
+            </div>
+        </div>
+    `;
+    const input = el.querySelector('textarea');
+    const send = (type, content) => {
+        if (ws && ws.readyState === WebSocket.OPEN) {
+            ws.send(JSON.stringify({ type, agent_id: turn.agent_id, content }));
+        }
+    };
+    el.querySelector('.send-human').addEventListener('click', () => {
+        if (input.value.trim()) send('human_reply', input.value);
+    });
+    el.querySelector('.skip-human').addEventListener('click', () => send('human_skip'));
+    input.addEventListener('keydown', (e) => {
+        if (e.key === 'Enter' && (e.ctrlKey || e.metaKey) && input.value.trim()) {
+            e.preventDefault();
+            send('human_reply', input.value);
+        }
+    });
+
+    messagesContainer.appendChild(el);
+    updateStatus('processing', `Đang chờ ${turn.agent_name}...`);
+    forceScrollToBottom();
+    input.focus();
+}
+
+// Agenda: sub-topics opened in order, each with a turn or time budget
+let agendaComplete = false;
+
+async function loadAgenda() {
+    try {
+        const { agenda } = await (await fetch(`${debateApi}/agenda`)).json();
+        renderAgenda(agenda);
+    } catch (error) {
+        console.error('Failed to load agenda:', error);
+    }
+}
+
+function renderAgenda(agenda) {
+    agendaComplete = !!(agenda && agenda.complete);
+    agendaNextBtn.disabled = !isDebateRunning || !agenda || agenda.complete;
+    if (!agenda) {
+        agendaList.innerHTML = '';
+        return;
+    }
+    agendaList.innerHTML = agenda.items.map((item, i) => {
+        const mark = item.state === 'done' ? '✓' : item.state === 'open' ? '▶' : `${i + 1}.`;
+        const budget = [];
+        if (item.turns) budget.push(`${item.turns_taken}/${item.turns} lượt`);
+        if (item.seconds) budget.push(`${Math.round(item.seconds / 60 * 10) / 10} phút`);
+        if (item.state === 'open' && agenda.seconds_left) budget.push(`còn ${Math.ceil(agenda.seconds_left / 60)} phút`);
+        return `<div class="agenda-item ${item.state}">${mark} ${escapeHtml(item.topic)}${budget.length ? ` <small>(${budget.join(', ')})</small>` : ''}</div>`;
+    }).join('');
+}
+
+// Parse "topic | turns | minutes" lines into agenda items
+function parseAgenda(text) {
+    return text.split('\n').map(line => line.trim()).filter(Boolean).map(line => {
+        const [topic, turns, minutes] = line.split('|').map(part => part.trim());
+        return {
+            topic,
+            turns: parseInt(turns, 10) || 0,
+            seconds: Math.round((parseFloat(minutes) || 0) * 60),
+            summarize: agendaSummaryCheckbox.checked
+        };
+    });
+}
+
+async function saveAgenda() {
+    try {
+        const response = await fetch(`${debateApi}/agenda`, {
+            method: 'PUT',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ items: parseAgenda(agendaInput.value) })
+        });
+        const data = await response.json();
+        if (!response.ok) {
+            alert(data.error || 'Không thể lưu chương trình');
+            return;
+        }
+        agendaInput.value = '';
+    } catch (error) {
+        console.error('Failed to save agenda:', error);
+    }
+}
+
+async function nextAgendaItem() {
+    try {
+        const response = await fetch(`${debateApi}/agenda/next`, { method: 'POST' });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể chuyển mục');
+        }
+    } catch (error) {
+        console.error('Failed to advance agenda:', error);
+    }
+}
+
+async function interject() {
+    const content = interjectInput.value.trim();
+    if (!content || !isDebateRunning) return;
+
+    try {
+        const response = await fetch(`${debateApi}/interject`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ content, to: interjectTo.value, audience: selectedAudience() })
+        });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể chen lời');
+            return;
+        }
+        interjectInput.value = '';
+    } catch (error) {
+        console.error('Failed to interject:', error);
+    }
+}
+
+async function triggerParallelRound() {
+    if (!isDebateRunning) return;
+
+    try {
+        const response = await fetch(`${debateApi}/parallel`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ critique: critiqueCheckbox.checked })
+        });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể chạy vòng song song');
+        }
+    } catch (error) {
+        console.error('Failed to trigger parallel round:', error);
+    }
+}
+
+async function requestSummary() {
+    try {
+        const response = await fetch(`${debateApi}/summary`, { method: 'POST' });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể tóm tắt');
+        }
+    } catch (error) {
+        console.error('Failed to request summary:', error);
+    }
+}
+
+async function requestJudging() {
+    const agentId = prompt('ID agent làm giám khảo (nên là agent không tham gia tranh luận):', 'tong_hop');
+    if (!agentId) return;
+
+    try {
+        const response = await fetch(`${debateApi}/judge`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ agent_id: agentId.trim() })
+        });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể chấm điểm');
+        }
+    } catch (error) {
+        console.error('Failed to request judging:', error);
+    }
+}
+
+// renderVerdict renders the judge's scores as an HTML table
+function renderVerdict(verdict) {
+    const header = verdict.rubric.map(c => `<th>${escapeHtml(c.name)}</th>`).join('');
+    const rows = verdict.scores.map(s => `
+        <tr>
+            <td>${escapeHtml(s.agent_name)}</td>
+            ${verdict.rubric.map(c => `<td>${(s.scores[c.id] ?? 0).toFixed(1)}</td>`).join('')}
+            <td><strong>${s.total.toFixed(1)}</strong></td>
+        </tr>`).join('');
+
+    return `
+        <div class="verdict">
+            <div>Phán quyết của ${escapeHtml(verdict.judge)} — thắng: <strong>${escapeHtml(verdict.winner_name)}</strong></div>
+            <table><tr><th></th>${header}<th>Tổng</th></tr>${rows}</table>
+            <div>${escapeHtml(verdict.rationale)}</div>
+        </div>`;
+}
+
 // Auto Mode
 function toggleAutoMode() {
     if (isAutoMode) {
@@ -546,6 +1294,9 @@ function updateControls() {
     stopBtn.disabled = !isDebateRunning;
     autoBtn.disabled = !isDebateRunning;
     nextAgentBtn.disabled = !isDebateRunning;
+    parallelBtn.disabled = !isDebateRunning;
+    interjectBtn.disabled = !isDebateRunning;
+    agendaNextBtn.disabled = !isDebateRunning || agendaList.childElementCount === 0 || agendaComplete;
     topicInput.disabled = isDebateRunning;
 
     // Re-render agents to update their disabled state
@@ -581,12 +1332,24 @@ function createMessage(data) {
         <div class="content" style="border-left-color: ${agent.color}">
             <div class="header">
                 <span class="name" style="color: ${agent.color}">${agent.name}</span>
-                <span class="time">${new Date().toLocaleTimeString('vi-VN')}</span>
+                <span>
+                    <span class="time">${new Date().toLocaleTimeString('vi-VN')}</span>
+                    <button class="msg-action edit-btn" title="Sửa nội dung">✎</button>
+                    <button class="msg-action regenerate-btn" title="Tạo lại">↻</button>
+                    <button class="msg-action delete-btn" title="Xóa tin nhắn">✕</button>
+                    <button class="msg-action fork-btn" title="Rẽ nhánh từ tin nhắn này">⑂</button>
+                </span>
             </div>
             <div class="text markdown-body streaming"></div>
         </div>
     `;
 
+    messageEl.querySelector('.fork-btn').addEventListener('click', () => forkFromMessage(data.message_id));
+    messageEl.querySelector('.edit-btn').addEventListener('click', () => startEditMessage(messageEl));
+    messageEl.querySelector('.regenerate-btn').addEventListener('click', () => regenerateMessage(data.message_id));
+    messageEl.querySelector('.delete-btn').addEventListener('click', () => deleteMessage(data.message_id));
+    renderAudience(messageEl, data.audience);
+
     // Reset streaming content for new message
     currentStreamingContent = '';
 
@@ -609,11 +1372,319 @@ function finalizeMessage(messageEl) {
     textEl.classList.remove('streaming');
     // Final render of markdown
     textEl.innerHTML = renderMarkdown(currentStreamingContent);
+    textEl.dataset.raw = currentStreamingContent;
     currentStreamingContent = '';
     hasMessages = true;
     updateControls();
 }
 
+// Load and render the full transcript from the server (e.g. after resuming a saved debate)
+async function loadMessages() {
+    try {
+        const response = await fetch(`${debateApi}/messages`);
+        const messages = await response.json();
+        const summaries = await (await fetch(`${debateApi}/summaries`)).json();
+        messagesContainer.innerHTML = '';
+        hasMessages = false;
+        messages.forEach(renderStoredMessage);
+        summaries.forEach(renderStoredSummary);
+        updateControls();
+        forceScrollToBottom();
+        loadBranches();
+    } catch (error) {
+        console.error('Failed to load messages:', error);
+    }
+}
+
+// Re-render a stored message in place after an edit or regeneration
+function updateStoredMessage(msg) {
+    const messageEl = messagesContainer.querySelector(`.message[data-message-id="${CSS.escape(msg.id)}"]`);
+    if (!messageEl) return;
+
+    const color = msg.color || '#666';
+    messageEl.querySelector('.avatar').style.background = color;
+    messageEl.querySelector('.avatar').textContent = msg.agent_name.charAt(0);
+    messageEl.querySelector('.content').style.borderLeftColor = color;
+    const nameEl = messageEl.querySelector('.name');
+    nameEl.textContent = msg.agent_name;
+    if (msg.to) {
+        const target = agents.find(a => a.id === msg.to);
+        nameEl.textContent += ` → ${target ? target.name : msg.to}`;
+    }
+    nameEl.style.color = color;
+    renderAudience(messageEl, msg.audience);
+
+    const timeEl = messageEl.querySelector('.time');
+    timeEl.textContent = new Date(msg.timestamp).toLocaleTimeString('vi-VN');
+    if (msg.edits && msg.edits.length > 0) {
+        timeEl.textContent += ' (đã sửa)';
+        timeEl.title = `${msg.edits.length} phiên bản trước`;
+    }
+
+    const textEl = messageEl.querySelector('.text');
+    textEl.classList.remove('streaming');
+    textEl.innerHTML = renderMarkdown(msg.content);
+    textEl.dataset.raw = msg.content;
+    messageEl.querySelector('.sources')?.remove();
+    if (msg.citations && msg.citations.length > 0) {
+        renderSources(messageEl, msg.citations);
+    }
+    messageEl.querySelector('.meta')?.remove();
+    if (msg.meta) {
+        renderMeta(messageEl, msg.meta);
+    }
+}
+
+function startEditMessage(messageEl) {
+    const textEl = messageEl.querySelector('.text');
+    if (textEl.querySelector('textarea')) return;
+
+    const original = textEl.dataset.raw || textEl.innerText;
+    const renderedHtml = textEl.innerHTML;
+    textEl.innerHTML = `
+        <textarea class="edit-input" rows="6"></textarea>
+        <div class="edit-actions">
+            <button class="btn btn-primary btn-sm save-edit">Lưu</button>
+            <button class="btn btn-outline btn-sm cancel-edit">Hủy</button>
+        </div>
+    `;
+    const input = textEl.querySelector('textarea');
+    input.value = original;
+    input.focus();
+
+    textEl.querySelector('.cancel-edit').addEventListener('click', () => {
+        textEl.innerHTML = renderedHtml;
+    });
+    textEl.querySelector('.save-edit').addEventListener('click', async () => {
+        try {
+            const response = await fetch(`${debateApi}/messages/${encodeURIComponent(messageEl.dataset.messageId)}`, {
+                method: 'PATCH',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ content: input.value })
+            });
+            if (!response.ok) {
+                const data = await response.json();
+                alert(data.error || 'Không thể sửa tin nhắn');
+            }
+            // The message_updated event re-renders the message
+        } catch (error) {
+            console.error('Failed to edit message:', error);
+        }
+    });
+}
+
+async function regenerateMessage(messageId) {
+    const agentId = prompt('ID agent viết lại (để trống để giữ nguyên người viết):');
+    if (agentId === null) return;
+
+    try {
+        const response = await fetch(`${debateApi}/messages/${encodeURIComponent(messageId)}/regenerate`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ agent_id: agentId.trim() })
+        });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể tạo lại tin nhắn');
+        }
+    } catch (error) {
+        console.error('Failed to regenerate message:', error);
+    }
+}
+
+async function deleteMessage(messageId) {
+    if (!confirm('Xóa tin nhắn này khỏi cuộc thảo luận?')) return;
+
+    try {
+        const response = await fetch(`${debateApi}/messages/${encodeURIComponent(messageId)}`, { method: 'DELETE' });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể xóa tin nhắn');
+        }
+    } catch (error) {
+        console.error('Failed to delete message:', error);
+    }
+}
+
+async function loadBranches() {
+    try {
+        const branches = await (await fetch(`${debateApi}/branches`)).json();
+        branchSelect.innerHTML = branches.map(b =>
+            `<option value="${escapeHtml(b.id)}"${b.active ? ' selected' : ''}>${escapeHtml(b.name)} (${b.message_count})</option>`
+        ).join('');
+    } catch (error) {
+        console.error('Failed to load branches:', error);
+    }
+}
+
+async function forkFromMessage(messageId) {
+    const name = prompt('Tên nhánh mới (để trống để đặt tự động):');
+    if (name === null) return;
+
+    try {
+        const response = await fetch(`${debateApi}/branches`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ message_id: messageId, name: name.trim(), switch: true })
+        });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể rẽ nhánh');
+        }
+    } catch (error) {
+        console.error('Failed to fork:', error);
+    }
+}
+
+async function switchBranch(branchId) {
+    try {
+        const response = await fetch(`${debateApi}/branches/${encodeURIComponent(branchId)}/switch`, { method: 'POST' });
+        if (!response.ok) {
+            const data = await response.json();
+            alert(data.error || 'Không thể chuyển nhánh');
+            loadBranches();
+        }
+    } catch (error) {
+        console.error('Failed to switch branch:', error);
+    }
+}
+
+function renderStoredMessage(msg) {
+    if (msg.agent_id === 'system') {
+        addSystemMessage(escapeHtml(msg.content));
+        return;
+    }
+    const messageEl = createMessage({
+        agent_id: msg.agent_id,
+        agent_name: msg.agent_name,
+        message_id: msg.id
+    });
+    currentStreamingContent = msg.content;
+    finalizeMessage(messageEl);
+    updateStoredMessage(msg);
+}
+
+// Streams of the current parallel round, by message ID
+const parallelStreams = {};
+
+function handleParallelStream(data) {
+    const stream = parallelStreams[data.message_id];
+    switch (data.type) {
+        case 'start': {
+            const el = createMessage(data);
+            parallelStreams[data.message_id] = { el, content: '' };
+            break;
+        }
+        case 'chunk':
+            if (stream) {
+                stream.content += data.content;
+                stream.el.querySelector('.text').innerHTML = renderMarkdown(stream.content);
+                scrollToBottom();
+            }
+            break;
+        case 'end':
+        case 'error':
+            if (!stream) break;
+            if (data.type === 'error') {
+                addSystemMessage(`⚠️ ${escapeHtml(stream.el.querySelector('.name').textContent)}: ${escapeHtml(data.error)}`);
+            }
+            stream.el.querySelector('.text').classList.remove('streaming');
+            if (data.citations && data.citations.length > 0) {
+                renderSources(stream.el, data.citations);
+            }
+            if (data.meta) {
+                renderMeta(stream.el, data.meta);
+            }
+            delete parallelStreams[data.message_id];
+            hasMessages = true;
+            updateControls();
+            break;
+    }
+}
+
+// Summaries stream alongside turns, so they keep their own element and buffer
+const summaryMessages = {};
+
+function createSummaryMessage(data) {
+    const agent = agents.find(a => a.id === data.agent_id) || {
+        name: data.agent_name,
+        color: data.color || '#666'
+    };
+    const summaryLabels = { rolling: 'Tóm tắt định kỳ', agenda: 'Biên bản mục' };
+    const label = summaryLabels[data.kind] || 'Tóm tắt cuối';
+
+    const messageEl = document.createElement('div');
+    messageEl.className = 'message summary-message';
+    messageEl.dataset.messageId = data.message_id;
+    messageEl.innerHTML = `
+        <div class="avatar" style="background: ${agent.color}">
+            Σ
+        </div>
+        <div class="content" style="border-left-color: ${agent.color}">
+            <div class="header">
+                <span class="name" style="color: ${agent.color}">${label} · ${escapeHtml(agent.name)}</span>
+                <span class="time">${new Date().toLocaleTimeString('vi-VN')}</span>
+            </div>
+            <div class="text markdown-body streaming"></div>
+        </div>
+    `;
+    messagesContainer.appendChild(messageEl);
+    scrollToBottom();
+    return messageEl;
+}
+
+function renderStoredSummary(summary) {
+    const messageEl = createSummaryMessage({
+        agent_id: summary.agent_id,
+        agent_name: summary.agent_name,
+        message_id: summary.id,
+        kind: summary.kind
+    });
+    messageEl.querySelector('.time').textContent = new Date(summary.timestamp).toLocaleTimeString('vi-VN');
+    const textEl = messageEl.querySelector('.text');
+    textEl.classList.remove('streaming');
+    textEl.innerHTML = renderMarkdown(summary.content);
+}
+
+// Render numbered sources (e.g. Perplexity citations) under a message
+function renderSources(messageEl, citations) {
+    const sourcesEl = document.createElement('ol');
+    sourcesEl.className = 'sources';
+    citations.forEach(c => {
+        const li = document.createElement('li');
+        li.value = c.index;
+        const link = document.createElement('a');
+        link.href = c.url;
+        link.target = '_blank';
+        link.rel = 'noopener noreferrer';
+        link.textContent = c.title || c.url;
+        li.appendChild(link);
+        sourcesEl.appendChild(li);
+    });
+    messageEl.querySelector('.content').appendChild(sourcesEl);
+}
+
+// Render a message's provider, model, timing and token counts under it
+function renderMeta(messageEl, meta) {
+    const parts = [`${meta.provider} / ${meta.model}`];
+    if (meta.options && meta.options.temperature) {
+        parts.push(`temperature ${meta.options.temperature}`);
+    }
+    parts.push(`TTFT ${(meta.ttft_ms / 1000).toFixed(1)}s`, `${(meta.duration_ms / 1000).toFixed(1)}s`);
+    parts.push(`${meta.tokens_estimated ? '~' : ''}${meta.input_tokens} → ${meta.output_tokens} token`);
+    if (meta.finish_reason) parts.push(meta.finish_reason);
+
+    const metaEl = document.createElement('div');
+    metaEl.className = 'meta';
+    metaEl.textContent = parts.join(' · ');
+    if (meta.tokens_estimated) {
+        metaEl.title = 'Số token ước lượng: nhà cung cấp không báo cáo';
+    } else if (meta.attempts) {
+        metaEl.title = `${meta.attempts} lần thử`;
+    }
+    messageEl.querySelector('.content').appendChild(metaEl);
+}
+
 // Render markdown content safely
 function renderMarkdown(content) {
     if (!content) return '';
@@ -825,6 +1896,17 @@ function exportToMd() {
             mdLines.push('');
             mdLines.push(content);
             mdLines.push('');
+
+            const sourceLinks = message.querySelectorAll('.sources a');
+            if (sourceLinks.length > 0) {
+                mdLines.push('**Nguồn:**');
+                mdLines.push('');
+                sourceLinks.forEach(link => {
+                    mdLines.push(`${link.parentElement.value}. [${link.textContent}](${link.href})`);
+                });
+                mdLines.push('');
+            }
+
             mdLines.push('---');
             mdLines.push('');
         }
@@ -1013,6 +2095,7 @@ async function loadAgentConfigs() {
         const response = await fetch('/api/agents/configs');
         agentConfigs = await response.json();
         renderAgentManagerList();
+        renderJoinOptions();
     } catch (error) {
         console.error('Failed to load agent configs:', error);
     }
@@ -1039,6 +2122,11 @@ function setupAgentManager() {
         saveOrderBtn.addEventListener('click', saveAgentOrder);
     }
 
+    document.getElementById('castAgentsBtn').addEventListener('click', showCastView);
+    document.getElementById('castProposeBtn').addEventListener('click', proposeCast);
+    document.getElementById('castUseBtn').addEventListener('click', () => acceptCast(false));
+    document.getElementById('castSaveBtn').addEventListener('click', () => acceptCast(true));
+
     if (agentForm) {
         agentForm.addEventListener('submit', handleAgentFormSubmit);
     }
@@ -1084,10 +2172,95 @@ function closeAgentModal() {
 function showAgentList() {
     document.getElementById('agentListView').style.display = 'block';
     document.getElementById('agentEditView').style.display = 'none';
+    document.getElementById('agentCastView').style.display = 'none';
     document.getElementById('agentModalTitle').textContent = 'Quản lý Agents';
     editingAgentId = null;
 }
 
+// Show Auto-cast View
+function showCastView() {
+    document.getElementById('agentListView').style.display = 'none';
+    document.getElementById('agentCastView').style.display = 'block';
+    document.getElementById('agentModalTitle').textContent = 'Tự chọn đội hình';
+    document.getElementById('castTopic').value = topicInput.value.trim();
+    renderCast([]);
+}
+
+// Ask the server for a proposed panel
+async function proposeCast() {
+    const btn = document.getElementById('castProposeBtn');
+    btn.disabled = true;
+    btn.textContent = 'Đang đề xuất...';
+    try {
+        const response = await fetch(`${debateApi}/cast`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({
+                topic: document.getElementById('castTopic').value.trim(),
+                count: parseInt(document.getElementById('castCount').value) || 0
+            })
+        });
+        const data = await response.json();
+        if (!response.ok) {
+            alert('Lỗi: ' + (data.error || 'Không thể đề xuất đội hình'));
+            return;
+        }
+        renderCast(data.agents);
+    } catch (error) {
+        console.error('Failed to cast agents:', error);
+        alert('Lỗi kết nối server');
+    } finally {
+        btn.disabled = false;
+        btn.textContent = 'Đề xuất';
+    }
+}
+
+function renderCast(proposals) {
+    castProposals = proposals;
+    document.getElementById('castUseBtn').disabled = proposals.length === 0;
+    document.getElementById('castSaveBtn').disabled = proposals.length === 0;
+    document.getElementById('castList').innerHTML = proposals.map((p, i) => {
+        const swapped = p.suggested_provider
+            ? ` <span class="cast-note" title="Chưa có API key cho ${escapeHtml(p.suggested_provider)}">(thay cho ${escapeHtml(p.suggested_provider)})</span>`
+            : '';
+        return `
+        <div class="cast-item" style="border-left-color: ${escapeHtml(p.color)}">
+            <label><input type="checkbox" class="cast-include" data-index="${i}" checked>
+                <strong>${escapeHtml(p.name)}</strong> <code>${escapeHtml(p.id)}</code> · ${escapeHtml(p.provider)}${swapped}</label>
+            <p>${escapeHtml(p.role)}</p>
+            <details><summary>System prompt</summary><pre>${escapeHtml(p.system_prompt)}</pre></details>
+        </div>`;
+    }).join('');
+}
+
+// Use the checked proposals as a temporary panel, or save them to config.yaml
+async function acceptCast(save) {
+    const picked = [...document.querySelectorAll('.cast-include:checked')]
+        .map(el => castProposals[parseInt(el.dataset.index)]);
+    if (picked.length === 0) {
+        alert('Chọn ít nhất một agent');
+        return;
+    }
+    try {
+        const response = await fetch(`${debateApi}/cast/accept`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ agents: picked, save })
+        });
+        const data = await response.json();
+        if (!response.ok) {
+            alert('Lỗi: ' + (data.error || 'Không thể dùng đội hình'));
+            return;
+        }
+        selectedAgents.clear();
+        await loadAgents();
+        closeAgentModal();
+    } catch (error) {
+        console.error('Failed to accept cast:', error);
+        alert('Lỗi kết nối server');
+    }
+}
+
 // Show Agent Form (for add/edit)
 function showAgentForm(agentId) {
     document.getElementById('agentListView').style.display = 'none';
@@ -1107,6 +2280,7 @@ function showAgentForm(agentId) {
             document.getElementById('agentId').value = agent.id;
             document.getElementById('agentName').value = agent.name;
             document.getElementById('agentRole').value = agent.role || '';
+            document.getElementById('agentKind').value = agent.kind || '';
             document.getElementById('agentProvider').value = agent.provider || 'openai';
             document.getElementById('agentModel').value = agent.model || '';
             document.getElementById('agentTemperature').value = agent.temperature || 0.7;
@@ -1120,6 +2294,11 @@ function showAgentForm(agentId) {
             document.getElementById('agentBaseUrl').value = agent.base_url || '';
             document.getElementById('agentApiKey').value = agent.api_key || '';
             document.getElementById('agentSystemPrompt').value = agent.system_prompt || '';
+            const visibility = agent.visibility || {};
+            document.getElementById('agentVisibilityMode').value = visibility.mode || '';
+            document.getElementById('agentVisibilityLastN').value = visibility.last_n || 0;
+            document.getElementById('agentVisibilityFrom').value = (visibility.from || []).join(', ');
+            document.getElementById('agentVisibilityAnonymous').checked = !!visibility.anonymous;
         }
     } else {
         // Add mode
@@ -1132,6 +2311,7 @@ function showAgentForm(agentId) {
         document.getElementById('agentTopK').value = 0;
         document.getElementById('agentFrequencyPenalty').value = 0;
         document.getElementById('agentPresencePenalty').value = 0;
+        document.getElementById('agentVisibilityLastN').value = 0;
         document.getElementById('agentColor').value = '#4A90D9';
         document.getElementById('agentColorText').value = '#4A90D9';
     }
@@ -1290,6 +2470,7 @@ async function handleAgentFormSubmit(e) {
         id: document.getElementById('agentId').value.trim(),
         name: document.getElementById('agentName').value.trim(),
         role: document.getElementById('agentRole').value.trim(),
+        kind: document.getElementById('agentKind').value,
         provider: document.getElementById('agentProvider').value,
         model: document.getElementById('agentModel').value.trim(),
         temperature: parseFloat(document.getElementById('agentTemperature').value) || 0.7,
@@ -1301,7 +2482,13 @@ async function handleAgentFormSubmit(e) {
         color: document.getElementById('agentColor').value,
         base_url: document.getElementById('agentBaseUrl').value.trim(),
         api_key: document.getElementById('agentApiKey').value,
-        system_prompt: document.getElementById('agentSystemPrompt').value
+        system_prompt: document.getElementById('agentSystemPrompt').value,
+        visibility: {
+            mode: document.getElementById('agentVisibilityMode').value,
+            last_n: parseInt(document.getElementById('agentVisibilityLastN').value) || 0,
+            from: document.getElementById('agentVisibilityFrom').value.split(',').map(s => s.trim()).filter(Boolean),
+            anonymous: document.getElementById('agentVisibilityAnonymous').checked
+        }
     };
 
     if (!formData.id || !formData.name) {